@@ -18,9 +18,11 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -29,16 +31,19 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	nvidiacomv1alpha1 "github.com/ai-dynamo/dynamo/deploy/dynamo/operator/api/v1alpha1"
 	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/internal/controller"
 	commonController "github.com/ai-dynamo/dynamo/deploy/dynamo/operator/internal/controller_common"
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/system"
 	istioclientsetscheme "istio.io/client-go/pkg/clientset/versioned/scheme"
 	//+kubebuilder:scaffold:imports
 )
@@ -65,6 +70,8 @@ func main() {
 	var leaderElectionID string
 	var natsAddr string
 	var etcdAddr string
+	var ingressAnnotationAllowlist string
+	var ingressAnnotationAllowlistMode string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -80,9 +87,15 @@ func main() {
 		"Id to use for the leader election.")
 	flag.StringVar(&natsAddr, "natsAddr", "", "address of the NATS server")
 	flag.StringVar(&etcdAddr, "etcdAddr", "", "address of the etcd server")
+	flag.StringVar(&ingressAnnotationAllowlist, "ingress-annotation-allowlist", "",
+		"Comma-separated ingress annotation keys the network config and the DynamoNimDeployments may set, "+
+			"a key ending with * allows all the keys with that prefix. Empty allows all the annotations.")
+	flag.StringVar(&ingressAnnotationAllowlistMode, "ingress-annotation-allowlist-mode", string(system.AnnotationAllowlistModeStrip),
+		"What is done with the ingress annotations that aren't allowed: strip or reject.")
 	opts := zap.Options{
 		Development: true,
 	}
+	system.RegisterGoFlags(flag.CommandLine)
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
@@ -94,6 +107,20 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	var allowedAnnotations []string
+	for _, key := range strings.Split(ingressAnnotationAllowlist, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			allowedAnnotations = append(allowedAnnotations, key)
+		}
+	}
+	if err := system.SetAnnotationAllowlist(system.AnnotationAllowlist{
+		Keys: allowedAnnotations,
+		Mode: system.AnnotationAllowlistMode(ingressAnnotationAllowlistMode),
+	}); err != nil {
+		setupLog.Error(err, "invalid ingress annotation allowlist")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -179,6 +206,18 @@ func main() {
 	}
 	//+kubebuilder:scaffold:builder
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create kubernetes clientset")
+		os.Exit(1)
+	}
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		return system.RunProbeIngressGC(ctx, clientset)
+	})); err != nil {
+		setupLog.Error(err, "unable to set up the probe ingress garbage collection")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
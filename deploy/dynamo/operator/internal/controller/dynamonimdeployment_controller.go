@@ -19,8 +19,8 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"reflect"
 	"sort"
@@ -63,10 +63,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -103,6 +103,11 @@ type DynamoNimDeploymentReconciler struct {
 	Config   controller_common.Config
 	NatsAddr string
 	EtcdAddr string
+
+	// clientset is created once in SetupWithManager, for the lookups the
+	// controller-runtime client doesn't cache, such as the network configmap
+	// and the ingress classes.
+	clientset kubernetes.Interface
 }
 
 // +kubebuilder:rbac:groups=nvidia.com,resources=dynamonimdeployments,verbs=get;list;watch;create;update;patch;delete
@@ -110,7 +115,9 @@ type DynamoNimDeploymentReconciler struct {
 // +kubebuilder:rbac:groups=nvidia.com,resources=dynamonimdeployments/finalizers,verbs=update
 
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
@@ -119,6 +126,7 @@ type DynamoNimDeploymentReconciler struct {
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways;httproutes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.istio.io,resources=virtualservices,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;create;delete
 
@@ -153,6 +161,8 @@ func (r *DynamoNimDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	logs = logs.WithValues("dynamoNimDeployment", dynamoNimDeployment.Name, "namespace", dynamoNimDeployment.Namespace)
 
+	ctx = withIngressControllerTypes(ctx)
+
 	if len(dynamoNimDeployment.Status.Conditions) == 0 {
 		logs.Info("Starting to reconcile DynamoNimDeployment")
 		logs.Info("Initializing DynamoNimDeployment status")
@@ -609,19 +619,13 @@ var cachedYataiConf *commonconfig.YataiConfig
 
 //nolint:nakedret
 func (r *DynamoNimDeploymentReconciler) getYataiClient(ctx context.Context) (yataiClient **yataiclient.YataiClient, clusterName *string, err error) {
-	restConfig := config.GetConfigOrDie()
-	clientset, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		err = errors.Wrapf(err, "create kubernetes clientset")
-		return
-	}
 	var yataiConf *commonconfig.YataiConfig
 
 	if cachedYataiConf != nil {
 		yataiConf = cachedYataiConf
 	} else {
 		yataiConf, err = commonconfig.GetYataiConfig(ctx, func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
-			secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+			secret, err := r.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 			return secret, errors.Wrap(err, "get secret")
 		}, commonconsts.YataiDeploymentComponentName, false)
 		isNotFound := k8serrors.IsNotFound(err)
@@ -1051,6 +1055,13 @@ func (r *DynamoNimDeploymentReconciler) createOrUpdateService(ctx context.Contex
 		return
 	}
 
+	if opt.isGenericService && opt.dynamoNimDeployment.Spec.Ingress.Enabled {
+		err = r.setBackendProtocolServiceAnnotations(ctx, service)
+		if err != nil {
+			return
+		}
+	}
+
 	logs = logs.WithValues("namespace", service.Namespace, "serviceName", service.Name, "serviceSelector", service.Spec.Selector)
 
 	serviceNamespacedName := fmt.Sprintf("%s/%s", service.Namespace, service.Name)
@@ -2582,6 +2593,53 @@ func (r *DynamoNimDeploymentReconciler) generateService(opt generateServiceOptio
 	return
 }
 
+// setBackendProtocolServiceAnnotations adds the annotations of the ingress service
+// that the ingress controller reads the backend protocol from, for the
+// controllers that aren't configured through the ingress.
+func (r *DynamoNimDeploymentReconciler) setBackendProtocolServiceAnnotations(ctx context.Context, service *corev1.Service) error {
+	ingressConfig, err := r.GetIngressConfig(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "get ingress config")
+	}
+	if ingressConfig.BackendProtocol == "" {
+		return nil
+	}
+
+	controllerType := r.getIngressControllerType(ctx, ingressConfig.ClassName)
+	annotations := system.BackendProtocolServiceAnnotations(ingressConfig.BackendProtocol, controllerType, commonconsts.BentoServicePortName)
+	if len(annotations) == 0 {
+		return nil
+	}
+	if service.Annotations == nil {
+		service.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		service.Annotations[k] = v
+	}
+	return nil
+}
+
+type ingressControllerTypesKey struct{}
+
+// withIngressControllerTypes returns a context that memoizes the ingress controller
+// types getIngressControllerType resolves, so that the ingress classes are only
+// listed once per reconcile.
+func withIngressControllerTypes(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ingressControllerTypesKey{}, map[string]system.IngressControllerType{})
+}
+
+func (r *DynamoNimDeploymentReconciler) getIngressControllerType(ctx context.Context, className *string) system.IngressControllerType {
+	controllerTypes, _ := ctx.Value(ingressControllerTypesKey{}).(map[string]system.IngressControllerType)
+	if controllerType, ok := controllerTypes[ptr.Deref(className, "")]; ok {
+		return controllerType
+	}
+	controllerType := system.GetIngressControllerType(ctx, r.clientset, className)
+	if controllerTypes != nil {
+		controllerTypes[ptr.Deref(className, "")] = controllerType
+	}
+	return controllerType
+}
+
 func (r *DynamoNimDeploymentReconciler) generateIngressHost(ctx context.Context, dynamoNimDeployment *v1alpha1.DynamoNimDeployment) (string, error) {
 	return r.generateDefaultHostname(ctx, dynamoNimDeployment)
 }
@@ -2594,16 +2652,11 @@ func (r *DynamoNimDeploymentReconciler) generateDefaultHostname(ctx context.Cont
 	if cachedDomainSuffix != nil {
 		domainSuffix = *cachedDomainSuffix
 	} else {
-		restConfig := config.GetConfigOrDie()
-		clientset, err := kubernetes.NewForConfig(restConfig)
-		if err != nil {
-			return "", errors.Wrapf(err, "create kubernetes clientset")
-		}
-
+		var err error
 		domainSuffix, err = system.GetDomainSuffix(ctx, func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
-			configmap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+			configmap, err := r.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
 			return configmap, errors.Wrap(err, "get configmap")
-		}, clientset)
+		}, r.clientset)
 		if err != nil {
 			return "", errors.Wrapf(err, "get domain suffix")
 		}
@@ -2621,11 +2674,10 @@ const (
 	TLSModeStatic TLSModeOpt = "static"
 )
 
+// IngressConfig is the ingress config of the network configmap, see
+// system.ParseIngressConfig, with the TLS mode of the generated ingresses.
 type IngressConfig struct {
-	ClassName           *string
-	Annotations         map[string]string
-	Path                string
-	PathType            networkingv1.PathType
+	*system.IngressConfig
 	TLSMode             TLSModeOpt
 	StaticTLSSecretName string
 }
@@ -2639,15 +2691,8 @@ func (r *DynamoNimDeploymentReconciler) GetIngressConfig(ctx context.Context) (i
 		return
 	}
 
-	restConfig := config.GetConfigOrDie()
-	clientset, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		err = errors.Wrapf(err, "create kubernetes clientset")
-		return
-	}
-
 	configMap, err := system.GetNetworkConfigConfigMap(ctx, func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
-		configmap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		configmap, err := r.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
 		return configmap, errors.Wrap(err, "get network config configmap")
 	})
 	if err != nil {
@@ -2655,38 +2700,14 @@ func (r *DynamoNimDeploymentReconciler) GetIngressConfig(ctx context.Context) (i
 		return
 	}
 
-	var className *string
-
-	className_ := strings.TrimSpace(configMap.Data[commonconsts.KubeConfigMapKeyNetworkConfigIngressClass])
-	if className_ != "" {
-		className = &className_
-	}
-
-	annotations := make(map[string]string)
-
-	annotations_ := strings.TrimSpace(configMap.Data[commonconsts.KubeConfigMapKeyNetworkConfigIngressAnnotations])
-	if annotations_ != "" {
-		err = json.Unmarshal([]byte(annotations_), &annotations)
-		if err != nil {
-			err = errors.Wrapf(err, "failed to json unmarshal %s in configmap %s: %s", commonconsts.KubeConfigMapKeyNetworkConfigIngressAnnotations, commonconsts.KubeConfigMapNameNetworkConfig, annotations_)
-			return
-		}
-	}
-
-	path := strings.TrimSpace(configMap.Data["ingress-path"])
-	if path == "" {
-		path = "/"
-	}
-
-	pathType := networkingv1.PathTypeImplementationSpecific
-
-	pathType_ := strings.TrimSpace(configMap.Data["ingress-path-type"])
-	if pathType_ != "" {
-		pathType = networkingv1.PathType(pathType_)
+	baseConfig, err := system.ParseIngressConfig(configMap)
+	if err != nil {
+		return
 	}
+	baseConfig.LogStrippedAnnotations(log.FromContext(ctx), configMap)
 
 	tlsMode := TLSModeNone
-	tlsModeStr := strings.TrimSpace(configMap.Data["ingress-tls-mode"])
+	tlsModeStr := strings.TrimSpace(configMap.Data[commonconsts.KubeConfigMapKeyNetworkConfigIngressTLSMode])
 	if tlsModeStr != "" && tlsModeStr != "none" {
 		if tlsModeStr == "auto" || tlsModeStr == "static" {
 			tlsMode = TLSModeOpt(tlsModeStr)
@@ -2697,17 +2718,14 @@ func (r *DynamoNimDeploymentReconciler) GetIngressConfig(ctx context.Context) (i
 		}
 	}
 
-	staticTLSSecretName := strings.TrimSpace(configMap.Data["ingress-static-tls-secret-name"])
+	staticTLSSecretName := strings.TrimSpace(configMap.Data[commonconsts.KubeConfigMapKeyNetworkConfigIngressStaticTLSSecretName])
 	if tlsMode == TLSModeStatic && staticTLSSecretName == "" {
 		err = errors.Wrapf(err, "TLS mode is static but ingress-static-tls-secret isn't set")
 		return
 	}
 
 	ingressConfig = &IngressConfig{
-		ClassName:           className,
-		Annotations:         annotations,
-		Path:                path,
-		PathType:            pathType,
+		IngressConfig:       baseConfig,
 		TLSMode:             tlsMode,
 		StaticTLSSecretName: staticTLSSecretName,
 	}
@@ -2717,6 +2735,21 @@ func (r *DynamoNimDeploymentReconciler) GetIngressConfig(ctx context.Context) (i
 	return
 }
 
+// withControllerDefaults returns a copy of the ingress config with the defaults of
+// the ingress controller applied, see system.IngressConfig.ApplyControllerDefaults,
+// and that inherits its default annotations, see
+// system.ControllerDefaultAnnotations, leaving the cached ingress config as is.
+func (c *IngressConfig) withControllerDefaults(controllerType system.IngressControllerType) *IngressConfig {
+	baseConfig := *c.IngressConfig
+	baseConfig.Annotations = maps.Clone(c.Annotations)
+	baseConfig.ApplyControllerDefaults(controllerType)
+	baseConfig.InheritAnnotations(system.ControllerDefaultAnnotations(controllerType))
+
+	ingressConfig := *c
+	ingressConfig.IngressConfig = &baseConfig
+	return &ingressConfig
+}
+
 type generateIngressesOption struct {
 	yataiClient         **yataiclient.YataiClient
 	dynamoNimDeployment *v1alpha1.DynamoNimDeployment
@@ -2752,6 +2785,15 @@ more_set_headers "X-Yatai-Bento: %s";
 
 	annotations["nginx.ingress.kubernetes.io/ssl-redirect"] = "false"
 
+	// The annotations the operator sets itself aren't subject to the annotation
+	// allowlist, see system.FilterAllowedIngressAnnotations.
+	managedAnnotations := map[string]string{
+		commonconsts.KubeAnnotationBentoRepository:          dynamoNimRepositoryName,
+		commonconsts.KubeAnnotationBentoVersion:             dynamoNimVersion,
+		"nginx.ingress.kubernetes.io/configuration-snippet": annotations["nginx.ingress.kubernetes.io/configuration-snippet"],
+		"nginx.ingress.kubernetes.io/ssl-redirect":          "false",
+	}
+
 	labels := r.getKubeLabels(dynamoNimDeployment, dynamoNim)
 
 	kubeNs := dynamoNimDeployment.Namespace
@@ -2763,7 +2805,18 @@ more_set_headers "X-Yatai-Bento: %s";
 	}
 
 	ingressClassName := ingressConfig.ClassName
-	ingressAnnotations := ingressConfig.Annotations
+	controllerType := r.getIngressControllerType(ctx, ingressClassName)
+	ingressConfig = ingressConfig.withControllerDefaults(controllerType)
+	maps.Copy(managedAnnotations, system.ControllerDefaultAnnotations(controllerType))
+
+	ingressAnnotations, err := system.RenderAnnotations(ingressConfig.Annotations, system.AnnotationTemplateData{
+		Namespace:   kubeNs,
+		IngressName: kubeName,
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "render the ingress annotations")
+		return
+	}
 	ingressPath := ingressConfig.Path
 	ingressPathType := ingressConfig.PathType
 	ingressTLSMode := ingressConfig.TLSMode
@@ -2773,6 +2826,30 @@ more_set_headers "X-Yatai-Bento: %s";
 		annotations[k] = v
 	}
 
+	if ingressConfig.BackendProtocol != "" {
+		var backendProtocolAnnotations map[string]string
+		backendProtocolAnnotations, err = system.BackendProtocolIngressAnnotations(ingressConfig.BackendProtocol, controllerType)
+		if err != nil {
+			err = errors.Wrapf(err, "get the backend protocol annotations")
+			return
+		}
+		for k, v := range backendProtocolAnnotations {
+			annotations[k] = v
+		}
+	}
+
+	if ingressConfig.RewriteTarget != "" {
+		var rewriteTargetAnnotations map[string]string
+		rewriteTargetAnnotations, err = system.RewriteTargetIngressAnnotations(ingressConfig.RewriteTarget, ingressConfig.RewriteMiddleware, controllerType)
+		if err != nil {
+			err = errors.Wrapf(err, "get the rewrite target annotations")
+			return
+		}
+		for k, v := range rewriteTargetAnnotations {
+			annotations[k] = v
+		}
+	}
+
 	for k, v := range opt.dynamoNimDeployment.Spec.Ingress.Annotations {
 		annotations[k] = v
 	}
@@ -2804,13 +2881,47 @@ more_set_headers "X-Yatai-Bento: %s";
 		return
 	}
 
-	// override default tls if DynamoNimDeployment defines its own tls section
+	// the tls sections of the network configmap only cover their own hosts,
+	// so that the other hosts of the ingress are served over plain HTTP
+	if len(ingressConfig.TLS) > 0 {
+		tls = make([]networkingv1.IngressTLS, 0, len(ingressConfig.TLS))
+		for _, t := range ingressConfig.TLS {
+			tls = append(tls, networkingv1.IngressTLS{
+				Hosts:      t.Hosts,
+				SecretName: t.SecretName,
+			})
+		}
+	}
+
+	// override default tls if DynamoNimDeployment defines its own tls section,
+	// and otherwise let cert-manager provision it if an issuer is configured
 	if opt.dynamoNimDeployment.Spec.Ingress.TLS != nil && opt.dynamoNimDeployment.Spec.Ingress.TLS.SecretName != "" {
 		tls = make([]networkingv1.IngressTLS, 0, 1)
 		tls = append(tls, networkingv1.IngressTLS{
 			Hosts:      []string{internalHost},
 			SecretName: opt.dynamoNimDeployment.Spec.Ingress.TLS.SecretName,
 		})
+	} else if ingressConfig.CertManagerIssuer != "" {
+		certManagerAnnotations, certManagerTLS := system.WithCertManager(ingressConfig.CertManagerIssuer, []string{internalHost})
+		for k, v := range certManagerAnnotations {
+			annotations[k] = v
+		}
+		maps.Copy(managedAnnotations, certManagerAnnotations)
+		tls = []networkingv1.IngressTLS{{
+			Hosts:      certManagerTLS.Hosts,
+			SecretName: certManagerTLS.SecretName,
+		}}
+	}
+
+	// The tenants may set the annotations through the DynamoNimDeployment as
+	// well as through the network config, so the allowlist applies to the
+	// merged ones.
+	annotations, strippedAnnotations, err := system.FilterAllowedIngressAnnotations(kubeNs+"/"+kubeName, annotations, managedAnnotations)
+	if err != nil {
+		return
+	}
+	if len(strippedAnnotations) > 0 {
+		log.FromContext(ctx).Info("Ignoring the ingress annotations, which aren't allowed", "ingress", kubeNs+"/"+kubeName, "annotations", strippedAnnotations)
 	}
 
 	serviceName := r.getGenericServiceName(dynamoNimDeployment, dynamoNim)
@@ -2825,32 +2936,25 @@ more_set_headers "X-Yatai-Bento: %s";
 		Spec: networkingv1.IngressSpec{
 			IngressClassName: ingressClassName,
 			TLS:              tls,
-			Rules: []networkingv1.IngressRule{
-				{
-					Host: internalHost,
-					IngressRuleValue: networkingv1.IngressRuleValue{
-						HTTP: &networkingv1.HTTPIngressRuleValue{
-							Paths: []networkingv1.HTTPIngressPath{
-								{
-									Path:     ingressPath,
-									PathType: &ingressPathType,
-									Backend: networkingv1.IngressBackend{
-										Service: &networkingv1.IngressServiceBackend{
-											Name: serviceName,
-											Port: networkingv1.ServiceBackendPort{
-												Name: commonconsts.BentoServicePortName,
-											},
-										},
-									},
-								},
-							},
-						},
-					},
+			Rules: system.IngressRules(ingressConfig.Paths, ingressPath, ingressPathType, internalHost, networkingv1.IngressServiceBackend{
+				Name: serviceName,
+				Port: networkingv1.ServiceBackendPort{
+					Name: commonconsts.BentoServicePortName,
 				},
-			},
+			}),
 		},
 	}
 
+	err = system.ValidateIngressTLSHosts(interIng.Spec.TLS, interIng.Spec.Rules)
+	if err != nil {
+		err = errors.Wrapf(err, "validate ingress %s tls", interIng.Name)
+		return
+	}
+
+	if ingressConfig.DefaultBackend != nil {
+		interIng.Spec.DefaultBackend = ingressConfig.DefaultBackend.IngressBackend()
+	}
+
 	err = ctrl.SetControllerReference(dynamoNimDeployment, interIng, r.Scheme)
 	if err != nil {
 		err = errors.Wrapf(err, "set ingress %s controller reference", interIng.Name)
@@ -2875,14 +2979,9 @@ func (r *DynamoNimDeploymentReconciler) doCleanUpAbandonedRunnerServices() error
 	if cachedDynamoNimDeploymentNamespaces != nil {
 		dynamoNimDeploymentNamespaces = *cachedDynamoNimDeploymentNamespaces
 	} else {
-		restConfig := config.GetConfigOrDie()
-		clientset, err := kubernetes.NewForConfig(restConfig)
-		if err != nil {
-			return errors.Wrapf(err, "create kubernetes clientset")
-		}
-
+		var err error
 		dynamoNimDeploymentNamespaces, err = commonconfig.GetBentoDeploymentNamespaces(ctx, func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
-			secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+			secret, err := r.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 			return secret, errors.Wrap(err, "get secret")
 		})
 		if err != nil {
@@ -3012,9 +3111,30 @@ func (r *DynamoNimDeploymentReconciler) registerDynamoComponent() {
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// registerMetrics registers the domain suffix detection metrics with the
+// controller-runtime registry, which the manager serves on its metrics endpoint.
+func registerMetrics() error {
+	if _, err := system.RegisterMetrics(ctrlmetrics.Registry); err != nil {
+		return errors.Wrap(err, "register the domain suffix detection metrics")
+	}
+	return nil
+}
+
 func (r *DynamoNimDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	logs := log.Log.WithValues("func", "SetupWithManager")
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrapf(err, "create kubernetes clientset")
+	}
+	r.clientset = clientset
+
+	if err := registerMetrics(); err != nil {
+		return err
+	}
+	// The domain suffix discovery records its progress on the network configmap.
+	system.SetEventRecorder(mgr.GetEventRecorderFor("dynamo-domain-suffix"))
+
 	if os.Getenv("DISABLE_CLEANUP_ABANDONED_RUNNER_SERVICES") != commonconsts.KubeLabelValueTrue {
 		go r.cleanUpAbandonedRunnerServices()
 	} else {
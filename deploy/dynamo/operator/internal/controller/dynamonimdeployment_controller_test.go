@@ -0,0 +1,304 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/api/v1alpha1"
+	commonconsts "github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/system"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// newIngressTestReconciler returns a reconciler whose clientset serves the network
+// configmap with the data, and the objects. The cached ingress config and domain
+// suffix are reset for the test.
+func newIngressTestReconciler(t *testing.T, networkConfig map[string]string, objects ...k8sruntime.Object) *DynamoNimDeploymentReconciler {
+	t.Helper()
+
+	cachedIngressConfig = nil
+	cachedDomainSuffix = ptr.To("10.0.0.1.sslip.io")
+	t.Cleanup(func() {
+		cachedIngressConfig = nil
+		cachedDomainSuffix = nil
+	})
+
+	scheme := k8sruntime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the API to the scheme: %v", err)
+	}
+
+	objects = append(objects, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      commonconsts.KubeConfigMapNameNetworkConfig,
+			Namespace: system.GetNamespace(),
+		},
+		Data: networkConfig,
+	})
+
+	return &DynamoNimDeploymentReconciler{
+		Scheme:    scheme,
+		Recorder:  record.NewFakeRecorder(100),
+		clientset: fake.NewSimpleClientset(objects...),
+	}
+}
+
+// generateTestIngress generates the ingress of a DynamoNimDeployment with the
+// ingress spec.
+func generateTestIngress(t *testing.T, r *DynamoNimDeploymentReconciler, ingress v1alpha1.IngressSpec) *networkingv1.Ingress {
+	t.Helper()
+
+	ingresses, err := r.generateIngresses(withIngressControllerTypes(context.Background()), generateIngressesOption{
+		dynamoNimDeployment: &v1alpha1.DynamoNimDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid"},
+			Spec: v1alpha1.DynamoNimDeploymentSpec{
+				Ingress: ingress,
+			},
+		},
+		dynamoNim: &v1alpha1.DynamoNim{
+			Spec: v1alpha1.DynamoNimSpec{Tag: "app:v1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("generateIngresses() error = %v", err)
+	}
+	if len(ingresses) != 1 {
+		t.Fatalf("generateIngresses() = %d ingresses, want 1", len(ingresses))
+	}
+	return ingresses[0]
+}
+
+func TestGenerateIngressesCertManager(t *testing.T) {
+	r := newIngressTestReconciler(t, map[string]string{
+		commonconsts.KubeConfigMapKeyNetworkConfigCertManagerIssuer: "letsencrypt",
+	})
+
+	ingress := generateTestIngress(t, r, v1alpha1.IngressSpec{Enabled: true})
+
+	if got := ingress.Annotations["cert-manager.io/cluster-issuer"]; got != "letsencrypt" {
+		t.Errorf("the cluster issuer annotation = %q, want letsencrypt", got)
+	}
+	if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName == "" {
+		t.Errorf("the ingress TLS = %+v, want the cert-manager secret", ingress.Spec.TLS)
+	}
+}
+
+func TestGenerateIngressesControllerDefaultAnnotations(t *testing.T) {
+	nginx := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+	r := newIngressTestReconciler(t, map[string]string{
+		commonconsts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+	}, nginx)
+
+	ingress := generateTestIngress(t, r, v1alpha1.IngressSpec{
+		Enabled: true,
+		Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-read-timeout": "30",
+		},
+	})
+
+	if got := ingress.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"]; got != "0" {
+		t.Errorf("the proxy-body-size annotation = %q, want the nginx default 0", got)
+	}
+	if got := ingress.Annotations["nginx.ingress.kubernetes.io/proxy-read-timeout"]; got != "30" {
+		t.Errorf("the proxy-read-timeout annotation = %q, want the one of the DynamoNimDeployment", got)
+	}
+	if _, ok := cachedIngressConfig.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"]; ok {
+		t.Errorf("the cached ingress config got the controller defaults, want them only on the generated ingress")
+	}
+}
+
+func TestGenerateIngressesControllerDefaultPathType(t *testing.T) {
+	nginx := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+
+	tests := []struct {
+		name          string
+		networkConfig map[string]string
+		want          networkingv1.PathType
+	}{
+		{
+			name: "defaulted",
+			networkConfig: map[string]string{
+				commonconsts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+			},
+			want: networkingv1.PathTypePrefix,
+		},
+		{
+			name: "explicit",
+			networkConfig: map[string]string{
+				commonconsts.KubeConfigMapKeyNetworkConfigIngressClass:    "nginx",
+				commonconsts.KubeConfigMapKeyNetworkConfigIngressPathType: string(networkingv1.PathTypeExact),
+			},
+			want: networkingv1.PathTypeExact,
+		},
+		{
+			name:          "unknown controller",
+			networkConfig: map[string]string{},
+			want:          networkingv1.PathTypeImplementationSpecific,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newIngressTestReconciler(t, tt.networkConfig, nginx)
+
+			ingress := generateTestIngress(t, r, v1alpha1.IngressSpec{Enabled: true})
+
+			paths := ingress.Spec.Rules[0].HTTP.Paths
+			if len(paths) != 1 || paths[0].PathType == nil || *paths[0].PathType != tt.want {
+				t.Errorf("the ingress paths = %+v, want the path type %s", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateIngressesAnnotationAllowlist(t *testing.T) {
+	spec := v1alpha1.IngressSpec{
+		Enabled: true,
+		Annotations: map[string]string{
+			"cert-manager.io/cluster-issuer":             "letsencrypt",
+			"nginx.ingress.kubernetes.io/server-snippet": "return 200;",
+		},
+	}
+
+	t.Run("strip", func(t *testing.T) {
+		if err := system.SetAnnotationAllowlist(system.AnnotationAllowlist{Keys: []string{"cert-manager.io/*"}}); err != nil {
+			t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+		}
+		defer func() { _ = system.SetAnnotationAllowlist(system.AnnotationAllowlist{}) }()
+		r := newIngressTestReconciler(t, map[string]string{})
+
+		ingress := generateTestIngress(t, r, spec)
+
+		if _, ok := ingress.Annotations["nginx.ingress.kubernetes.io/server-snippet"]; ok {
+			t.Errorf("the ingress annotations = %v, want the server snippet of the DynamoNimDeployment stripped", ingress.Annotations)
+		}
+		if ingress.Annotations["cert-manager.io/cluster-issuer"] != "letsencrypt" {
+			t.Errorf("the ingress annotations = %v, want the allowed annotation of the DynamoNimDeployment kept", ingress.Annotations)
+		}
+		if ingress.Annotations["nginx.ingress.kubernetes.io/ssl-redirect"] != "false" || ingress.Annotations["nginx.ingress.kubernetes.io/configuration-snippet"] == "" {
+			t.Errorf("the ingress annotations = %v, want the annotations of the operator kept", ingress.Annotations)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		if err := system.SetAnnotationAllowlist(system.AnnotationAllowlist{Keys: []string{"cert-manager.io/*"}, Mode: system.AnnotationAllowlistModeReject}); err != nil {
+			t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+		}
+		defer func() { _ = system.SetAnnotationAllowlist(system.AnnotationAllowlist{}) }()
+		r := newIngressTestReconciler(t, map[string]string{})
+
+		_, err := r.generateIngresses(withIngressControllerTypes(context.Background()), generateIngressesOption{
+			dynamoNimDeployment: &v1alpha1.DynamoNimDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec:       v1alpha1.DynamoNimDeploymentSpec{Ingress: spec},
+			},
+			dynamoNim: &v1alpha1.DynamoNim{Spec: v1alpha1.DynamoNimSpec{Tag: "app:v1"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "nginx.ingress.kubernetes.io/server-snippet") {
+			t.Errorf("generateIngresses() error = %v, want one about the server snippet", err)
+		}
+	})
+}
+
+func TestGenerateDefaultHostnameRecordsEvents(t *testing.T) {
+	r := newIngressTestReconciler(t, map[string]string{
+		commonconsts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		commonconsts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		commonconsts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	}, &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	})
+	// The probe ingresses get a load balancer address on creation.
+	r.clientset.(*fake.Clientset).PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress)
+		if ing.Name == "" {
+			ing.Name = ing.GenerateName + "test"
+		}
+		ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}
+		return false, nil, nil
+	})
+	cachedDomainSuffix = nil
+	recorder := record.NewFakeRecorder(10)
+	system.SetEventRecorder(recorder)
+	defer system.SetEventRecorder(nil)
+
+	hostname, err := r.generateDefaultHostname(context.Background(), &v1alpha1.DynamoNimDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("generateDefaultHostname() error = %v", err)
+	}
+	if want := "app-default.10.0.0.1.sslip.io"; hostname != want {
+		t.Errorf("generateDefaultHostname() = %q, want %q", hostname, want)
+	}
+
+	want := "Normal " + system.EventReasonDomainSuffixDetected + " "
+	for len(recorder.Events) > 0 {
+		if strings.HasPrefix(<-recorder.Events, want) {
+			return
+		}
+	}
+	t.Errorf("no %s event was recorded", system.EventReasonDomainSuffixDetected)
+}
+
+func TestRegisterMetrics(t *testing.T) {
+	// The controller setup registers the metrics every time it runs.
+	for i := 0; i < 2; i++ {
+		if err := registerMetrics(); err != nil {
+			t.Fatalf("registerMetrics() error = %v", err)
+		}
+	}
+
+	metrics, err := system.RegisterMetrics(ctrlmetrics.Registry)
+	if err != nil {
+		t.Fatalf("RegisterMetrics() error = %v", err)
+	}
+	metrics.DetectionFailures.WithLabelValues("timeout")
+
+	families, err := ctrlmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	registered := map[string]bool{}
+	for _, family := range families {
+		registered[family.GetName()] = true
+	}
+	for _, name := range []string{"dynamo_domain_suffix_detection_seconds", "dynamo_domain_suffix_detection_failures_total"} {
+		if !registered[name] {
+			t.Errorf("%s isn't served by the manager registry", name)
+		}
+	}
+}
@@ -68,9 +68,14 @@ const (
 	KubeLabelYataiImageBuilderPod = "yatai.ai/yatai-image-builder-pod"
 	KubeLabelBentoDeploymentPod   = "yatai.ai/bento-deployment-pod"
 
-	KubeLabelManagedBy    = "app.kubernetes.io/managed-by"
-	KubeLabelHelmHeritage = "heritage"
-	KubeLabelHelmRelease  = "release"
+	KubeLabelDynamoPurpose    = "dynamo.nvidia.com/purpose"
+	KubeLabelDynamoComponent  = "dynamo.nvidia.com/component"
+	KubeLabelValueDomainProbe = "domain-probe"
+
+	KubeLabelManagedBy           = "app.kubernetes.io/managed-by"
+	KubeLabelValueDynamoOperator = "dynamo-operator"
+	KubeLabelHelmHeritage        = "heritage"
+	KubeLabelHelmRelease         = "release"
 
 	KubeAnnotationBentoRepository        = "yatai.ai/bento-repository"
 	KubeAnnotationBentoVersion           = "yatai.ai/bento-version"
@@ -89,6 +94,12 @@ const (
 	KubeAnnotationGCPAccessKeySecretName          = "yatai.ai/gcp-access-key-secret"
 	KubeAnnotationIsMultiTenancy                  = "yatai.ai/is-multi-tenancy"
 
+	KubeAnnotationDynamoProbeIngressTTL        = "dynamo.nvidia.com/probe-ingress-ttl"
+	KubeAnnotationDynamoDiscoveryCorrelationID = "dynamo.nvidia.com/discovery-correlation-id"
+	KubeAnnotationDynamoConfigPriority         = "dynamo.nvidia.com/config-priority"
+	KubeAnnotationDynamoPersistentProbeIngress = "dynamo.nvidia.com/persistent-probe-ingress"
+	KubeAnnotationIngressClass                 = "kubernetes.io/ingress.class"
+
 	KubeCreator = "yatai"
 
 	KubeResourceGPUNvidia = "nvidia.com/gpu"
@@ -105,11 +116,80 @@ const (
 
 	KubeConfigMapNameNetworkConfig = "network"
 
-	KubeConfigMapKeyNetworkConfigDomainSuffix       = "domain-suffix"
-	KubeConfigMapKeyNetworkConfigIngressClass       = "ingress-class"
-	KubeConfigMapKeyNetworkConfigIngressAnnotations = "ingress-annotations"
-	KubeConfigMapKeyNetworkConfigIngressPath        = "ingress-path"
-	KubeConfigMapKeyNetworkConfigIngressPathType    = "ingress-path-type"
+	KubeConfigMapKeyNetworkConfigDomainSuffix                     = "domain-suffix"
+	KubeConfigMapKeyNetworkConfigExternalIP                       = "external-ip"
+	KubeConfigMapKeyNetworkConfigIngressClass                     = "ingress-class"
+	KubeConfigMapKeyNetworkConfigIngressAnnotations               = "ingress-annotations"
+	KubeConfigMapKeyNetworkConfigIngressAnnotationsBase           = "ingress-annotations-base"
+	KubeConfigMapKeyNetworkConfigIngressRemoveAnnotations         = "ingress-remove-annotations"
+	KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation          = "ingress.annotation."
+	KubeConfigMapKeyNetworkConfigIngressPath                      = "ingress-path"
+	KubeConfigMapKeyNetworkConfigIngressPathType                  = "ingress-path-type"
+	KubeConfigMapKeyNetworkConfigIngressPaths                     = "ingress-paths"
+	KubeConfigMapKeyNetworkConfigIngressDefaultBackend            = "ingress-default-backend"
+	KubeConfigMapKeyNetworkConfigIngressTLS                       = "ingress-tls"
+	KubeConfigMapKeyNetworkConfigIngressTLSMode                   = "ingress-tls-mode"
+	KubeConfigMapKeyNetworkConfigIngressStaticTLSSecretName       = "ingress-static-tls-secret-name"
+	KubeConfigMapKeyNetworkConfigIngressBackendProtocol           = "ingress-backend-protocol"
+	KubeConfigMapKeyNetworkConfigCertManagerIssuer                = "cert-manager-issuer"
+	KubeConfigMapKeyNetworkConfigIngressRewriteTarget             = "ingress-rewrite-target"
+	KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware         = "ingress-rewrite-middleware"
+	KubeConfigMapKeyNetworkConfigMagicDNSTemplate                 = "magic-dns-template"
+	KubeConfigMapKeyNetworkConfigMagicDNSIPFormat                 = "magic-dns-ip-format"
+	KubeConfigMapKeyNetworkConfigNetworkMode                      = "network-mode"
+	KubeConfigMapKeyNetworkConfigGatewayClass                     = "gateway-class"
+	KubeConfigMapKeyNetworkConfigLBScheme                         = "lb-scheme"
+	KubeConfigMapKeyNetworkConfigLBSubnets                        = "lb-subnets"
+	KubeConfigMapKeyNetworkConfigIngressControllerService         = "ingress-controller-service"
+	KubeConfigMapKeyNetworkConfigIngressControllerServiceSelector = "ingress-controller-service-selector"
+	KubeConfigMapKeyNetworkConfigDiscoveryPreflight               = "discovery-preflight"
+	KubeConfigMapKeyNetworkConfigDiscoveryIngressClassCheck       = "discovery-ingress-class-check"
+	KubeConfigMapKeyNetworkConfigDiscoveryIngressClassMode        = "discovery-ingress-class-mode"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeCatchAll           = "discovery-probe-catch-all"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSuffix         = "discovery-probe-host-suffix"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSeed           = "discovery-probe-host-seed"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendService     = "discovery-probe-backend-service"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort        = "discovery-probe-backend-port"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName    = "discovery-probe-backend-port-name"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortLookup  = "discovery-probe-backend-port-lookup"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace          = "discovery-probe-namespace"
+	KubeConfigMapKeyNetworkConfigDiscoveryPostHookFatal           = "discovery-post-hook-fatal"
+	KubeConfigMapKeyNetworkConfigDiscoveryReadyCondition          = "discovery-ready-condition"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeTTL                = "discovery-probe-ttl"
+	KubeConfigMapKeyNetworkConfigDiscoveryStatusConfigMap         = "discovery-status-configmap"
+	KubeConfigMapKeyNetworkConfigDiscoveryPinnedAddress           = "discovery-pinned-address"
+	KubeConfigMapKeyNetworkConfigDiscoveryPollInterval            = "discovery-poll-interval"
+	KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout             = "discovery-wait-timeout"
+	KubeConfigMapKeyNetworkConfigDiscoveryProvisioningBudget      = "discovery-provisioning-budget"
+	KubeConfigMapKeyNetworkConfigDiscoveryMinBudget               = "discovery-min-budget"
+	KubeConfigMapKeyNetworkConfigDiscoveryServiceStatusFallback   = "discovery-service-status-fallback"
+	KubeConfigMapKeyNetworkConfigDiscoveryNodeAddressFallback     = "discovery-node-address-fallback"
+	KubeConfigMapKeyNetworkConfigDiscoveryCreateRetries           = "discovery-create-retries"
+	KubeConfigMapKeyNetworkConfigDiscoveryDialPort                = "discovery-dial-port"
+	KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthCheck         = "discovery-grpc-health-check"
+	KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthService       = "discovery-grpc-health-service"
+	KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthTimeout       = "discovery-grpc-health-timeout"
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressChangePolicy     = "discovery-address-change-policy"
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference       = "discovery-address-preference"
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressAnnotation       = "discovery-address-annotation"
+	KubeConfigMapKeyNetworkConfigDiscoveryHostnameFallback        = "discovery-hostname-fallback"
+	KubeConfigMapKeyNetworkConfigDiscoveryPreferHostname          = "discovery-prefer-hostname"
+	KubeConfigMapKeyNetworkConfigDiscoveryResolveTimeout          = "discovery-resolve-timeout"
+	KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe         = "discovery-persistent-probe"
+	KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe              = "discovery-reuse-probe"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress            = "discovery-probe-ingress"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts              = "discovery-probe-hosts"
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses     = "discovery-probe-ingress-classes"
+	KubeConfigMapKeyNetworkConfigDiscoveryExternalNameService     = "discovery-external-name-service"
+	KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately         = "discovery-poll-immediately"
+	KubeConfigMapKeyNetworkConfigDiscoveryWatch                   = "discovery-watch"
+	KubeConfigMapKeyNetworkConfigDiscoveryReverseLookup           = "discovery-reverse-lookup"
+	KubeConfigMapKeyNetworkConfigDiscoveryVerifyDomainSuffix      = "discovery-verify-domain-suffix"
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily           = "discovery-address-family"
+	KubeConfigMapKeyNetworkConfigDiscoveryPreferredAddressFamily  = "discovery-preferred-address-family"
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressCIDRs            = "discovery-address-cidrs"
+	KubeConfigMapKeyNetworkConfigDiscoveryControllerCheck         = "discovery-controller-check"
+	KubeConfigMapKeyNetworkConfigDiscoveryControllerSelector      = "discovery-controller-selector"
 
 	KubeConfigMapNameYataiConfig = "yatai"
 
@@ -134,3 +214,81 @@ var KubeListEverything = metav1.ListOptions{
 	LabelSelector: labels.Everything().String(),
 	FieldSelector: fields.Everything().String(),
 }
+
+// KubeConfigMapKeysNetworkConfig are the keys of the network configmap, other
+// than the KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation ones.
+var KubeConfigMapKeysNetworkConfig = []string{
+	KubeConfigMapKeyNetworkConfigDomainSuffix,
+	KubeConfigMapKeyNetworkConfigExternalIP,
+	KubeConfigMapKeyNetworkConfigIngressClass,
+	KubeConfigMapKeyNetworkConfigIngressAnnotations,
+	KubeConfigMapKeyNetworkConfigIngressAnnotationsBase,
+	KubeConfigMapKeyNetworkConfigIngressRemoveAnnotations,
+	KubeConfigMapKeyNetworkConfigIngressPath,
+	KubeConfigMapKeyNetworkConfigIngressPathType,
+	KubeConfigMapKeyNetworkConfigIngressPaths,
+	KubeConfigMapKeyNetworkConfigIngressDefaultBackend,
+	KubeConfigMapKeyNetworkConfigIngressTLS,
+	KubeConfigMapKeyNetworkConfigIngressTLSMode,
+	KubeConfigMapKeyNetworkConfigIngressStaticTLSSecretName,
+	KubeConfigMapKeyNetworkConfigIngressBackendProtocol,
+	KubeConfigMapKeyNetworkConfigCertManagerIssuer,
+	KubeConfigMapKeyNetworkConfigIngressRewriteTarget,
+	KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware,
+	KubeConfigMapKeyNetworkConfigMagicDNSTemplate,
+	KubeConfigMapKeyNetworkConfigMagicDNSIPFormat,
+	KubeConfigMapKeyNetworkConfigNetworkMode,
+	KubeConfigMapKeyNetworkConfigGatewayClass,
+	KubeConfigMapKeyNetworkConfigLBScheme,
+	KubeConfigMapKeyNetworkConfigLBSubnets,
+	KubeConfigMapKeyNetworkConfigIngressControllerService,
+	KubeConfigMapKeyNetworkConfigIngressControllerServiceSelector,
+	KubeConfigMapKeyNetworkConfigDiscoveryPreflight,
+	KubeConfigMapKeyNetworkConfigDiscoveryIngressClassCheck,
+	KubeConfigMapKeyNetworkConfigDiscoveryIngressClassMode,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeCatchAll,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSuffix,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSeed,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendService,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortLookup,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace,
+	KubeConfigMapKeyNetworkConfigDiscoveryPostHookFatal,
+	KubeConfigMapKeyNetworkConfigDiscoveryReadyCondition,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeTTL,
+	KubeConfigMapKeyNetworkConfigDiscoveryStatusConfigMap,
+	KubeConfigMapKeyNetworkConfigDiscoveryPinnedAddress,
+	KubeConfigMapKeyNetworkConfigDiscoveryPollInterval,
+	KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout,
+	KubeConfigMapKeyNetworkConfigDiscoveryProvisioningBudget,
+	KubeConfigMapKeyNetworkConfigDiscoveryMinBudget,
+	KubeConfigMapKeyNetworkConfigDiscoveryServiceStatusFallback,
+	KubeConfigMapKeyNetworkConfigDiscoveryNodeAddressFallback,
+	KubeConfigMapKeyNetworkConfigDiscoveryCreateRetries,
+	KubeConfigMapKeyNetworkConfigDiscoveryDialPort,
+	KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthCheck,
+	KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthService,
+	KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthTimeout,
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressChangePolicy,
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference,
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressAnnotation,
+	KubeConfigMapKeyNetworkConfigDiscoveryHostnameFallback,
+	KubeConfigMapKeyNetworkConfigDiscoveryPreferHostname,
+	KubeConfigMapKeyNetworkConfigDiscoveryResolveTimeout,
+	KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe,
+	KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts,
+	KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses,
+	KubeConfigMapKeyNetworkConfigDiscoveryExternalNameService,
+	KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately,
+	KubeConfigMapKeyNetworkConfigDiscoveryWatch,
+	KubeConfigMapKeyNetworkConfigDiscoveryReverseLookup,
+	KubeConfigMapKeyNetworkConfigDiscoveryVerifyDomainSuffix,
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily,
+	KubeConfigMapKeyNetworkConfigDiscoveryPreferredAddressFamily,
+	KubeConfigMapKeyNetworkConfigDiscoveryAddressCIDRs,
+	KubeConfigMapKeyNetworkConfigDiscoveryControllerCheck,
+	KubeConfigMapKeyNetworkConfigDiscoveryControllerSelector,
+}
@@ -0,0 +1,371 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// defaultResolveTimeout bounds the resolution of a load balancer hostname, unless
+// set in the network config.
+const defaultResolveTimeout = 10 * time.Second
+
+// ErrIngressNoAddress is returned when the load balancer status of the probe has
+// no IP or hostname to derive the domain suffix from.
+var ErrIngressNoAddress = errors.New("the load balancer status has no IP or hostname")
+
+// ErrDNSResolution matches, with errors.Is, the *ResolveError of a hostname that
+// can't be resolved.
+var ErrDNSResolution = errors.New("failed to resolve the hostname")
+
+// ResolveError is returned when a load balancer hostname can't be resolved to an
+// IP address. IsTemporary and IsNotFound are those of the *net.DNSError it wraps,
+// if any, so that callers can retry a temporary failure but not an NXDOMAIN.
+type ResolveError struct {
+	Hostname    string
+	IsTemporary bool
+	IsNotFound  bool
+	Err         error
+}
+
+func newResolveError(hostname string, err error) *ResolveError {
+	resolveErr := &ResolveError{Hostname: hostname, Err: err}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		resolveErr.IsTemporary = dnsErr.IsTemporary || dnsErr.IsTimeout
+		resolveErr.IsNotFound = dnsErr.IsNotFound
+	}
+	return resolveErr
+}
+
+func (e *ResolveError) Error() string {
+	return "failed to resolve ip address for hostname " + e.Hostname + ": " + e.Err.Error()
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ResolveError) Is(target error) bool {
+	return target == ErrDNSResolution
+}
+
+// validateAddress checks that the address is a legal IP or DNS hostname.
+func validateAddress(address string) error {
+	if net.ParseIP(address) != nil {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(address); len(errs) > 0 {
+		return errors.Errorf("%q is neither an IP nor a valid hostname: %s", address, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// selectLoadBalancerAddress picks the load balancer ingress entry to derive the
+// address from: the pinned one if configured, and otherwise the first one.
+func selectLoadBalancerAddress(entries []networkingv1.IngressLoadBalancerIngress, owner string, config *discoveryConfig) (address networkingv1.IngressLoadBalancerIngress, err error) {
+	if len(entries) == 0 {
+		err = errors.Wrapf(ErrIngressNoAddress, "%s", owner)
+		return
+	}
+
+	if config.PinnedAddress == "" {
+		address = entries[0]
+		return
+	}
+
+	pinned := config.PinnedAddress
+	if parsed := net.ParseIP(pinned); parsed != nil {
+		pinned = parsed.String()
+	}
+	for _, entry := range entries {
+		if entry.IP == pinned || (entry.IP != "" && net.ParseIP(entry.IP).String() == pinned) || strings.EqualFold(entry.Hostname, pinned) {
+			address = entry
+			return
+		}
+	}
+
+	err = errors.Errorf("the pinned address %s is not in the load balancer status of %s: %s", config.PinnedAddress, owner, formatLoadBalancerIngress(entries))
+	return
+}
+
+// Resolver resolves hostnames to IP addresses. *net.Resolver implements it.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+var (
+	resolverMu sync.RWMutex
+	resolver   Resolver = net.DefaultResolver
+)
+
+// SetResolver replaces the resolver used to resolve the load balancer hostnames,
+// e.g. to use a custom DNS server. A nil resolver restores net.DefaultResolver.
+func SetResolver(r Resolver) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	resolver = r
+}
+
+func getResolver() Resolver {
+	resolverMu.RLock()
+	defer resolverMu.RUnlock()
+	return resolver
+}
+
+// AddressPreference is which field of a load balancer ingress entry the IP is
+// derived from when the entry has both an IP and a hostname.
+//
+// The hostname keys of the network config apply in this order: with
+// discovery-prefer-hostname, the hostname of an entry is used unresolved and
+// the preference is ignored, which is why the two can only be combined with
+// AddressPreferenceHostname. Otherwise the preference picks the field, and
+// discovery-hostname-fallback only applies when a preferred hostname fails to
+// resolve.
+type AddressPreference string
+
+const (
+	// AddressPreferenceIP uses the IP, and resolves the hostname only when there
+	// is no IP.
+	AddressPreferenceIP AddressPreference = "ip"
+	// AddressPreferenceHostname resolves the hostname, and uses the IP only when
+	// there is no hostname, or when its resolution fails and the fallback is
+	// allowed.
+	AddressPreferenceHostname AddressPreference = "hostname"
+	// AddressPreferenceAuto uses the IP if it is publicly routable, and otherwise
+	// resolves the hostname as AddressPreferenceHostname does, e.g. for the load
+	// balancers reporting a private IP together with their public hostname.
+	AddressPreferenceAuto AddressPreference = "auto"
+)
+
+// prefersHostname reports whether the hostname of a load balancer ingress entry
+// is preferred to its IP ip.
+func prefersHostname(ip string, preference AddressPreference) bool {
+	switch preference {
+	case AddressPreferenceHostname:
+		return true
+	case AddressPreferenceAuto:
+		parsed := net.ParseIP(ip)
+		return parsed == nil || !parsed.IsGlobalUnicast() || parsed.IsPrivate()
+	}
+	return false
+}
+
+// IngressAddress is an address of the ingress load balancer.
+type IngressAddress struct {
+	// IP is the IP of the load balancer, reported by its status or resolved from
+	// Hostname. It is empty if the hostname is preferred, see Host.
+	IP string
+	// Hostname is the hostname reported by the load balancer status, if any, e.g.
+	// to point a CNAME record at rather than the IP.
+	Hostname string
+	// Resolved reports whether IP was resolved from Hostname rather than reported
+	// by the status.
+	Resolved bool
+	// Ports are the ports reported by the load balancer status, if any.
+	Ports []int32
+}
+
+// Host returns the IP of the address, or its hostname if it is preferred to the
+// IP.
+func (a IngressAddress) Host() string {
+	if a.IP == "" {
+		return a.Hostname
+	}
+	return a.IP
+}
+
+// addressIPs returns the hosts of the addresses.
+func addressIPs(addresses []IngressAddress) []string {
+	ips := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		ips = append(ips, address.Host())
+	}
+	return ips
+}
+
+// resolveLoadBalancerIngress returns the IP of the load balancer ingress entry,
+// following the address preference of the discovery config.
+func resolveLoadBalancerIngress(ctx context.Context, entry networkingv1.IngressLoadBalancerIngress, config *discoveryConfig) (string, error) {
+	address, err := resolveLoadBalancerAddress(ctx, entry, config)
+	return address.Host(), err
+}
+
+// resolveLoadBalancerAddress is resolveLoadBalancerIngress, keeping the hostname and
+// the ports of the entry.
+func resolveLoadBalancerAddress(ctx context.Context, entry networkingv1.IngressLoadBalancerIngress, config *discoveryConfig) (IngressAddress, error) {
+	if entry.IP == "" && entry.Hostname == "" {
+		return IngressAddress{}, ErrIngressNoAddress
+	}
+	address := IngressAddress{IP: entry.IP, Hostname: entry.Hostname}
+	for _, port := range entry.Ports {
+		address.Ports = append(address.Ports, port.Port)
+	}
+	if entry.Hostname != "" && config.PreferHostname {
+		address.IP = ""
+		return address, nil
+	}
+	if entry.Hostname == "" || (entry.IP != "" && !prefersHostname(entry.IP, config.AddressPreference)) {
+		return address, nil
+	}
+
+	ip, err := resolveHostname(ctx, entry.Hostname, config.AddressSelector, config.ResolveTimeout)
+	if err != nil {
+		if entry.IP != "" && config.HostnameFallback && ctx.Err() == nil {
+			contextLogger(ctx).Error(err, "Falling back to the load balancer IP", "ip", entry.IP, "hostname", entry.Hostname)
+			return address, nil
+		}
+		return IngressAddress{}, err
+	}
+	address.IP = ip
+	address.Resolved = true
+	return address, nil
+}
+
+// resolveLoadBalancerAddresses returns the addresses of the load balancer ingress
+// entries: only the pinned one if configured, and otherwise all of them. Entries
+// whose hostname doesn't resolve are skipped as long as another one does.
+func resolveLoadBalancerAddresses(ctx context.Context, entries []networkingv1.IngressLoadBalancerIngress, owner string, config *discoveryConfig) ([]IngressAddress, error) {
+	if config.PinnedAddress != "" {
+		entry, err := selectLoadBalancerAddress(entries, owner, config)
+		if err != nil {
+			return nil, err
+		}
+		address, err := resolveLoadBalancerAddress(ctx, entry, config)
+		if err != nil {
+			return nil, err
+		}
+		return []IngressAddress{address}, nil
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.Wrapf(ErrIngressNoAddress, "%s", owner)
+	}
+
+	seen := make(map[string]struct{}, len(entries))
+	addresses := make([]IngressAddress, 0, len(entries))
+	var firstErr error
+	for _, entry := range entries {
+		address, err := resolveLoadBalancerAddress(ctx, entry, config)
+		if err != nil {
+			contextLogger(ctx).Error(err, "Skipping the load balancer address", "address", formatLoadBalancerIngress([]networkingv1.IngressLoadBalancerIngress{entry}), "owner", owner)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if _, ok := seen[address.Host()]; ok {
+			continue
+		}
+		seen[address.Host()] = struct{}{}
+		addresses = append(addresses, address)
+	}
+	if len(addresses) == 0 {
+		return nil, firstErr
+	}
+
+	preferred := AddressFamilyIPv4
+	if config.AddressSelector != nil && config.AddressSelector.Preferred == AddressFamilyIPv6 {
+		preferred = AddressFamilyIPv6
+	}
+	sortAddresses(addresses, preferred)
+	return addresses, nil
+}
+
+// sortAddresses sorts the addresses whose IP is of the preferred family first, and
+// then by IP.
+func sortAddresses(addresses []IngressAddress, preferred AddressFamily) {
+	isPreferred := func(ip string) bool {
+		parsed := net.ParseIP(ip)
+		return parsed != nil && (parsed.To4() != nil) == (preferred != AddressFamilyIPv6)
+	}
+	sort.SliceStable(addresses, func(i, j int) bool {
+		if pi, pj := isPreferred(addresses[i].IP), isPreferred(addresses[j].IP); pi != pj {
+			return pi
+		}
+		return addresses[i].IP < addresses[j].IP
+	})
+}
+
+// resolveHostname resolves the hostname within the timeout, if any, and picks one
+// of its addresses with the selector. The cancellation of ctx is returned as is,
+// rather than as a *ResolveError.
+func resolveHostname(ctx context.Context, hostname string, selector *AddressSelector, timeout time.Duration) (string, error) {
+	resolveCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		resolveCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ipAddrs, err := getResolver().LookupIPAddr(resolveCtx, hostname)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", errors.Wrapf(ctx.Err(), "failed to resolve ip address for hostname %s", hostname)
+		}
+		if resolveCtx.Err() != nil {
+			err = &net.DNSError{Err: "resolution timed out after " + timeout.String(), Name: hostname, IsTimeout: true}
+		}
+		return "", newResolveError(hostname, err)
+	}
+	ips := make([]net.IP, 0, len(ipAddrs))
+	for _, ipAddr := range ipAddrs {
+		ips = append(ips, ipAddr.IP)
+	}
+	ip, err := selector.Select(ips)
+	if err != nil {
+		return "", newResolveError(hostname, errors.Wrap(err, "failed to select an address"))
+	}
+	return ip.String(), nil
+}
+
+// hasLoadBalancerAddress reports whether any of the load balancer entries has an
+// IP or a hostname. Some controllers first report an entry with neither, which
+// means the address isn't assigned yet.
+func hasLoadBalancerAddress(entries []networkingv1.IngressLoadBalancerIngress) bool {
+	for _, entry := range entries {
+		if entry.IP != "" || entry.Hostname != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func formatLoadBalancerIngress(entries []networkingv1.IngressLoadBalancerIngress) string {
+	addresses := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IP != "" {
+			addresses = append(addresses, entry.IP)
+		}
+		if entry.Hostname != "" {
+			addresses = append(addresses, entry.Hostname)
+		}
+	}
+	return strings.Join(addresses, ", ")
+}
@@ -0,0 +1,695 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+// newLoadBalancerClientset returns a fake clientset with the nginx ingress class,
+// whose ingresses get the load balancer status on creation.
+func newLoadBalancerClientset(status ...networkingv1.IngressLoadBalancerIngress) *fake.Clientset {
+	cliset := fake.NewSimpleClientset(&networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	})
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress)
+		if ing.Name == "" {
+			ing.Name = ing.GenerateName + "test"
+		}
+		ing.Status.LoadBalancer.Ingress = status
+		return false, nil, nil
+	})
+	return cliset
+}
+
+func TestGetIngressIPs(t *testing.T) {
+	SetResolver(fakeResolver{
+		"lb.example.com":      {"10.0.0.5"},
+		"lb-ipv6.example.com": {"2001:db8::1"},
+	})
+	defer SetResolver(nil)
+
+	cliset := newLoadBalancerClientset(
+		networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9"},
+		networkingv1.IngressLoadBalancerIngress{Hostname: "lb.example.com"},
+		networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.2"},
+		networkingv1.IngressLoadBalancerIngress{Hostname: "lb-ipv6.example.com"},
+		networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9"},
+		networkingv1.IngressLoadBalancerIngress{Hostname: "gone.example.com"},
+	)
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+
+	ips, err := GetIngressIPs(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIPs() error = %v", err)
+	}
+	want := []string{"10.0.0.2", "10.0.0.5", "10.0.0.9", "2001:db8::1"}
+	if !reflect.DeepEqual(ips, want) {
+		t.Errorf("GetIngressIPs() = %v, want %v", ips, want)
+	}
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != want[0] {
+		t.Errorf("GetIngressIP() = %q, want %q", ip, want[0])
+	}
+}
+
+func TestGetIngressAddress(t *testing.T) {
+	SetResolver(fakeResolver{"lb.example.com": {"10.0.0.5"}})
+	defer SetResolver(nil)
+
+	tests := []struct {
+		name       string
+		status     networkingv1.IngressLoadBalancerIngress
+		preference string
+		want       IngressAddress
+	}{
+		{
+			name:   "ip only",
+			status: networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9"},
+			want:   IngressAddress{IP: "10.0.0.9"},
+		},
+		{
+			name:   "hostname only",
+			status: networkingv1.IngressLoadBalancerIngress{Hostname: "lb.example.com"},
+			want:   IngressAddress{IP: "10.0.0.5", Hostname: "lb.example.com", Resolved: true},
+		},
+		{
+			name:   "ip and hostname",
+			status: networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9", Hostname: "lb.example.com"},
+			want:   IngressAddress{IP: "10.0.0.9", Hostname: "lb.example.com"},
+		},
+		{
+			name:       "ip and hostname, ip preferred",
+			status:     networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9", Hostname: "lb.example.com"},
+			preference: "ip",
+			want:       IngressAddress{IP: "10.0.0.9", Hostname: "lb.example.com"},
+		},
+		{
+			name:       "public ip and hostname, auto",
+			status:     networkingv1.IngressLoadBalancerIngress{IP: "203.0.113.9", Hostname: "lb.example.com"},
+			preference: "auto",
+			want:       IngressAddress{IP: "203.0.113.9", Hostname: "lb.example.com"},
+		},
+		{
+			name:       "private ip and hostname, auto",
+			status:     networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9", Hostname: "lb.example.com"},
+			preference: "auto",
+			want:       IngressAddress{IP: "10.0.0.5", Hostname: "lb.example.com", Resolved: true},
+		},
+		{
+			name:       "ip and hostname, hostname preferred",
+			status:     networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9", Hostname: "lb.example.com"},
+			preference: "hostname",
+			want:       IngressAddress{IP: "10.0.0.5", Hostname: "lb.example.com", Resolved: true},
+		},
+		{
+			name: "ports",
+			status: networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9", Ports: []networkingv1.IngressPortStatus{
+				{Port: 80}, {Port: 443},
+			}},
+			want: IngressAddress{IP: "10.0.0.9", Ports: []int32{80, 443}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+			}
+			if tt.preference != "" {
+				data[consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference] = tt.preference
+			}
+			configMap := newNetworkConfigMap(data)
+
+			address, err := GetIngressAddress(context.Background(), staticConfigMapGetter(configMap), newLoadBalancerClientset(tt.status))
+			if err != nil {
+				t.Fatalf("GetIngressAddress() error = %v", err)
+			}
+			if !reflect.DeepEqual(address, tt.want) {
+				t.Errorf("GetIngressAddress() = %+v, want %+v", address, tt.want)
+			}
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), newLoadBalancerClientset(tt.status))
+			if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if ip != tt.want.IP {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, tt.want.IP)
+			}
+		})
+	}
+}
+
+func TestSelectLoadBalancerAddress(t *testing.T) {
+	entries := []networkingv1.IngressLoadBalancerIngress{
+		{IP: "10.0.0.2"},
+		{IP: "2001:db8::1"},
+		{Hostname: "LB.example.com"},
+	}
+
+	tests := []struct {
+		name    string
+		entries []networkingv1.IngressLoadBalancerIngress
+		pinned  string
+		want    networkingv1.IngressLoadBalancerIngress
+		wantErr bool
+	}{
+		{name: "first", entries: entries, want: entries[0]},
+		{name: "pinned IP", entries: entries, pinned: "2001:0db8::0001", want: entries[1]},
+		{name: "pinned hostname", entries: entries, pinned: "lb.example.com", want: entries[2]},
+		{name: "pinned missing", entries: entries, pinned: "10.0.0.9", wantErr: true},
+		{name: "no entries", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := selectLoadBalancerAddress(tt.entries, "the ingress test", &discoveryConfig{PinnedAddress: tt.pinned})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectLoadBalancerAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tt.entries) == 0 && !errors.Is(err, ErrIngressNoAddress) {
+				t.Errorf("selectLoadBalancerAddress() error = %v, want ErrIngressNoAddress", err)
+			}
+			if !reflect.DeepEqual(address, tt.want) {
+				t.Errorf("selectLoadBalancerAddress() = %+v, want %+v", address, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveLoadBalancerAddressesPinned(t *testing.T) {
+	config := &discoveryConfig{PinnedAddress: "10.0.0.9"}
+	addresses, err := resolveLoadBalancerAddresses(context.Background(), []networkingv1.IngressLoadBalancerIngress{
+		{IP: "10.0.0.2"},
+		{IP: "10.0.0.9"},
+	}, "the ingress test", config)
+	if err != nil {
+		t.Fatalf("resolveLoadBalancerAddresses() error = %v", err)
+	}
+	if ips := addressIPs(addresses); !reflect.DeepEqual(ips, []string{"10.0.0.9"}) {
+		t.Errorf("resolveLoadBalancerAddresses() = %v, want only the pinned address", ips)
+	}
+}
+
+func TestGetIngressIPTypedErrors(t *testing.T) {
+	SetResolver(fakeResolver{})
+	defer SetResolver(nil)
+
+	forbidden := k8serrors.NewForbidden(schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}, "", nil)
+
+	tests := []struct {
+		name   string
+		status []networkingv1.IngressLoadBalancerIngress
+		create error
+		check  func(t *testing.T, err error)
+	}{
+		{
+			// An entry without an address isn't assigned yet, so it is
+			// waited on until the timeout.
+			name:   "no address",
+			status: []networkingv1.IngressLoadBalancerIngress{{}},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("GetIngressIP() error = %v, want %v", err, context.DeadlineExceeded)
+				}
+			},
+		},
+		{
+			name:   "unresolvable hostname",
+			status: []networkingv1.IngressLoadBalancerIngress{{Hostname: "gone.example.com"}},
+			check: func(t *testing.T, err error) {
+				var resolveErr *ResolveError
+				if !errors.As(err, &resolveErr) {
+					t.Fatalf("GetIngressIP() error = %v, want a *ResolveError", err)
+				}
+				if resolveErr.Hostname != "gone.example.com" {
+					t.Errorf("ResolveError.Hostname = %q, want %q", resolveErr.Hostname, "gone.example.com")
+				}
+			},
+		},
+		{
+			name:   "create failure",
+			create: forbidden,
+			check: func(t *testing.T, err error) {
+				var createErr *ProbeCreateError
+				if !errors.As(err, &createErr) {
+					t.Fatalf("GetIngressIP() error = %v, want a *ProbeCreateError", err)
+				}
+				if createErr.Kind != "ingress" {
+					t.Errorf("ProbeCreateError.Kind = %q, want ingress", createErr.Kind)
+				}
+				if !k8serrors.IsForbidden(err) {
+					t.Errorf("GetIngressIP() error = %v, want the forbidden API error in its chain", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset(tt.status...)
+			if tt.create != nil {
+				cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.create
+				})
+			}
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:     "100ms",
+			})
+
+			_, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if err == nil {
+				t.Fatal("GetIngressIP() succeeded, want an error")
+			}
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestGetIngressIPStubResolver(t *testing.T) {
+	SetResolver(fakeResolver{"lb.example.com": {"10.0.0.7", "10.0.0.8"}})
+	defer SetResolver(nil)
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{Hostname: "lb.example.com"}))
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.7" {
+		t.Errorf("GetIngressIP() = %q, want the first canned address 10.0.0.7", ip)
+	}
+
+	_, err = GetIngressIP(context.Background(), staticConfigMapGetter(configMap), newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{Hostname: "unknown.example.com"}))
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("GetIngressIP() error = %v, want the not found error of the resolver", err)
+	}
+
+	SetResolver(nil)
+	if getResolver() != net.DefaultResolver {
+		t.Error("SetResolver(nil) didn't restore net.DefaultResolver")
+	}
+}
+
+func TestGetIngressIPPollsImmediatelyByDefault(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+	})
+	config, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		t.Fatalf("parseDiscoveryConfig() error = %v", err)
+	}
+	if !config.PollImmediately {
+		t.Fatal("PollImmediately = false, want true by default")
+	}
+
+	start := time.Now()
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"}))
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.1" {
+		t.Errorf("GetIngressIP() = %q, want 10.0.0.1", ip)
+	}
+	if elapsed := time.Since(start); elapsed >= config.PollInterval {
+		t.Errorf("GetIngressIP() took %s, want less than the poll interval %s", elapsed, config.PollInterval)
+	}
+}
+
+func TestGetIngressIPPollImmediatelyDisabled(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "50ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "false",
+	})
+
+	// The probe ingress gets its address once created, so that it is polled.
+	cliset := newLoadBalancerClientset()
+	cliset.PrependReactor("get", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, err := cliset.Tracker().Get(networkingv1.SchemeGroupVersion.WithResource("ingresses"), action.GetNamespace(), action.(k8stesting.GetAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		ing := obj.(*networkingv1.Ingress).DeepCopy()
+		ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}
+		return true, ing, nil
+	})
+
+	start := time.Now()
+	if _, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset); err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	// The first poll waits for the jittered interval, at least 40ms.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("GetIngressIP() took %s, want the first poll after the poll interval", elapsed)
+	}
+
+	configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately] = "sometimes"
+	if _, err := parseDiscoveryConfig(configMap); err == nil {
+		t.Error("parseDiscoveryConfig() with an invalid poll-immediately succeeded, want an error")
+	}
+}
+
+func TestGetIngressIPWaitsForPartialLoadBalancerStatus(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+	// The controller reports an entry with neither an IP nor a hostname first.
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{})
+	var gets int
+	cliset.PrependReactor("get", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		obj, err := cliset.Tracker().Get(networkingv1.SchemeGroupVersion.WithResource("ingresses"), action.GetNamespace(), action.(k8stesting.GetAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		ing := obj.(*networkingv1.Ingress).DeepCopy()
+		if gets > 1 {
+			ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.3"}}
+		}
+		return true, ing, nil
+	})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.3" {
+		t.Errorf("GetIngressIP() = %q, want 10.0.0.3", ip)
+	}
+	if gets < 2 {
+		t.Errorf("the ingress was polled %d times, want the empty entry polled over", gets)
+	}
+}
+
+// errorResolver fails every lookup with its error.
+type errorResolver struct {
+	err error
+}
+
+func (r errorResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return nil, r.err
+}
+
+func TestGetIngressIPDNSResolutionError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           *net.DNSError
+		wantTemporary bool
+		wantNotFound  bool
+	}{
+		{
+			name:          "temporary",
+			err:           &net.DNSError{Err: "server misbehaving", Name: "lb.example.com", IsTemporary: true},
+			wantTemporary: true,
+		},
+		{
+			name:          "timeout",
+			err:           &net.DNSError{Err: "i/o timeout", Name: "lb.example.com", IsTimeout: true},
+			wantTemporary: true,
+		},
+		{
+			name:         "not found",
+			err:          &net.DNSError{Err: "no such host", Name: "lb.example.com", IsNotFound: true},
+			wantNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetResolver(errorResolver{err: tt.err})
+			defer SetResolver(nil)
+
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{Hostname: "lb.example.com"})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+			})
+
+			_, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if !errors.Is(err, ErrDNSResolution) {
+				t.Fatalf("GetIngressIP() error = %v, want %v", err, ErrDNSResolution)
+			}
+			var resolveErr *ResolveError
+			if !errors.As(err, &resolveErr) {
+				t.Fatalf("GetIngressIP() error = %v, want a *ResolveError", err)
+			}
+			if resolveErr.IsTemporary != tt.wantTemporary || resolveErr.IsNotFound != tt.wantNotFound {
+				t.Errorf("ResolveError IsTemporary, IsNotFound = %v, %v, want %v, %v", resolveErr.IsTemporary, resolveErr.IsNotFound, tt.wantTemporary, tt.wantNotFound)
+			}
+			var dnsErr *net.DNSError
+			if !errors.As(err, &dnsErr) || dnsErr != tt.err {
+				t.Errorf("GetIngressIP() error = %v, want it to wrap the DNS error", err)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPPreferHostname(t *testing.T) {
+	// Resolving any hostname fails, so that only an unresolved one is returned.
+	SetResolver(fakeResolver{})
+	defer SetResolver(nil)
+
+	tests := []struct {
+		name   string
+		status []networkingv1.IngressLoadBalancerIngress
+		want   string
+	}{
+		{name: "hostname", status: []networkingv1.IngressLoadBalancerIngress{{Hostname: "lb.example.com"}}, want: "lb.example.com"},
+		{name: "hostname and ip", status: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1", Hostname: "lb.example.com"}}, want: "lb.example.com"},
+		{name: "ip", status: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}, want: "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:            "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:   "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferHostname: "true",
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), newLoadBalancerClientset(tt.status...))
+			if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if ip != tt.want {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, tt.want)
+			}
+
+			cliset := newLoadBalancerClientset(tt.status...)
+			if err := cliset.Tracker().Add(configMap); err != nil {
+				t.Fatalf("failed to add the network configmap: %v", err)
+			}
+			domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if err != nil {
+				t.Fatalf("GetDomainSuffix() error = %v", err)
+			}
+			if want := ComposeMagicDNSSuffix(tt.want, GetMagicDNS()); domainSuffix != want {
+				t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, want)
+			}
+		})
+	}
+}
+
+func TestParseDiscoveryConfigPreferHostnameConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		wantErr bool
+	}{
+		{name: "alone"},
+		{name: "hostname preference", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference: "hostname"}},
+		{name: "ip preference", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference: "ip"}, wantErr: true},
+		{name: "auto preference", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference: "auto"}, wantErr: true},
+		{name: "hostname fallback", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryHostnameFallback: "true"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferHostname: "true"}
+			for k, v := range tt.data {
+				data[k] = v
+			}
+			if _, err := parseDiscoveryConfig(newNetworkConfigMap(data)); (err != nil) != tt.wantErr {
+				t.Errorf("parseDiscoveryConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// blockingResolver blocks every lookup until its context is done, calling
+// onLookup first if set.
+type blockingResolver struct {
+	onLookup func()
+}
+
+func (r blockingResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if r.onLookup != nil {
+		r.onLookup()
+	}
+	<-ctx.Done()
+	return nil, &net.DNSError{Err: ctx.Err().Error(), Name: host}
+}
+
+func TestResolveHostname(t *testing.T) {
+	SetResolver(fakeResolver{"lb.example.com": {"10.0.0.5", "2001:db8::1"}})
+	defer SetResolver(nil)
+
+	ip, err := resolveHostname(context.Background(), "lb.example.com", &AddressSelector{Family: AddressFamilyIPv6}, 0)
+	if err != nil || ip != "2001:db8::1" {
+		t.Errorf("resolveHostname() = %q, %v, want the IPv6 address picked by the selector", ip, err)
+	}
+
+	_, cidr, _ := net.ParseCIDR("192.168.0.0/16")
+	_, err = resolveHostname(context.Background(), "lb.example.com", &AddressSelector{Family: AddressFamilyAny, CIDRs: []*net.IPNet{cidr}}, 0)
+	var resolveErr *ResolveError
+	if !errors.As(err, &resolveErr) || resolveErr.Hostname != "lb.example.com" {
+		t.Errorf("resolveHostname() error = %v, want a *ResolveError when no address matches the selector", err)
+	}
+
+	_, err = resolveHostname(context.Background(), "gone.example.com", &AddressSelector{Family: AddressFamilyAny}, 0)
+	if !errors.As(err, &resolveErr) || !resolveErr.IsNotFound {
+		t.Errorf("resolveHostname() error = %v, want a not found *ResolveError", err)
+	}
+
+	SetResolver(blockingResolver{})
+	_, err = resolveHostname(context.Background(), "lb.example.com", &AddressSelector{Family: AddressFamilyAny}, 10*time.Millisecond)
+	var dnsErr *net.DNSError
+	if !errors.As(err, &resolveErr) || !errors.As(err, &dnsErr) || !dnsErr.IsTimeout {
+		t.Errorf("resolveHostname() error = %v, want a *ResolveError of the timeout", err)
+	}
+}
+
+func TestResolveLoadBalancerAddressHostnameFallback(t *testing.T) {
+	// Resolving any hostname fails.
+	SetResolver(fakeResolver{})
+	defer SetResolver(nil)
+
+	entry := networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1", Hostname: "lb.example.com"}
+	for _, fallback := range []bool{false, true} {
+		t.Run(strconv.FormatBool(fallback), func(t *testing.T) {
+			config := &discoveryConfig{
+				AddressPreference: AddressPreferenceHostname,
+				AddressSelector:   &AddressSelector{Family: AddressFamilyAny},
+				HostnameFallback:  fallback,
+			}
+			address, err := resolveLoadBalancerAddress(context.Background(), entry, config)
+			if !fallback {
+				var resolveErr *ResolveError
+				if !errors.As(err, &resolveErr) {
+					t.Errorf("resolveLoadBalancerAddress() error = %v, want a *ResolveError without the fallback", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveLoadBalancerAddress() error = %v", err)
+			}
+			if address.IP != "10.0.0.1" || address.Resolved {
+				t.Errorf("resolveLoadBalancerAddress() = %+v, want the unresolved load balancer IP", address)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPResolveTimeout(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:            "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:   "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryResolveTimeout: "50ms",
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		SetResolver(blockingResolver{})
+		defer SetResolver(nil)
+
+		cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{Hostname: "lb.example.com"})
+		start := time.Now()
+		_, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+		var resolveErr *ResolveError
+		if !errors.As(err, &resolveErr) || !resolveErr.IsTemporary {
+			t.Fatalf("GetIngressIP() error = %v, want a temporary *ResolveError", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("GetIngressIP() took %s, want the resolution bounded by its timeout", elapsed)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		SetResolver(blockingResolver{onLookup: cancel})
+		defer SetResolver(nil)
+
+		cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{Hostname: "lb.example.com"})
+		_, err := GetIngressIP(ctx, staticConfigMapGetter(configMap), cliset)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("GetIngressIP() error = %v, want %v", err, context.Canceled)
+		}
+		var resolveErr *ResolveError
+		if errors.As(err, &resolveErr) {
+			t.Errorf("GetIngressIP() error = %v, want the cancellation rather than a *ResolveError", err)
+		}
+	})
+}
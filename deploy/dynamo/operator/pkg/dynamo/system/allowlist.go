@@ -0,0 +1,159 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AnnotationAllowlistMode is what is done with the ingress annotations of the
+// network config that aren't allowed.
+type AnnotationAllowlistMode string
+
+const (
+	// AnnotationAllowlistModeStrip drops the annotations that aren't allowed,
+	// with a warning.
+	AnnotationAllowlistModeStrip AnnotationAllowlistMode = "strip"
+	// AnnotationAllowlistModeReject fails the parsing of the ingress config.
+	AnnotationAllowlistModeReject AnnotationAllowlistMode = "reject"
+)
+
+// AnnotationAllowlist restricts the ingress annotations the network config may
+// set, for multi-tenant clusters where some annotations, such as the nginx
+// snippets, would let a tenant bypass the auth of the ingress controller. A key
+// ending with `*` allows all the annotations with that prefix, e.g.
+// `cert-manager.io/*`. An empty allowlist allows all the annotations.
+type AnnotationAllowlist struct {
+	Keys []string
+	// Mode defaults to AnnotationAllowlistModeStrip.
+	Mode AnnotationAllowlistMode
+}
+
+var (
+	annotationAllowlistMu sync.RWMutex
+	annotationAllowlist   AnnotationAllowlist
+)
+
+// SetAnnotationAllowlist sets the allowlist of the ingress annotations of the
+// network config. It is set by the operator rather than in the network config,
+// which the tenants may edit. The operator-managed annotations, see
+// SetDefaultAnnotations, aren't subject to it.
+func SetAnnotationAllowlist(allowlist AnnotationAllowlist) error {
+	switch allowlist.Mode {
+	case "":
+		allowlist.Mode = AnnotationAllowlistModeStrip
+	case AnnotationAllowlistModeStrip, AnnotationAllowlistModeReject:
+	default:
+		return errors.Errorf("invalid annotation allowlist mode %q, expected %s or %s", allowlist.Mode, AnnotationAllowlistModeStrip, AnnotationAllowlistModeReject)
+	}
+	allowlist.Keys = append([]string(nil), allowlist.Keys...)
+
+	annotationAllowlistMu.Lock()
+	defer annotationAllowlistMu.Unlock()
+	annotationAllowlist = allowlist
+	return nil
+}
+
+func getAnnotationAllowlist() AnnotationAllowlist {
+	annotationAllowlistMu.RLock()
+	defer annotationAllowlistMu.RUnlock()
+	return annotationAllowlist
+}
+
+// FilterAllowedAnnotations applies the allowlist set with SetAnnotationAllowlist
+// to the ingress annotations parsed from the network configmap, see
+// ParseIngressAnnotations. It also returns the keys of the annotations it
+// strips, sorted, for the caller to warn about.
+func FilterAllowedAnnotations(configMap *corev1.ConfigMap, annotations map[string]string) (filtered map[string]string, stripped []string, err error) {
+	return getAnnotationAllowlist().filter("configmap "+configMap.Name, annotations)
+}
+
+// FilterAllowedIngressAnnotations applies the allowlist to the merged annotations
+// of a generated ingress, which the tenants may set through its custom resource
+// as well as through the network config. The annotations that have the value
+// the operator sets them to, in managed or with SetDefaultAnnotations, aren't
+// subject to it.
+func FilterAllowedIngressAnnotations(ingressName string, annotations, managed map[string]string) (filtered map[string]string, stripped []string, err error) {
+	defaults := getDefaultAnnotations()
+	subject := make(map[string]string, len(annotations))
+	exempt := make(map[string]string)
+	for key, value := range annotations {
+		if managedValue, ok := managed[key]; ok && managedValue == value {
+			exempt[key] = value
+		} else if defaultValue, ok := defaults[key]; ok && defaultValue == value {
+			exempt[key] = value
+		} else {
+			subject[key] = value
+		}
+	}
+
+	filtered, stripped, err = getAnnotationAllowlist().filter("ingress "+ingressName, subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range exempt {
+		filtered[key] = value
+	}
+	return filtered, stripped, nil
+}
+
+// allows reports whether the annotation key is allowed.
+func (a AnnotationAllowlist) allows(key string) bool {
+	if len(a.Keys) == 0 {
+		return true
+	}
+	for _, allowed := range a.Keys {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(key, prefix) {
+			return true
+		}
+		if allowed == key {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the annotations of the source without the ones that aren't
+// allowed, and their keys, or an error listing them in the reject mode.
+func (a AnnotationAllowlist) filter(source string, annotations map[string]string) (filtered map[string]string, denied []string, err error) {
+	for key := range annotations {
+		if !a.allows(key) {
+			denied = append(denied, key)
+		}
+	}
+	if len(denied) == 0 {
+		return annotations, nil, nil
+	}
+	sort.Strings(denied)
+
+	if a.Mode == AnnotationAllowlistModeReject {
+		return nil, nil, errors.Errorf("the ingress annotations %s of %s aren't allowed", strings.Join(denied, ", "), source)
+	}
+	filtered = make(map[string]string, len(annotations)-len(denied))
+	for key, value := range annotations {
+		if a.allows(key) {
+			filtered[key] = value
+		}
+	}
+	return filtered, denied, nil
+}
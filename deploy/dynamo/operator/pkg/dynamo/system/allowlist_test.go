@@ -0,0 +1,202 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestParseIngressConfigAnnotationAllowlist(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"cert-manager.io/cluster-issuer": "letsencrypt", "nginx.ingress.kubernetes.io/ssl-redirect": "false", "nginx.ingress.kubernetes.io/server-snippet": "return 200;"}`,
+	})
+
+	tests := []struct {
+		name            string
+		allowlist       AnnotationAllowlist
+		wantAnnotations map[string]string
+		wantErr         string
+	}{
+		{
+			name:      "empty allowlist",
+			allowlist: AnnotationAllowlist{},
+			wantAnnotations: map[string]string{
+				"cert-manager.io/cluster-issuer":             "letsencrypt",
+				"nginx.ingress.kubernetes.io/ssl-redirect":   "false",
+				"nginx.ingress.kubernetes.io/server-snippet": "return 200;",
+			},
+		},
+		{
+			name:      "strip",
+			allowlist: AnnotationAllowlist{Keys: []string{"cert-manager.io/*", "nginx.ingress.kubernetes.io/ssl-redirect"}},
+			wantAnnotations: map[string]string{
+				"cert-manager.io/cluster-issuer":           "letsencrypt",
+				"nginx.ingress.kubernetes.io/ssl-redirect": "false",
+			},
+		},
+		{
+			name:      "reject",
+			allowlist: AnnotationAllowlist{Keys: []string{"cert-manager.io/*", "nginx.ingress.kubernetes.io/ssl-redirect"}, Mode: AnnotationAllowlistModeReject},
+			wantErr:   "nginx.ingress.kubernetes.io/server-snippet",
+		},
+		{
+			name:      "reject all allowed",
+			allowlist: AnnotationAllowlist{Keys: []string{"cert-manager.io/*", "nginx.ingress.kubernetes.io/*"}, Mode: AnnotationAllowlistModeReject},
+			wantAnnotations: map[string]string{
+				"cert-manager.io/cluster-issuer":             "letsencrypt",
+				"nginx.ingress.kubernetes.io/ssl-redirect":   "false",
+				"nginx.ingress.kubernetes.io/server-snippet": "return 200;",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetAnnotationAllowlist(tt.allowlist); err != nil {
+				t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+			}
+			defer func() { _ = SetAnnotationAllowlist(AnnotationAllowlist{}) }()
+
+			ingressConfig, err := ParseIngressConfig(configMap)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseIngressConfig() error = %v, want one about %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIngressConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(ingressConfig.Annotations, tt.wantAnnotations) {
+				t.Errorf("Annotations = %v, want %v", ingressConfig.Annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
+
+func TestSetAnnotationAllowlistInvalidMode(t *testing.T) {
+	if err := SetAnnotationAllowlist(AnnotationAllowlist{Mode: "drop"}); err == nil {
+		t.Errorf("SetAnnotationAllowlist() with an invalid mode error = nil, want an error")
+	}
+}
+
+func TestAnnotationAllowlistDefaultAnnotations(t *testing.T) {
+	SetDefaultAnnotations(map[string]string{"cert-manager.io/cluster-issuer": "operator"})
+	defer SetDefaultAnnotations(nil)
+	if err := SetAnnotationAllowlist(AnnotationAllowlist{Keys: []string{"nginx.ingress.kubernetes.io/ssl-redirect"}, Mode: AnnotationAllowlistModeReject}); err != nil {
+		t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+	}
+	defer func() { _ = SetAnnotationAllowlist(AnnotationAllowlist{}) }()
+
+	ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(nil))
+	if err != nil {
+		t.Fatalf("ParseIngressConfig() error = %v", err)
+	}
+	if ingressConfig.Annotations["cert-manager.io/cluster-issuer"] != "operator" {
+		t.Errorf("Annotations = %v, want the operator default annotation kept", ingressConfig.Annotations)
+	}
+}
+
+func TestFilterAllowedAnnotations(t *testing.T) {
+	configMap := newNetworkConfigMap(nil)
+	configMap.Name = "tenant-network"
+	annotations := map[string]string{
+		"cert-manager.io/cluster-issuer":             "letsencrypt",
+		"nginx.ingress.kubernetes.io/server-snippet": "return 200;",
+		"nginx.ingress.kubernetes.io/auth-snippet":   "return 200;",
+	}
+
+	if err := SetAnnotationAllowlist(AnnotationAllowlist{Keys: []string{"cert-manager.io/*"}}); err != nil {
+		t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+	}
+	defer func() { _ = SetAnnotationAllowlist(AnnotationAllowlist{}) }()
+	filtered, stripped, err := FilterAllowedAnnotations(configMap, annotations)
+	if err != nil {
+		t.Fatalf("FilterAllowedAnnotations() error = %v", err)
+	}
+	if want := map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}; !reflect.DeepEqual(filtered, want) {
+		t.Errorf("FilterAllowedAnnotations() = %v, want %v", filtered, want)
+	}
+	if want := []string{"nginx.ingress.kubernetes.io/auth-snippet", "nginx.ingress.kubernetes.io/server-snippet"}; !reflect.DeepEqual(stripped, want) {
+		t.Errorf("FilterAllowedAnnotations() stripped = %v, want %v", stripped, want)
+	}
+
+	if err := SetAnnotationAllowlist(AnnotationAllowlist{Keys: []string{"cert-manager.io/*"}, Mode: AnnotationAllowlistModeReject}); err != nil {
+		t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+	}
+	if _, _, err := FilterAllowedAnnotations(configMap, annotations); err == nil || !strings.Contains(err.Error(), configMap.Name) {
+		t.Errorf("FilterAllowedAnnotations() error = %v, want one naming the configmap %s", err, configMap.Name)
+	}
+}
+
+func TestFilterAllowedIngressAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		"cert-manager.io/cluster-issuer":                    "letsencrypt",
+		"nginx.ingress.kubernetes.io/ssl-redirect":          "false",
+		"nginx.ingress.kubernetes.io/configuration-snippet": "return 200;",
+		"nginx.ingress.kubernetes.io/server-snippet":        "return 200;",
+	}
+	managed := map[string]string{
+		"nginx.ingress.kubernetes.io/ssl-redirect":          "false",
+		"nginx.ingress.kubernetes.io/configuration-snippet": "more_set_headers \"X-Powered-By: Yatai\";",
+	}
+	allowlist := []string{"cert-manager.io/*"}
+
+	t.Run("strip", func(t *testing.T) {
+		if err := SetAnnotationAllowlist(AnnotationAllowlist{Keys: allowlist}); err != nil {
+			t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+		}
+		defer func() { _ = SetAnnotationAllowlist(AnnotationAllowlist{}) }()
+
+		filtered, stripped, err := FilterAllowedIngressAnnotations("default/app", annotations, managed)
+		if err != nil {
+			t.Fatalf("FilterAllowedIngressAnnotations() error = %v", err)
+		}
+		// The configuration snippet has another value than the managed one,
+		// so it was set by a tenant.
+		want := map[string]string{
+			"cert-manager.io/cluster-issuer":           "letsencrypt",
+			"nginx.ingress.kubernetes.io/ssl-redirect": "false",
+		}
+		if !reflect.DeepEqual(filtered, want) {
+			t.Errorf("FilterAllowedIngressAnnotations() = %v, want %v", filtered, want)
+		}
+		if want := []string{"nginx.ingress.kubernetes.io/configuration-snippet", "nginx.ingress.kubernetes.io/server-snippet"}; !reflect.DeepEqual(stripped, want) {
+			t.Errorf("FilterAllowedIngressAnnotations() stripped = %v, want %v", stripped, want)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		if err := SetAnnotationAllowlist(AnnotationAllowlist{Keys: allowlist, Mode: AnnotationAllowlistModeReject}); err != nil {
+			t.Fatalf("SetAnnotationAllowlist() error = %v", err)
+		}
+		defer func() { _ = SetAnnotationAllowlist(AnnotationAllowlist{}) }()
+
+		_, _, err := FilterAllowedIngressAnnotations("default/app", annotations, managed)
+		if err == nil || !strings.Contains(err.Error(), "ingress default/app") || strings.Contains(err.Error(), "ssl-redirect") {
+			t.Errorf("FilterAllowedIngressAnnotations() error = %v, want one about the tenant annotations of ingress default/app", err)
+		}
+		if _, _, err := FilterAllowedIngressAnnotations("default/app", managed, managed); err != nil {
+			t.Errorf("FilterAllowedIngressAnnotations() of the managed annotations error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// AnnotationTemplateData is the data the values of the ingress annotations of the
+// network config are expanded with, as Go templates:
+//
+//   - {{ .Namespace }} is the namespace of the ingress.
+//   - {{ .IngressName }} is the name of the ingress, or its generated name prefix
+//     for the probe ingress.
+//
+// A literal `{{` is written {{ "{{" }}. Referencing any other variable is an
+// error.
+type AnnotationTemplateData struct {
+	Namespace   string
+	IngressName string
+}
+
+// RenderAnnotations returns a copy of the annotations with their values expanded
+// with the data.
+func RenderAnnotations(annotations map[string]string, data AnnotationTemplateData) (map[string]string, error) {
+	rendered := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		value, err := renderAnnotation(key, value, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered[key] = value
+	}
+	return rendered, nil
+}
+
+func renderAnnotation(key, value string, data AnnotationTemplateData) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+	tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "the value of annotation %q is not a valid template", key)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", errors.Wrapf(err, "the value of annotation %q can't be expanded, the variables are .Namespace and .IngressName", key)
+	}
+	return b.String(), nil
+}
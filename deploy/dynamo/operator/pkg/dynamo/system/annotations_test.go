@@ -0,0 +1,50 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderAnnotations(t *testing.T) {
+	data := AnnotationTemplateData{Namespace: "team-a", IngressName: "my-service"}
+
+	rendered, err := RenderAnnotations(map[string]string{
+		"example.com/owner":   "{{ .Namespace }}/{{ .IngressName }}",
+		"example.com/literal": `{{ "{{" }} .Namespace }}`,
+		"example.com/plain":   "false",
+	}, data)
+	if err != nil {
+		t.Fatalf("RenderAnnotations() error = %v", err)
+	}
+	want := map[string]string{
+		"example.com/owner":   "team-a/my-service",
+		"example.com/literal": "{{ .Namespace }}",
+		"example.com/plain":   "false",
+	}
+	if !reflect.DeepEqual(rendered, want) {
+		t.Errorf("RenderAnnotations() = %v, want %v", rendered, want)
+	}
+
+	_, err = RenderAnnotations(map[string]string{"example.com/tenant": "{{ .Tenant }}"}, data)
+	if err == nil || !strings.Contains(err.Error(), `"example.com/tenant"`) {
+		t.Errorf("RenderAnnotations() error = %v, want one naming the annotation", err)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// BackendProtocol is the protocol the ingress controller speaks to the backend
+// service with.
+type BackendProtocol string
+
+const (
+	BackendProtocolHTTP  BackendProtocol = "HTTP"
+	BackendProtocolHTTPS BackendProtocol = "HTTPS"
+	BackendProtocolGRPC  BackendProtocol = "GRPC"
+	BackendProtocolGRPCS BackendProtocol = "GRPCS"
+)
+
+const (
+	nginxBackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+	// contourUpstreamProtocolAnnotationPrefix is suffixed with the protocol, and
+	// its value lists the service ports that speak it.
+	contourUpstreamProtocolAnnotationPrefix = "projectcontour.io/upstream-protocol."
+)
+
+// contourUpstreamProtocols are the contour upstream protocols of the backend
+// protocols, HTTP being the default one.
+var contourUpstreamProtocols = map[BackendProtocol]string{
+	BackendProtocolHTTPS: "tls",
+	BackendProtocolGRPC:  "h2c",
+	BackendProtocolGRPCS: "h2",
+}
+
+// ParseBackendProtocol returns the backend protocol of the network config,
+// case-insensitively, or "" if it isn't set.
+func ParseBackendProtocol(configMap *corev1.ConfigMap) (BackendProtocol, error) {
+	protocol := BackendProtocol(strings.ToUpper(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressBackendProtocol])))
+	switch protocol {
+	case "", BackendProtocolHTTP, BackendProtocolHTTPS, BackendProtocolGRPC, BackendProtocolGRPCS:
+		return protocol, nil
+	default:
+		return "", errors.Errorf("invalid %s %q in configmap %s, must be one of %s, %s, %s or %s", consts.KubeConfigMapKeyNetworkConfigIngressBackendProtocol, protocol, consts.KubeConfigMapNameNetworkConfig, BackendProtocolHTTP, BackendProtocolHTTPS, BackendProtocolGRPC, BackendProtocolGRPCS)
+	}
+}
+
+// BackendProtocolIngressAnnotations returns the ingress annotations that make the
+// ingress controller speak the backend protocol. Contour reads the protocol from
+// the backend service instead, see BackendProtocolServiceAnnotations. It fails
+// for a protocol other than HTTP that the controller isn't known to be
+// configured for, rather than leaving the backend unreachable.
+func BackendProtocolIngressAnnotations(protocol BackendProtocol, controllerType IngressControllerType) (map[string]string, error) {
+	switch {
+	case protocol == "":
+		return nil, nil
+	case controllerType == IngressControllerNginx:
+		return map[string]string{nginxBackendProtocolAnnotation: string(protocol)}, nil
+	case controllerType == IngressControllerContour, protocol == BackendProtocolHTTP:
+		return nil, nil
+	default:
+		return nil, errors.Errorf("the backend protocol %s isn't supported for the ingress controller %q, set the annotations of the controller in %s of configmap %s instead", protocol, controllerType, consts.KubeConfigMapKeyNetworkConfigIngressAnnotations, consts.KubeConfigMapNameNetworkConfig)
+	}
+}
+
+// BackendProtocolServiceAnnotations returns the annotations of the backend service
+// that make the ingress controller speak the backend protocol to its port, which
+// is a port name or number. Only contour is configured through the service.
+func BackendProtocolServiceAnnotations(protocol BackendProtocol, controllerType IngressControllerType, port string) map[string]string {
+	if controllerType != IngressControllerContour {
+		return nil
+	}
+	upstreamProtocol, ok := contourUpstreamProtocols[protocol]
+	if !ok {
+		return nil
+	}
+	return map[string]string{contourUpstreamProtocolAnnotationPrefix + upstreamProtocol: port}
+}
@@ -0,0 +1,109 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestParseBackendProtocol(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    BackendProtocol
+		wantErr bool
+	}{
+		{value: "", want: ""},
+		{value: "GRPC", want: BackendProtocolGRPC},
+		{value: " grpcs ", want: BackendProtocolGRPCS},
+		{value: "https", want: BackendProtocolHTTPS},
+		{value: "h2c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressBackendProtocol: tt.value,
+			})
+			got, err := ParseBackendProtocol(configMap)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBackendProtocol() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBackendProtocol() = %q, want %q", got, tt.want)
+			}
+
+			ingressConfig, err := ParseIngressConfig(configMap)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && ingressConfig.BackendProtocol != tt.want {
+				t.Errorf("BackendProtocol = %q, want %q", ingressConfig.BackendProtocol, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackendProtocolAnnotations(t *testing.T) {
+	tests := []struct {
+		protocol          BackendProtocol
+		controllerType    IngressControllerType
+		wantIngress       map[string]string
+		wantService       map[string]string
+		wantIngressErrMsg string
+	}{
+		{protocol: "", controllerType: IngressControllerNginx},
+		{protocol: "", controllerType: IngressControllerContour},
+		{protocol: BackendProtocolHTTP, controllerType: IngressControllerNginx, wantIngress: map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTP"}},
+		{protocol: BackendProtocolHTTPS, controllerType: IngressControllerNginx, wantIngress: map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"}},
+		{protocol: BackendProtocolGRPC, controllerType: IngressControllerNginx, wantIngress: map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "GRPC"}},
+		{protocol: BackendProtocolGRPCS, controllerType: IngressControllerNginx, wantIngress: map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "GRPCS"}},
+		{protocol: BackendProtocolHTTP, controllerType: IngressControllerContour},
+		{protocol: BackendProtocolHTTPS, controllerType: IngressControllerContour, wantService: map[string]string{"projectcontour.io/upstream-protocol.tls": "http"}},
+		{protocol: BackendProtocolGRPC, controllerType: IngressControllerContour, wantService: map[string]string{"projectcontour.io/upstream-protocol.h2c": "http"}},
+		{protocol: BackendProtocolGRPCS, controllerType: IngressControllerContour, wantService: map[string]string{"projectcontour.io/upstream-protocol.h2": "http"}},
+		{protocol: BackendProtocolHTTP, controllerType: IngressControllerTraefik},
+		{protocol: BackendProtocolGRPC, controllerType: IngressControllerTraefik, wantIngressErrMsg: `isn't supported for the ingress controller "traefik"`},
+		{protocol: BackendProtocolGRPC, controllerType: IngressControllerUnknown, wantIngressErrMsg: `isn't supported for the ingress controller ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.controllerType)+"/"+string(tt.protocol), func(t *testing.T) {
+			ingressAnnotations, err := BackendProtocolIngressAnnotations(tt.protocol, tt.controllerType)
+			if tt.wantIngressErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantIngressErrMsg) {
+					t.Errorf("BackendProtocolIngressAnnotations() error = %v, want one containing %q", err, tt.wantIngressErrMsg)
+				}
+			} else if err != nil {
+				t.Errorf("BackendProtocolIngressAnnotations() error = %v", err)
+			} else if len(ingressAnnotations) != 0 || len(tt.wantIngress) != 0 {
+				if !reflect.DeepEqual(ingressAnnotations, tt.wantIngress) {
+					t.Errorf("BackendProtocolIngressAnnotations() = %v, want %v", ingressAnnotations, tt.wantIngress)
+				}
+			}
+
+			serviceAnnotations := BackendProtocolServiceAnnotations(tt.protocol, tt.controllerType, "http")
+			if (len(serviceAnnotations) != 0 || len(tt.wantService) != 0) && !reflect.DeepEqual(serviceAnnotations, tt.wantService) {
+				t.Errorf("BackendProtocolServiceAnnotations() = %v, want %v", serviceAnnotations, tt.wantService)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+const (
+	certManagerClusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
+	certManagerSecretNamePrefix        = "dynamo-tls-"
+	certManagerSecretNameHashLength    = 16
+)
+
+// WithCertManager returns the annotations and the TLS section that make
+// cert-manager provision a certificate for the hosts with the cluster issuer. The
+// secret name is derived from the set of hosts, so that the same hosts always
+// share the same secret, whatever their order.
+func WithCertManager(issuer string, hosts []string) (annotations map[string]string, tls IngressTLSConfig) {
+	hosts = uniqueSortedHosts(hosts)
+	annotations = map[string]string{certManagerClusterIssuerAnnotation: issuer}
+	tls = IngressTLSConfig{
+		SecretName: certManagerSecretName(hosts),
+		Hosts:      hosts,
+	}
+	return
+}
+
+func uniqueSortedHosts(hosts []string) []string {
+	seen := make(map[string]struct{}, len(hosts))
+	unique := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if _, ok := seen[host]; ok || host == "" {
+			continue
+		}
+		seen[host] = struct{}{}
+		unique = append(unique, host)
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// certManagerSecretName hashes the sorted hosts, since they may be too long, or
+// too many, for a secret name.
+func certManagerSecretName(hosts []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(hosts, "\n")))
+	return certManagerSecretNamePrefix + hex.EncodeToString(sum[:])[:certManagerSecretNameHashLength]
+}
+
+// ParseCertManagerIssuer returns the cert-manager cluster issuer of the network
+// config, or "" if it isn't set.
+func ParseCertManagerIssuer(configMap *corev1.ConfigMap) (string, error) {
+	issuer := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigCertManagerIssuer])
+	if issuer == "" {
+		return "", nil
+	}
+	if errs := validation.IsDNS1123Subdomain(issuer); len(errs) > 0 {
+		return "", errors.Errorf("invalid %s %q in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigCertManagerIssuer, issuer, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+	}
+	return issuer, nil
+}
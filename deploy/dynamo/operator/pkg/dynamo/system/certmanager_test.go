@@ -0,0 +1,77 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestWithCertManager(t *testing.T) {
+	annotations, tls := WithCertManager("letsencrypt", []string{"b.example.com", "a.example.com", "B.example.com"})
+
+	wantAnnotations := map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}
+	if !reflect.DeepEqual(annotations, wantAnnotations) {
+		t.Errorf("WithCertManager() annotations = %v, want %v", annotations, wantAnnotations)
+	}
+	if want := []string{"a.example.com", "b.example.com"}; !reflect.DeepEqual(tls.Hosts, want) {
+		t.Errorf("WithCertManager() hosts = %v, want %v", tls.Hosts, want)
+	}
+	if !strings.HasPrefix(tls.SecretName, "dynamo-tls-") || len(tls.SecretName) != len("dynamo-tls-")+16 {
+		t.Errorf("WithCertManager() secret name = %q, want dynamo-tls- and 16 hex digits", tls.SecretName)
+	}
+
+	_, reordered := WithCertManager("other", []string{"a.example.com", "b.example.com"})
+	if reordered.SecretName != tls.SecretName {
+		t.Errorf("WithCertManager() secret name = %q for the same hosts, want %q", reordered.SecretName, tls.SecretName)
+	}
+	_, other := WithCertManager("letsencrypt", []string{"a.example.com"})
+	if other.SecretName == tls.SecretName {
+		t.Errorf("WithCertManager() secret name = %q for other hosts, want another one", other.SecretName)
+	}
+}
+
+func TestParseCertManagerIssuer(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: ""},
+		{value: " letsencrypt-prod ", want: "letsencrypt-prod"},
+		{value: "Lets_Encrypt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigCertManagerIssuer: tt.value,
+			})
+			ingressConfig, err := ParseIngressConfig(configMap)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && ingressConfig.CertManagerIssuer != tt.want {
+				t.Errorf("CertManagerIssuer = %q, want %q", ingressConfig.CertManagerIssuer, tt.want)
+			}
+		})
+	}
+}
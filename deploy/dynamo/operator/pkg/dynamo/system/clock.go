@@ -0,0 +1,48 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"sync"
+
+	"k8s.io/utils/clock"
+)
+
+var (
+	clockMu sync.RWMutex
+	// discoveryClock drives the probe status polls and their timeout.
+	discoveryClock clock.Clock = clock.RealClock{}
+)
+
+// SetClock replaces the clock of the probe status polls and their timeout, e.g.
+// with a fake one to test a 20 minute timeout without waiting for it. A nil clock
+// restores the real one.
+func SetClock(c clock.Clock) {
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	if c == nil {
+		c = clock.RealClock{}
+	}
+	discoveryClock = c
+}
+
+func getClock() clock.Clock {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return discoveryClock
+}
@@ -0,0 +1,67 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+var knownNetworkConfigKeys = func() map[string]bool {
+	keys := make(map[string]bool, len(consts.KubeConfigMapKeysNetworkConfig))
+	for _, key := range consts.KubeConfigMapKeysNetworkConfig {
+		keys[key] = true
+	}
+	return keys
+}()
+
+// warnedNetworkConfigKeys are the unrecognized keys already warned about, so
+// that a typo is reported once rather than on every reconciliation.
+var warnedNetworkConfigKeys sync.Map
+
+// ValidateNetworkConfigKeys returns the keys of the network configmap, sorted,
+// that the operator doesn't recognize, e.g. misspelled ones, which are
+// otherwise silently ignored.
+func ValidateNetworkConfigKeys(configMap *corev1.ConfigMap) []string {
+	var unknown []string
+	for key := range configMap.Data {
+		if knownNetworkConfigKeys[key] || strings.HasPrefix(key, consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation) {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// warnUnknownNetworkConfigKeys logs a warning about the keys of the network
+// configmap ValidateNetworkConfigKeys doesn't recognize.
+func warnUnknownNetworkConfigKeys(logger logr.Logger, configMap *corev1.ConfigMap) {
+	for _, key := range ValidateNetworkConfigKeys(configMap) {
+		if _, warned := warnedNetworkConfigKeys.LoadOrStore(configMap.Namespace+"/"+configMap.Name+"/"+key, struct{}{}); warned {
+			continue
+		}
+		logger.Info("Ignoring the unrecognized key of the network config, check it for a typo", "key", key, "configmap", configMap.Namespace+"/"+configMap.Name)
+	}
+}
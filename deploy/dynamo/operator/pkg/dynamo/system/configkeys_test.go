@@ -0,0 +1,121 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestValidateNetworkConfigKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+		want []string
+	}{
+		{
+			name: "clean",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:       "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix:       "example.com",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWatch:     "true",
+				"ingress.annotation.nginx.ingress.kubernetes.io_proxy": "on",
+			},
+		},
+		{
+			name: "typos",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+				"ingresClass":               "nginx",
+				"discovery-poll-intervall":  "1s",
+				"ingress.annotations.owner": "team-a",
+			},
+			want: []string{"discovery-poll-intervall", "ingresClass", "ingress.annotations.owner"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateNetworkConfigKeys(newNetworkConfigMap(tt.data)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateNetworkConfigKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestKubeConfigMapKeysNetworkConfigComplete checks that every network config key
+// constant is registered, so that it isn't reported as unrecognized.
+func TestKubeConfigMapKeysNetworkConfigComplete(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "../consts/kube.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse the consts: %v", err)
+	}
+	registered := map[string]bool{}
+	for _, key := range consts.KubeConfigMapKeysNetworkConfig {
+		registered[key] = true
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "KubeConfigMapKeyNetworkConfig") || i >= len(spec.Values) {
+				continue
+			}
+			value, ok := spec.Values[i].(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			if key := strings.Trim(value.Value, `"`); !registered[key] {
+				t.Errorf("%s (%s) is missing from KubeConfigMapKeysNetworkConfig", name.Name, key)
+			}
+		}
+		return true
+	})
+}
+
+func TestWarnUnknownNetworkConfigKeys(t *testing.T) {
+	var lines []map[string]interface{}
+	base := funcr.New(func(prefix, args string) {}, funcr.Options{})
+	logger := logr.New(&recordingSink{LogSink: base.GetSink(), lines: &lines})
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+		"ingres-class": "traefik",
+	})
+	configMap.Name = "warn-unknown-keys"
+	warnUnknownNetworkConfigKeys(logger, configMap)
+	// A typo is reported once.
+	warnUnknownNetworkConfigKeys(logger, configMap)
+
+	if len(lines) != 1 {
+		t.Fatalf("logged %d lines, want 1: %v", len(lines), lines)
+	}
+	if lines[0]["key"] != "ingres-class" || lines[0]["configmap"] != configMap.Namespace+"/"+configMap.Name {
+		t.Errorf("logged %v, want the key ingres-class of the configmap %s/%s", lines[0], configMap.Namespace, configMap.Name)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultConfigMapCacheTTL is the TTL of NewCachedConfigMapGetter when none is
+// given.
+const DefaultConfigMapCacheTTL = 30 * time.Second
+
+type cachedConfigMap struct {
+	configMap *corev1.ConfigMap
+	expiresAt time.Time
+}
+
+// NewCachedConfigMapGetter wraps the configmap getter with an in-memory cache of
+// the configmaps by namespace and name, which expire after ttl, or
+// DefaultConfigMapCacheTTL if ttl isn't positive. Errors aren't cached. The
+// returned getter is safe for concurrent use, and returns copies that callers
+// may modify.
+func NewCachedConfigMapGetter(getter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), ttl time.Duration) func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return newCachedConfigMapGetter(getter, ttl, time.Now)
+}
+
+func newCachedConfigMapGetter(getter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), ttl time.Duration, now func() time.Time) func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	if ttl <= 0 {
+		ttl = DefaultConfigMapCacheTTL
+	}
+
+	var mu sync.Mutex
+	cache := make(map[string]cachedConfigMap)
+
+	return func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		key := namespace + "/" + name
+
+		mu.Lock()
+		entry, ok := cache[key]
+		if ok && now().Before(entry.expiresAt) {
+			mu.Unlock()
+			return entry.configMap.DeepCopy(), nil
+		}
+		delete(cache, key)
+		mu.Unlock()
+
+		configMap, err := getter(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+
+		mu.Lock()
+		cache[key] = cachedConfigMap{
+			configMap: configMap.DeepCopy(),
+			expiresAt: now().Add(ttl),
+		}
+		mu.Unlock()
+		return configMap, nil
+	}
+}
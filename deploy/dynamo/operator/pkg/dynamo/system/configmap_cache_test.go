@@ -0,0 +1,105 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCachedConfigMapGetter(t *testing.T) {
+	calls := make(map[string]int)
+	getter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		calls[namespace+"/"+name]++
+		if name == "missing" {
+			return nil, errors.New("not found")
+		}
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}, nil
+	}
+
+	now := time.Now()
+	cached := newCachedConfigMapGetter(getter, time.Minute, func() time.Time { return now })
+	ctx := context.Background()
+
+	get := func(namespace, name string) {
+		t.Helper()
+		configMap, err := cached(ctx, namespace, name)
+		if err != nil {
+			t.Fatalf("cached getter error = %v", err)
+		}
+		if configMap.Namespace != namespace || configMap.Name != name {
+			t.Fatalf("cached getter returned %s/%s, want %s/%s", configMap.Namespace, configMap.Name, namespace, name)
+		}
+	}
+
+	// A miss fetches the configmap, and a hit within the TTL doesn't.
+	get("a", "network")
+	get("a", "network")
+	if calls["a/network"] != 1 {
+		t.Errorf("a/network fetched %d times within the TTL, want 1", calls["a/network"])
+	}
+
+	// Another namespace is another key.
+	get("b", "network")
+	if calls["b/network"] != 1 {
+		t.Errorf("b/network fetched %d times, want 1", calls["b/network"])
+	}
+
+	// Errors aren't cached.
+	for i := 0; i < 2; i++ {
+		if _, err := cached(ctx, "a", "missing"); err == nil {
+			t.Fatal("cached getter error = nil, want the getter error")
+		}
+	}
+	if calls["a/missing"] != 2 {
+		t.Errorf("a/missing fetched %d times, want 2", calls["a/missing"])
+	}
+
+	// The entries expire after the TTL.
+	now = now.Add(time.Minute)
+	get("a", "network")
+	if calls["a/network"] != 2 {
+		t.Errorf("a/network fetched %d times after the TTL, want 2", calls["a/network"])
+	}
+}
+
+func TestCachedConfigMapGetterReturnsCopies(t *testing.T) {
+	getter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return &corev1.ConfigMap{Data: map[string]string{"key": "value"}}, nil
+	}
+	cached := NewCachedConfigMapGetter(getter, 0)
+
+	first, err := cached(context.Background(), "a", "network")
+	if err != nil {
+		t.Fatalf("cached getter error = %v", err)
+	}
+	first.Data["key"] = "modified"
+
+	second, err := cached(context.Background(), "a", "network")
+	if err != nil {
+		t.Fatalf("cached getter error = %v", err)
+	}
+	if second.Data["key"] != "value" {
+		t.Errorf("cached configmap data = %q, want the original value", second.Data["key"])
+	}
+}
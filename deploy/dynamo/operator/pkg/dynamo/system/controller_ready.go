@@ -0,0 +1,85 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CheckIngressControllerReady checks that the workloads of the ingress controller
+// behind the class (or the default class, when nil) have at least one ready
+// replica, and returns a *PreflightError if they don't.
+//
+// The workloads are looked up by the selector, or by the upstream labels of the
+// detected controller when it is empty. When the controller isn't recognized or
+// no workload matches the upstream labels, the check is skipped, since the
+// controller may be deployed with other labels.
+func CheckIngressControllerReady(ctx context.Context, cliset kubernetes.Interface, className *string, selector string) error {
+	explicit := selector != ""
+	if !explicit {
+		controllerType := GetIngressControllerType(ctx, cliset, className)
+		var ok bool
+		if selector, ok = ingressControllerPodSelectors[controllerType]; !ok {
+			contextLogger(ctx).Info("Skipping the ingress controller readiness check, the controller is not recognized", "controllerType", controllerType)
+			return nil
+		}
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: selector}
+
+	deployments, err := cliset.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the ingress controller deployments by %s", selector)
+	}
+	daemonSets, err := cliset.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the ingress controller daemonsets by %s", selector)
+	}
+
+	workloads := len(deployments.Items) + len(daemonSets.Items)
+	if workloads == 0 {
+		if explicit {
+			return &PreflightError{
+				Reason:  PreflightReasonIngressControllerNotRunning,
+				Message: fmt.Sprintf("no deployment or daemonset of the ingress controller matches %s", selector),
+			}
+		}
+		return nil
+	}
+
+	for _, deployment := range deployments.Items {
+		if deployment.Status.ReadyReplicas > 0 {
+			return nil
+		}
+	}
+	for _, daemonSet := range daemonSets.Items {
+		if daemonSet.Status.NumberReady > 0 {
+			return nil
+		}
+	}
+
+	return &PreflightError{
+		Reason:  PreflightReasonIngressControllerNotReady,
+		Message: fmt.Sprintf("none of the %d deployments and daemonsets of the ingress controller (%s) has a ready replica", workloads, selector),
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestCheckIngressControllerReady(t *testing.T) {
+	nginxClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+	unknownClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "example.com/ingress"},
+	}
+	nginxLabels := map[string]string{"app.kubernetes.io/name": "ingress-nginx"}
+	newDeployment := func(labels map[string]string, ready int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx", Labels: labels},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: ready},
+		}
+	}
+	newDaemonSet := func(labels map[string]string, ready int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx", Labels: labels},
+			Status:     appsv1.DaemonSetStatus{NumberReady: ready},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		selector   string
+		wantReason PreflightReason
+	}{
+		{
+			name:    "ready deployment",
+			objects: []runtime.Object{nginxClass, newDeployment(nginxLabels, 1)},
+		},
+		{
+			name:    "ready daemonset",
+			objects: []runtime.Object{nginxClass, newDeployment(nginxLabels, 0), newDaemonSet(nginxLabels, 2)},
+		},
+		{
+			name:       "not ready",
+			objects:    []runtime.Object{nginxClass, newDeployment(nginxLabels, 0), newDaemonSet(nginxLabels, 0)},
+			wantReason: PreflightReasonIngressControllerNotReady,
+		},
+		{
+			name:    "no upstream workload",
+			objects: []runtime.Object{nginxClass},
+		},
+		{
+			name:    "unrecognized controller",
+			objects: []runtime.Object{unknownClass, newDeployment(nginxLabels, 0)},
+		},
+		{
+			name:       "selector",
+			objects:    []runtime.Object{unknownClass, newDeployment(map[string]string{"app": "ingress"}, 0)},
+			selector:   "app=ingress",
+			wantReason: PreflightReasonIngressControllerNotReady,
+		},
+		{
+			name:       "selector matching nothing",
+			objects:    []runtime.Object{unknownClass, newDeployment(nginxLabels, 1)},
+			selector:   "app=ingress",
+			wantReason: PreflightReasonIngressControllerNotRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := fake.NewSimpleClientset(tt.objects...)
+			err := CheckIngressControllerReady(context.Background(), cliset, ptr.To("nginx"), tt.selector)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("CheckIngressControllerReady() error = %v, want nil", err)
+				}
+				return
+			}
+			var preflightErr *PreflightError
+			if !errors.As(err, &preflightErr) || preflightErr.Reason != tt.wantReason {
+				t.Errorf("CheckIngressControllerReady() error = %v, want a %s PreflightError", err, tt.wantReason)
+			}
+		})
+	}
+}
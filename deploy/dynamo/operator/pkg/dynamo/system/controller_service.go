@@ -0,0 +1,201 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// ingressControllerServiceSelectors are the label selectors of the Services
+// fronting the recognized ingress controllers, as set by their upstream manifests.
+var ingressControllerServiceSelectors = map[IngressControllerType]string{
+	IngressControllerNginx:   "app.kubernetes.io/name=ingress-nginx,app.kubernetes.io/component=controller",
+	IngressControllerContour: "app.kubernetes.io/name=contour,app.kubernetes.io/component=envoy",
+	IngressControllerTraefik: "app.kubernetes.io/name=traefik",
+	IngressControllerHAProxy: "app.kubernetes.io/name=kubernetes-ingress",
+	IngressControllerIstio:   "app=istio-ingressgateway",
+}
+
+// IngressControllerService is the Service fronting the ingress controller.
+type IngressControllerService struct {
+	Namespace string
+	Name      string
+	Type      corev1.ServiceType
+	ClusterIP string
+	Ports     []corev1.ServicePort
+}
+
+// GetIngressControllerService locates the Service of the ingress controller, so
+// that its health can be checked directly. The Service is looked up by the
+// namespace/name or the label selector set in the network config, and otherwise
+// by the upstream labels of the detected ingress controller.
+func GetIngressControllerService(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (controllerService *IngressControllerService, err error) {
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
+		return
+	}
+
+	ingressConfig, err := ParseIngressConfig(configMap)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get ingress config")
+		return
+	}
+
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get discovery config")
+		return
+	}
+
+	svc, err := findIngressControllerService(ctx, cliset, discoveryConfig, ingressConfig.ClassName)
+	if err != nil {
+		return
+	}
+
+	controllerService = &IngressControllerService{
+		Namespace: svc.Namespace,
+		Name:      svc.Name,
+		Type:      svc.Spec.Type,
+		ClusterIP: svc.Spec.ClusterIP,
+		Ports:     svc.Spec.Ports,
+	}
+	return
+}
+
+// serviceLoadBalancerEntries converts the load balancer status of the Service to
+// load balancer ingress entries, so that they are selected and resolved like
+// those of an ingress.
+func serviceLoadBalancerEntries(svc *corev1.Service) []networkingv1.IngressLoadBalancerIngress {
+	entries := make([]networkingv1.IngressLoadBalancerIngress, 0, len(svc.Status.LoadBalancer.Ingress))
+	for _, entry := range svc.Status.LoadBalancer.Ingress {
+		entries = append(entries, networkingv1.IngressLoadBalancerIngress{IP: entry.IP, Hostname: entry.Hostname})
+	}
+	return entries
+}
+
+func findIngressControllerService(ctx context.Context, cliset kubernetes.Interface, config *discoveryConfig, className *string) (*corev1.Service, error) {
+	if config.ControllerService != "" {
+		return getIngressControllerService(ctx, cliset, config)
+	}
+
+	services, selector, err := listIngressControllerServices(ctx, cliset, config, className)
+	if err != nil {
+		return nil, err
+	}
+	switch len(services) {
+	case 0:
+		return nil, errors.Errorf("no ingress controller service matches %s", selector)
+	case 1:
+		return &services[0], nil
+	default:
+		return nil, errors.Errorf("%d services match the ingress controller selector %s, set %s to pick one", len(services), selector, consts.KubeConfigMapKeyNetworkConfigIngressControllerService)
+	}
+}
+
+// findIngressControllerLoadBalancerService is findIngressControllerService for
+// the load balancer status fallback: of the Services matching the selector, the
+// only one of type LoadBalancer is picked, as a controller may also be fronted
+// by e.g. an internal or a metrics Service. A Service set by name is used as
+// is.
+func findIngressControllerLoadBalancerService(ctx context.Context, cliset kubernetes.Interface, config *discoveryConfig, className *string) (*corev1.Service, error) {
+	if config.ControllerService != "" {
+		return getIngressControllerService(ctx, cliset, config)
+	}
+
+	services, selector, err := listIngressControllerServices(ctx, cliset, config, className)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*corev1.Service
+	var names []string
+	for i := range services {
+		if services[i].Spec.Type == corev1.ServiceTypeLoadBalancer {
+			matches = append(matches, &services[i])
+			names = append(names, services[i].Namespace+"/"+services[i].Name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		if len(services) == 0 {
+			return nil, errors.Errorf("no ingress controller service matches %s", selector)
+		}
+		return nil, errors.Errorf("none of the %d ingress controller services matching %s is of type %s", len(services), selector, corev1.ServiceTypeLoadBalancer)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, errors.Errorf("%d services of type %s match the ingress controller selector %s: %s, set %s to pick one", len(matches), corev1.ServiceTypeLoadBalancer, selector, strings.Join(names, ", "), consts.KubeConfigMapKeyNetworkConfigIngressControllerService)
+	}
+}
+
+// getIngressControllerService gets the Service of the ingress controller set by
+// name in the network config.
+func getIngressControllerService(ctx context.Context, cliset kubernetes.Interface, config *discoveryConfig) (*corev1.Service, error) {
+	namespace, name, err := parseNamespacedName(config.ControllerService, namespaceFromContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigIngressControllerService, consts.KubeConfigMapNameNetworkConfig)
+	}
+	svc, err := cliset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the ingress controller service %s/%s", namespace, name)
+	}
+	return svc, nil
+}
+
+// listIngressControllerServices lists the Services matching the selector set in
+// the network config, or else the upstream labels of the detected ingress
+// controller, and returns them with the selector.
+func listIngressControllerServices(ctx context.Context, cliset kubernetes.Interface, config *discoveryConfig, className *string) (services []corev1.Service, selector string, err error) {
+	selector = config.ControllerServiceSelector
+	if selector == "" {
+		controllerType := GetIngressControllerType(ctx, cliset, className)
+		var ok bool
+		if selector, ok = ingressControllerServiceSelectors[controllerType]; !ok {
+			return nil, "", errors.Errorf("cannot locate the service of the ingress controller, set %s or %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigIngressControllerService, consts.KubeConfigMapKeyNetworkConfigIngressControllerServiceSelector, consts.KubeConfigMapNameNetworkConfig)
+		}
+	}
+
+	list, err := cliset.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to list the ingress controller services by %s", selector)
+	}
+	return list.Items, selector, nil
+}
+
+// parseNamespacedName parses `namespace/name`, or a bare `name` in the default
+// namespace.
+func parseNamespacedName(value, defaultNamespace string) (namespace, name string, err error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return defaultNamespace, parts[0], nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	default:
+		return "", "", errors.Errorf("expected `namespace/name` or `name`, got %q", value)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func newControllerService(namespace, name string, labels map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeLoadBalancer,
+			ClusterIP: "10.96.0.10",
+			Ports:     []corev1.ServicePort{{Name: "http", Port: 80}},
+		},
+	}
+}
+
+func TestGetIngressControllerService(t *testing.T) {
+	nginxLabels := map[string]string{"app.kubernetes.io/name": "ingress-nginx", "app.kubernetes.io/component": "controller"}
+	nginxClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+
+	tests := []struct {
+		name     string
+		data     map[string]string
+		services []*corev1.Service
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "by name",
+			data:     map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressControllerService: "ingress/edge"},
+			services: []*corev1.Service{newControllerService("ingress", "edge", nil)},
+			want:     "ingress/edge",
+		},
+		{
+			name:    "by name missing",
+			data:    map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressControllerService: "ingress/edge"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			data:    map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressControllerService: "a/b/c"},
+			wantErr: true,
+		},
+		{
+			name:     "by selector",
+			data:     map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressControllerServiceSelector: "app=edge"},
+			services: []*corev1.Service{newControllerService("ingress", "edge", map[string]string{"app": "edge"}), newControllerService("ingress", "other", nil)},
+			want:     "ingress/edge",
+		},
+		{
+			name:     "by controller labels",
+			data:     map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx"},
+			services: []*corev1.Service{newControllerService("ingress-nginx", "ingress-nginx-controller", nginxLabels)},
+			want:     "ingress-nginx/ingress-nginx-controller",
+		},
+		{
+			name:    "unknown controller",
+			wantErr: true,
+		},
+		{
+			name:     "ambiguous",
+			data:     map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx"},
+			services: []*corev1.Service{newControllerService("ingress-nginx", "a", nginxLabels), newControllerService("ingress-nginx", "b", nginxLabels)},
+			wantErr:  true,
+		},
+		{
+			name:    "no match",
+			data:    map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := fake.NewSimpleClientset(nginxClass)
+			for _, svc := range tt.services {
+				if _, err := cliset.CoreV1().Services(svc.Namespace).Create(context.Background(), svc, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create the service: %v", err)
+				}
+			}
+
+			controllerService, err := GetIngressControllerService(context.Background(), staticConfigMapGetter(newNetworkConfigMap(tt.data)), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIngressControllerService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := controllerService.Namespace + "/" + controllerService.Name; got != tt.want {
+				t.Errorf("GetIngressControllerService() = %s, want %s", got, tt.want)
+			}
+			if controllerService.ClusterIP != "10.96.0.10" || len(controllerService.Ports) != 1 {
+				t.Errorf("GetIngressControllerService() = %+v, want the cluster IP and ports of the service", controllerService)
+			}
+		})
+	}
+}
+
+func TestParseNamespacedName(t *testing.T) {
+	tests := []struct {
+		value         string
+		wantNamespace string
+		wantName      string
+		wantErr       bool
+	}{
+		{value: "edge", wantNamespace: "default", wantName: "edge"},
+		{value: " ingress/edge ", wantNamespace: "ingress", wantName: "edge"},
+		{value: "", wantErr: true},
+		{value: "ingress/", wantErr: true},
+		{value: "a/b/c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		namespace, name, err := parseNamespacedName(tt.value, "default")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNamespacedName(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if namespace != tt.wantNamespace || name != tt.wantName {
+			t.Errorf("parseNamespacedName(%q) = %s, %s, want %s, %s", tt.value, namespace, name, tt.wantNamespace, tt.wantName)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/rs/xid"
+)
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID, which the
+// discovery then uses instead of generating its own.
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by the context, or
+// an empty string if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
+
+// ensureCorrelationID returns a context carrying a correlation ID, generating one
+// if the context doesn't have one yet, so that a whole discovery shares one ID.
+func ensureCorrelationID(ctx context.Context) (context.Context, string) {
+	if correlationID := CorrelationIDFromContext(ctx); correlationID != "" {
+		return ctx, correlationID
+	}
+	correlationID := xid.New().String()
+	return WithCorrelationID(ctx, correlationID), correlationID
+}
+
+// WithLogger returns a context carrying the logger of the discovery, e.g. the
+// reconcile logger of a controller, so that its fields show up on the discovery
+// lines. Without one, the discovery doesn't log. It is the same context key as
+// the one of logr.NewContext.
+func WithLogger(ctx context.Context, logger logr.Logger) context.Context {
+	return logr.NewContext(ctx, logger)
+}
+
+func discoveryLogger(ctx context.Context, correlationID string) logr.Logger {
+	return logr.FromContextOrDiscard(ctx).WithValues("correlation_id", correlationID)
+}
+
+// contextLogger is discoveryLogger with the correlation ID and the namespace of
+// the context, for the helpers that aren't passed the logger of the discovery.
+func contextLogger(ctx context.Context) logr.Logger {
+	return discoveryLogger(ctx, CorrelationIDFromContext(ctx)).WithValues("namespace", namespaceFromContext(ctx))
+}
@@ -0,0 +1,114 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestGetDomainSuffixLogsStructuredFields(t *testing.T) {
+	var lines []map[string]interface{}
+	base := funcr.New(func(prefix, args string) {}, funcr.Options{})
+	logger := logr.New(&recordingSink{LogSink: base.GetSink(), lines: &lines})
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	ctx := WithCorrelationID(WithLogger(context.Background(), logger), "test-correlation")
+	if _, err := GetDomainSuffix(ctx, staticConfigMapGetter(configMap), cliset); err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		for key := range line {
+			seen[key] = true
+		}
+		if line["correlation_id"] != "test-correlation" {
+			t.Errorf("line %v has correlation_id %v, want test-correlation", line["msg"], line["correlation_id"])
+		}
+		if line["namespace"] != GetNamespace() {
+			t.Errorf("line %v has namespace %v, want %s", line["msg"], line["namespace"], GetNamespace())
+		}
+	}
+	for _, key := range []string{"ingress", "namespace", "ip", "domainSuffix"} {
+		if !seen[key] {
+			t.Errorf("no line has the %q key, lines: %v", key, lines)
+		}
+	}
+}
+
+func TestEnsureCorrelationID(t *testing.T) {
+	ctx, correlationID := ensureCorrelationID(WithCorrelationID(context.Background(), "existing"))
+	if correlationID != "existing" || CorrelationIDFromContext(ctx) != "existing" {
+		t.Errorf("ensureCorrelationID() = %q, want the existing correlation ID kept", correlationID)
+	}
+
+	ctx, correlationID = ensureCorrelationID(context.Background())
+	if correlationID == "" || CorrelationIDFromContext(ctx) != correlationID {
+		t.Errorf("ensureCorrelationID() = %q with %q in the context, want a generated ID carried by the context", correlationID, CorrelationIDFromContext(ctx))
+	}
+	if _, again := ensureCorrelationID(context.Background()); again == correlationID {
+		t.Errorf("ensureCorrelationID() generated %q twice, want distinct IDs", again)
+	}
+}
+
+func TestDiscoveryLoggerWithoutLogger(t *testing.T) {
+	if sink := discoveryLogger(context.Background(), "test").GetSink(); sink != nil {
+		t.Errorf("discoveryLogger() sink = %T, want the discard logger", sink)
+	}
+}
+
+// recordingSink records the key/value pairs of every info line, with those of
+// the WithValues calls.
+type recordingSink struct {
+	logr.LogSink
+	values []interface{}
+	lines  *[]map[string]interface{}
+}
+
+func (s *recordingSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	line := map[string]interface{}{"msg": msg}
+	all := append(append([]interface{}{}, s.values...), keysAndValues...)
+	for i := 0; i+1 < len(all); i += 2 {
+		line[all[i].(string)] = all[i+1]
+	}
+	*s.lines = append(*s.lines, line)
+}
+
+func (s *recordingSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &recordingSink{
+		LogSink: s.LogSink,
+		values:  append(append([]interface{}{}, s.values...), keysAndValues...),
+		lines:   s.lines,
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// dialCheckTimeout bounds each connection attempt of the dial check, so that a
+// blackholed address doesn't use up the poll interval.
+const dialCheckTimeout = 2 * time.Second
+
+// Dialer opens network connections. *net.Dialer implements it.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+var (
+	dialerMu sync.RWMutex
+	dialer   Dialer = &net.Dialer{}
+)
+
+// SetDialer replaces the dialer used to check that the discovered addresses
+// accept connections. A nil dialer restores the default net.Dialer.
+func SetDialer(d Dialer) {
+	dialerMu.Lock()
+	defer dialerMu.Unlock()
+	if d == nil {
+		d = &net.Dialer{}
+	}
+	dialer = d
+}
+
+func getDialer() Dialer {
+	dialerMu.RLock()
+	defer dialerMu.RUnlock()
+	return dialer
+}
+
+// waitForAddressesReachable waits for all of ips to accept TCP connections on
+// the dial port of the discovery config, if any, or to pass the gRPC health
+// check with the probe host as the authority.
+func waitForAddressesReachable(ctx context.Context, logger logr.Logger, ips []string, host string, discoveryConfig *discoveryConfig) error {
+	if discoveryConfig.DialPort == 0 {
+		return nil
+	}
+
+	port := strconv.Itoa(discoveryConfig.DialPort)
+	var lastErr error
+	err := pollProbe(ctx, discoveryConfig, func(ctx context.Context) (bool, error) {
+		for _, ip := range ips {
+			address := net.JoinHostPort(ip, port)
+			if discoveryConfig.GRPCHealthCheck {
+				lastErr = checkGRPCHealth(ctx, address, host, discoveryConfig.GRPCHealthService, discoveryConfig.GRPCHealthTimeout)
+			} else {
+				lastErr = dialAddress(ctx, address)
+			}
+			if lastErr != nil {
+				logger.V(1).Info("The address doesn't accept connections yet", "address", ip, "port", port, "error", lastErr.Error())
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		if lastErr != nil {
+			return errors.Wrapf(err, "failed to wait for the addresses %v to accept connections on port %s, last error: %v", ips, port, lastErr)
+		}
+		return errors.Wrapf(err, "failed to wait for the addresses %v to accept connections on port %s", ips, port)
+	}
+	return nil
+}
+
+func dialAddress(ctx context.Context, address string) error {
+	ctx, cancel := context.WithTimeout(ctx, dialCheckTimeout)
+	defer cancel()
+	conn, err := getDialer().DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
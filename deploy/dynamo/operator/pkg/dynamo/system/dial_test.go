@@ -0,0 +1,122 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// fakeDialer fails the first failures dials, then succeeds.
+type fakeDialer struct {
+	mu        sync.Mutex
+	failures  int
+	addresses []string
+}
+
+func (d *fakeDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.addresses = append(d.addresses, address)
+	if len(d.addresses) <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
+
+func TestGetIngressIPDialCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialPort  string
+		failures  int
+		wantDials int
+		wantErr   bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:      "reachable after retries",
+			dialPort:  "8080",
+			failures:  2,
+			wantDials: 3,
+		},
+		{
+			name:     "never reachable",
+			dialPort: "8080",
+			failures: 1 << 30,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer := &fakeDialer{failures: tt.failures}
+			SetDialer(dialer)
+			defer SetDialer(nil)
+
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort:        tt.dialPort,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:     "100ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIngressIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if ip != "10.0.0.1" {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, "10.0.0.1")
+			}
+			if len(dialer.addresses) != tt.wantDials {
+				t.Fatalf("dialed %d times, want %d", len(dialer.addresses), tt.wantDials)
+			}
+			for _, address := range dialer.addresses {
+				if address != "10.0.0.1:8080" {
+					t.Errorf("dialed %s, want 10.0.0.1:8080", address)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDiscoveryConfigDialPort(t *testing.T) {
+	for _, port := range []string{"-1", "65536", "http"} {
+		configMap := newNetworkConfigMap(map[string]string{
+			consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort: port,
+		})
+		if _, err := parseDiscoveryConfig(configMap); err == nil {
+			t.Errorf("parseDiscoveryConfig() with dial port %s succeeded, want an error", port)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// ParseMagicDNSSuffix extracts the IP embedded in a magic DNS domain suffix such
+// as `10.0.0.1.sslip.io`, `app.10.0.0.1.sslip.io` or `10-0-0-1.sslip.io`. It
+// returns false if the suffix isn't under the magic DNS domain or embeds no IP.
+func ParseMagicDNSSuffix(domainSuffix, magicDNS string) (ip string, ok bool) {
+	domainSuffix = strings.TrimSuffix(strings.TrimSpace(domainSuffix), ".")
+	magicDNS = strings.Trim(strings.TrimSpace(magicDNS), ".")
+
+	prefix, found := strings.CutSuffix(domainSuffix, "."+magicDNS)
+	if !found || prefix == "" {
+		return "", false
+	}
+
+	// Dotted IPv4 form: the IP is the last four labels of the prefix.
+	labels := strings.Split(prefix, ".")
+	if len(labels) >= 4 {
+		candidate := strings.Join(labels[len(labels)-4:], ".")
+		if parsed := net.ParseIP(candidate); parsed != nil {
+			return parsed.String(), true
+		}
+	}
+
+	// Dashed form: the IP is the last label with dashes instead of dots (IPv4)
+	// or colons (IPv6), possibly after a name and a dash.
+	last := labels[len(labels)-1]
+	for _, candidate := range []string{last, last[strings.Index(last, "-")+1:]} {
+		if parsed := net.ParseIP(strings.ReplaceAll(candidate, "-", ".")); parsed != nil {
+			return parsed.String(), true
+		}
+		if parsed := net.ParseIP(strings.ReplaceAll(candidate, "-", ":")); parsed != nil {
+			return parsed.String(), true
+		}
+	}
+
+	return "", false
+}
+
+// IsMagicDNSSuffix reports whether the domain suffix is one generated with the
+// magic DNS domain or, if set, the magic DNS template, from an embedded IP.
+func IsMagicDNSSuffix(domainSuffix, magicDNS, template string) bool {
+	if template == "" {
+		_, ok := ParseMagicDNSSuffix(domainSuffix, magicDNS)
+		return ok
+	}
+	prefix, suffix, _ := strings.Cut(template, "%s")
+	label, found := strings.CutPrefix(strings.TrimSuffix(strings.TrimSpace(domainSuffix), "."), prefix)
+	if !found {
+		return false
+	}
+	label, found = strings.CutSuffix(label, suffix)
+	if !found {
+		return false
+	}
+	return net.ParseIP(label) != nil || net.ParseIP(strings.ReplaceAll(label, "-", ".")) != nil || net.ParseIP(strings.ReplaceAll(label, "-", ":")) != nil
+}
+
+// ComposeMagicDNSSuffix builds the magic DNS domain suffix of the IP. IPv4
+// addresses are used as is, IPv6 addresses can't be bracketed in a DNS name and
+// use the dashed form, e.g. `2001-db8--1.sslip.io`, which ParseMagicDNSSuffix
+// reads back.
+func ComposeMagicDNSSuffix(ip, magicDNS string) string {
+	return formatDomainSuffix(magicDNSLabel(ip), magicDNS)
+}
+
+// DomainSuffixFormatter joins the label an IP is embedded into a magic DNS domain
+// suffix with and the magic DNS, for the magic DNS providers with other joining
+// rules than DefaultDomainSuffixFormatter. The magic DNS is the magic DNS
+// template of the network config when one is set.
+type DomainSuffixFormatter func(label, magicDNS string) string
+
+// DefaultDomainSuffixFormatter joins the label and the magic DNS with a dot, e.g.
+// `10.0.0.1.sslip.io`, or interpolates the label into a magic DNS template such
+// as `%s.nip.io`.
+func DefaultDomainSuffixFormatter(label, magicDNS string) string {
+	if strings.Contains(magicDNS, "%s") {
+		return fmt.Sprintf(magicDNS, label)
+	}
+	return label + "." + magicDNS
+}
+
+var (
+	domainSuffixFormatterMu sync.RWMutex
+	domainSuffixFormatter   DomainSuffixFormatter = DefaultDomainSuffixFormatter
+)
+
+// SetDomainSuffixFormatter replaces the formatter the magic DNS domain suffixes
+// are composed with. A nil formatter restores DefaultDomainSuffixFormatter. The
+// suffixes are only read back, e.g. by ParseMagicDNSSuffix, if they end with the
+// magic DNS after a dot.
+func SetDomainSuffixFormatter(f DomainSuffixFormatter) {
+	domainSuffixFormatterMu.Lock()
+	defer domainSuffixFormatterMu.Unlock()
+	if f == nil {
+		f = DefaultDomainSuffixFormatter
+	}
+	domainSuffixFormatter = f
+}
+
+func formatDomainSuffix(label, magicDNS string) string {
+	domainSuffixFormatterMu.RLock()
+	f := domainSuffixFormatter
+	domainSuffixFormatterMu.RUnlock()
+	return f(label, magicDNS)
+}
+
+// ComposeMagicDNSTemplate interpolates the IP, in the form of ComposeMagicDNSSuffix,
+// into a magic DNS template such as `%s.nip.io`.
+func ComposeMagicDNSTemplate(ip, template string) string {
+	return formatDomainSuffix(magicDNSLabel(ip), template)
+}
+
+// MagicDNSIPFormat is how an IPv4 address is embedded into a magic DNS domain
+// suffix. IPv6 addresses are always dashed.
+type MagicDNSIPFormat string
+
+const (
+	// MagicDNSIPFormatDotted embeds the IP as is, e.g. `10.0.0.1.sslip.io`.
+	MagicDNSIPFormatDotted MagicDNSIPFormat = "dotted"
+	// MagicDNSIPFormatDashed embeds the IP as a single label, e.g.
+	// `10-0-0-1.sslip.io`, for the magic DNS providers that require it.
+	MagicDNSIPFormatDashed MagicDNSIPFormat = "dashed"
+)
+
+// ComposeMagicDNSLabel returns the IP in the format, as the labels it is
+// embedded into a magic DNS domain suffix with, checking that they are valid DNS
+// labels.
+func ComposeMagicDNSLabel(ip string, format MagicDNSIPFormat) (string, error) {
+	label := magicDNSLabel(ip)
+	if format == MagicDNSIPFormatDashed {
+		label = strings.ReplaceAll(label, ".", "-")
+	}
+	for _, l := range strings.Split(label, ".") {
+		if errs := validation.IsDNS1123Label(l); len(errs) > 0 {
+			return "", errors.Errorf("the IP %s in the %s format isn't a valid DNS label: %s", ip, format, strings.Join(errs, ", "))
+		}
+	}
+	return label, nil
+}
+
+// validateMagicDNSTemplate checks that the template has exactly one %s and no
+// other formatting verb.
+func validateMagicDNSTemplate(template string) error {
+	if strings.Count(template, "%s") != 1 || strings.Count(template, "%") != 1 {
+		return errors.Errorf("%q must contain exactly one %%s and no other %% verb", template)
+	}
+	return nil
+}
+
+func magicDNSLabel(ip string) string {
+	parsed := net.ParseIP(strings.Trim(ip, "[]"))
+	if parsed == nil || parsed.To4() != nil {
+		return ip
+	}
+	label := parsed.String()
+	// A label must neither start nor end with a dash.
+	if strings.HasPrefix(label, ":") {
+		label = "0" + label
+	}
+	if strings.HasSuffix(label, ":") {
+		label += "0"
+	}
+	return strings.ReplaceAll(label, ":", "-")
+}
+
+// DomainSuffixMismatchError is returned when a magic DNS domain suffix embeds
+// another IP than the one of the ingress load balancer.
+type DomainSuffixMismatchError struct {
+	DomainSuffix string
+	SuffixIP     string
+	DiscoveredIP string
+}
+
+func (e *DomainSuffixMismatchError) Error() string {
+	return fmt.Sprintf("the domain suffix %s points to %s but the ingress load balancer IP is %s", e.DomainSuffix, e.SuffixIP, e.DiscoveredIP)
+}
+
+// CheckDomainSuffixIP returns a *DomainSuffixMismatchError if the domain suffix is
+// a magic DNS suffix embedding another IP than discoveredIP. Suffixes that are not
+// magic DNS suffixes can't be checked and are accepted.
+func CheckDomainSuffixIP(domainSuffix, magicDNS, discoveredIP string) error {
+	suffixIP, ok := ParseMagicDNSSuffix(domainSuffix, magicDNS)
+	if !ok {
+		return nil
+	}
+	if parsed := net.ParseIP(discoveredIP); parsed != nil && parsed.String() == suffixIP {
+		return nil
+	}
+	return &DomainSuffixMismatchError{
+		DomainSuffix: domainSuffix,
+		SuffixIP:     suffixIP,
+		DiscoveredIP: discoveredIP,
+	}
+}
+
+// VerifyDomainSuffix compares the IP embedded in the domain suffix set in the
+// network config with the freshly discovered ingress IP, to catch typos in
+// statically provided magic DNS suffixes. A mismatch is logged as a warning and
+// returned as a *DomainSuffixMismatchError.
+func VerifyDomainSuffix(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) error {
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
+	}
+
+	domainSuffix := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix])
+	if domainSuffix == "" {
+		return nil
+	}
+
+	magicDNS := GetMagicDNS()
+	if _, ok := ParseMagicDNSSuffix(domainSuffix, magicDNS); !ok {
+		return nil
+	}
+
+	ip, err := GetIngressIP(ctx, configmapGetter, cliset)
+	if err != nil {
+		return errors.Wrap(err, "failed to discover the ingress IP")
+	}
+
+	if err = CheckDomainSuffixIP(domainSuffix, magicDNS, ip); err != nil {
+		contextLogger(ctx).Error(err, "The domain suffix of the network config does not match the ingress", "key", consts.KubeConfigMapKeyNetworkConfigDomainSuffix)
+		return err
+	}
+	return nil
+}
+
+// domainSuffixVerificationLabel is the label under the configured domain suffix
+// that is resolved to verify it, which a wildcard record must cover.
+const domainSuffixVerificationLabel = "probe"
+
+// verifyDomainSuffix checks that a host under the domain suffix resolves, e.g.
+// that the wildcard DNS record of a configured domain suffix exists.
+func verifyDomainSuffix(ctx context.Context, domainSuffix string) error {
+	host := joinDomain(domainSuffixVerificationLabel, domainSuffix)
+	ipAddrs, err := getResolver().LookupIPAddr(ctx, host)
+	if err == nil && len(ipAddrs) == 0 {
+		err = errors.New("no addresses")
+	}
+	if err != nil {
+		return errors.Wrapf(newResolveError(host, err), "failed to verify the domain suffix %s set in the network config", domainSuffix)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestComposeMagicDNSSuffix(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{name: "ipv4", ip: "10.0.0.1", want: "10.0.0.1.sslip.io"},
+		{name: "ipv6", ip: "2001:db8::1", want: "2001-db8--1.sslip.io"},
+		{name: "bracketed ipv6", ip: "[2001:db8::1]", want: "2001-db8--1.sslip.io"},
+		{name: "ipv6 with a leading zero group", ip: "::1", want: "0--1.sslip.io"},
+		{name: "ipv6 with a trailing zero group", ip: "fe80::", want: "fe80--0.sslip.io"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComposeMagicDNSSuffix(tt.ip, "sslip.io")
+			if got != tt.want {
+				t.Fatalf("ComposeMagicDNSSuffix(%q) = %q, want %q", tt.ip, got, tt.want)
+			}
+			ip, ok := ParseMagicDNSSuffix(got, "sslip.io")
+			if !ok || CheckDomainSuffixIP(got, "sslip.io", ip) != nil {
+				t.Errorf("ParseMagicDNSSuffix(%q) = %q, %v, want the IP back", got, ip, ok)
+			}
+		})
+	}
+}
+
+func TestParseDiscoveryConfigMagicDNSTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "unset", template: ""},
+		{name: "nip.io", template: "%s.nip.io"},
+		{name: "internal resolver", template: "ip-%s.dns.corp.internal"},
+		{name: "no verb", template: "nip.io", wantErr: true},
+		{name: "two verbs", template: "%s.%s.nip.io", wantErr: true},
+		{name: "other verb", template: "%s.%d.nip.io", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate: tt.template,
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDiscoveryConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && config.MagicDNSTemplate != tt.template {
+				t.Errorf("MagicDNSTemplate = %q, want %q", config.MagicDNSTemplate, tt.template)
+			}
+		})
+	}
+}
+
+func TestGetDomainSuffixMagicDNSTemplate(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate:         "ip-%s.dns.corp.internal",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "2001:db8::1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+	if want := "ip-2001-db8--1.dns.corp.internal"; domainSuffix != want {
+		t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, want)
+	}
+}
+
+func TestGetDomainSuffixMagicDNSIPFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		template string
+		ip       string
+		want     string
+		wantErr  bool
+	}{
+		{format: "", ip: "10.0.0.1", want: "10.0.0.1.sslip.io"},
+		{format: "dotted", ip: "10.0.0.1", want: "10.0.0.1.sslip.io"},
+		{format: "Dashed", ip: "10.0.0.1", want: "10-0-0-1.sslip.io"},
+		{format: "dashed", template: "ip-%s.nip.io", ip: "10.0.0.1", want: "ip-10-0-0-1.nip.io"},
+		{format: "dotted", ip: "2001:db8::1", want: "2001-db8--1.sslip.io"},
+		{format: "dashed", ip: "2001:db8::1", want: "2001-db8--1.sslip.io"},
+		{format: "hex", ip: "10.0.0.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.template+"/"+tt.ip, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigMagicDNSIPFormat:      tt.format,
+				consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate:      tt.template,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: tt.ip})
+			if err := cliset.Tracker().Add(configMap); err != nil {
+				t.Fatalf("failed to add the network configmap: %v", err)
+			}
+
+			domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDomainSuffix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if domainSuffix != tt.want {
+				t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, tt.want)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !IsMagicDNSSuffix(domainSuffix, "sslip.io", tt.template) {
+				t.Errorf("IsMagicDNSSuffix(%q) = false, want true", domainSuffix)
+			}
+			if ip, ok := ParseMagicDNSSuffix(domainSuffix, "sslip.io"); tt.template == "" && (!ok || ip != tt.ip) {
+				t.Errorf("ParseMagicDNSSuffix(%q) = %q, %v, want %s", domainSuffix, ip, ok, tt.ip)
+			}
+		})
+	}
+}
+
+func TestGetDomainSuffixFormatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter DomainSuffixFormatter
+		want      string
+		wantErr   bool
+	}{
+		{name: "default", want: "10.0.0.1.sslip.io"},
+		{
+			name:      "dashed",
+			formatter: func(label, magicDNS string) string { return label + "-" + magicDNS },
+			want:      "10.0.0.1-sslip.io",
+		},
+		{
+			name:      "invalid DNS name",
+			formatter: func(label, magicDNS string) string { return label + "_" + magicDNS },
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDomainSuffixFormatter(tt.formatter)
+			defer SetDomainSuffixFormatter(nil)
+
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+			if err := cliset.Tracker().Add(configMap); err != nil {
+				t.Fatalf("failed to add the network configmap: %v", err)
+			}
+
+			domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDomainSuffix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if domainSuffix != tt.want {
+				t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, tt.want)
+			}
+			if got := ComposeMagicDNSSuffix("10.0.0.1", "sslip.io"); !tt.wantErr && got != tt.want {
+				t.Errorf("ComposeMagicDNSSuffix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposeMagicDNSLabel(t *testing.T) {
+	for _, format := range []MagicDNSIPFormat{MagicDNSIPFormatDotted, MagicDNSIPFormatDashed} {
+		if _, err := ComposeMagicDNSLabel("lb_1", format); err == nil {
+			t.Errorf("ComposeMagicDNSLabel(%q, %s) error = nil, want an invalid label", "lb_1", format)
+		}
+	}
+}
+
+func TestGetDomainSuffixVerifiesConfiguredSuffix(t *testing.T) {
+	SetResolver(fakeResolver{"probe.apps.example.com": {"10.0.0.1"}})
+	defer SetResolver(nil)
+
+	tests := []struct {
+		name         string
+		domainSuffix string
+		verify       string
+		wantErr      bool
+	}{
+		{name: "resolves", domainSuffix: "apps.example.com", verify: "true"},
+		{name: "nxdomain", domainSuffix: "missing.example.com", verify: "true", wantErr: true},
+		{name: "not verified", domainSuffix: "missing.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix:                tt.domainSuffix,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryVerifyDomainSuffix: tt.verify,
+			})
+
+			domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), fake.NewSimpleClientset())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDomainSuffix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var resolveErr *ResolveError
+				if !errors.As(err, &resolveErr) || resolveErr.Hostname != "probe."+tt.domainSuffix {
+					t.Errorf("GetDomainSuffix() error = %v, want a *ResolveError of probe.%s", err, tt.domainSuffix)
+				}
+				return
+			}
+			if domainSuffix != tt.domainSuffix {
+				t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, tt.domainSuffix)
+			}
+		})
+	}
+}
+
+func TestVerifyDomainSuffix(t *testing.T) {
+	t.Setenv(MagicDNSEnvKey, "")
+
+	tests := []struct {
+		name         string
+		domainSuffix string
+		wantMismatch bool
+	}{
+		{name: "unset"},
+		{name: "not magic DNS", domainSuffix: "apps.example.com"},
+		{name: "matches", domainSuffix: "10.0.0.9.sslip.io"},
+		{name: "mismatch", domainSuffix: "10.0.0.8.sslip.io", wantMismatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix:             tt.domainSuffix,
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9"})
+
+			err := VerifyDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if !tt.wantMismatch {
+				if err != nil {
+					t.Errorf("VerifyDomainSuffix() error = %v", err)
+				}
+				return
+			}
+			var mismatchErr *DomainSuffixMismatchError
+			if !errors.As(err, &mismatchErr) || mismatchErr.SuffixIP != "10.0.0.8" || mismatchErr.DiscoveredIP != "10.0.0.9" {
+				t.Errorf("VerifyDomainSuffix() error = %v, want a *DomainSuffixMismatchError of 10.0.0.8 and 10.0.0.9", err)
+			}
+		})
+	}
+}
+
+func TestGetDomainSuffixFormatterWithMagicDNSTemplate(t *testing.T) {
+	var formatted []string
+	SetDomainSuffixFormatter(func(label, magicDNS string) string {
+		formatted = append(formatted, magicDNS)
+		return DefaultDomainSuffixFormatter(label, magicDNS)
+	})
+	defer SetDomainSuffixFormatter(nil)
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate:         "ip-%s.dns.corp.internal",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+	if want := "ip-10.0.0.1.dns.corp.internal"; domainSuffix != want {
+		t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, want)
+	}
+	if len(formatted) == 0 || formatted[0] != "ip-%s.dns.corp.internal" {
+		t.Errorf("the formatter was called with %v, want the magic DNS template", formatted)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// cachedDomainSuffix is a domain suffix computed by DetectAndCacheDomainSuffix,
+// or the computation in flight.
+type cachedDomainSuffix struct {
+	done         chan struct{}
+	domainSuffix string
+	err          error
+	expires      time.Time
+}
+
+var (
+	domainSuffixCacheMu  sync.Mutex
+	domainSuffixCache    = make(map[string]*cachedDomainSuffix)
+	domainSuffixCacheTTL time.Duration
+)
+
+// SetDomainSuffixCacheTTL sets how long DetectAndCacheDomainSuffix serves a
+// domain suffix from its cache, zero meaning until InvalidateDomainSuffixCache.
+// The TTL follows the clock set with SetClock.
+func SetDomainSuffixCacheTTL(ttl time.Duration) {
+	domainSuffixCacheMu.Lock()
+	defer domainSuffixCacheMu.Unlock()
+	domainSuffixCacheTTL = ttl
+}
+
+// DetectAndCacheDomainSuffix is GetDomainSuffix memoized per namespace for the
+// process, so that the reconciles don't run the discovery again: the domain
+// suffix is computed once and served from the cache until it expires, see
+// SetDomainSuffixCacheTTL, or InvalidateDomainSuffixCache is called. The
+// concurrent callers of a namespace whose domain suffix isn't cached share a
+// single computation, run with the context of the first one. Errors aren't
+// cached.
+func DetectAndCacheDomainSuffix(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (string, error) {
+	namespace := namespaceFromContext(ctx)
+	now := getClock().Now()
+
+	domainSuffixCacheMu.Lock()
+	cached, ok := domainSuffixCache[namespace]
+	if ok {
+		select {
+		case <-cached.done:
+			if !cached.expires.IsZero() && !now.Before(cached.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if ok {
+		domainSuffixCacheMu.Unlock()
+		<-cached.done
+		return cached.domainSuffix, cached.err
+	}
+	cached = &cachedDomainSuffix{done: make(chan struct{})}
+	domainSuffixCache[namespace] = cached
+	domainSuffixCacheMu.Unlock()
+
+	cached.domainSuffix, cached.err = GetDomainSuffix(ctx, configmapGetter, cliset)
+
+	domainSuffixCacheMu.Lock()
+	if cached.err != nil {
+		if domainSuffixCache[namespace] == cached {
+			delete(domainSuffixCache, namespace)
+		}
+	} else if domainSuffixCacheTTL > 0 {
+		cached.expires = getClock().Now().Add(domainSuffixCacheTTL)
+	}
+	domainSuffixCacheMu.Unlock()
+	close(cached.done)
+	return cached.domainSuffix, cached.err
+}
+
+// InvalidateDomainSuffixCache empties the cache of DetectAndCacheDomainSuffix, so
+// that its next calls compute the domain suffixes again. The computations in
+// flight aren't canceled, but their results aren't cached.
+func InvalidateDomainSuffixCache() {
+	domainSuffixCacheMu.Lock()
+	defer domainSuffixCacheMu.Unlock()
+	domainSuffixCache = make(map[string]*cachedDomainSuffix)
+}
+
+// forgetCachedDomainSuffix removes the domain suffix of the namespace from the
+// cache of DetectAndCacheDomainSuffix.
+func forgetCachedDomainSuffix(namespace string) {
+	domainSuffixCacheMu.Lock()
+	defer domainSuffixCacheMu.Unlock()
+	delete(domainSuffixCache, namespace)
+}
@@ -0,0 +1,141 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestDetectAndCacheDomainSuffixSingleflight(t *testing.T) {
+	InvalidateDomainSuffixCache()
+	defer InvalidateDomainSuffixCache()
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "example.com",
+	})
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	getter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return configMap, nil
+	}
+	cliset := fake.NewSimpleClientset()
+
+	const callers = 10
+	results := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	call := func(i int) {
+		defer wg.Done()
+		results[i], errs[i] = DetectAndCacheDomainSuffix(context.Background(), getter, cliset)
+	}
+	wg.Add(1)
+	go call(0)
+	// The other callers start while the first computation is in flight.
+	<-started
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go call(i)
+	}
+	close(release)
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] != nil || results[i] != "example.com" {
+			t.Errorf("DetectAndCacheDomainSuffix() #%d = %q, %v, want %q", i, results[i], errs[i], "example.com")
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("the domain suffix was computed %d times, want once", calls.Load())
+	}
+
+	if _, err := DetectAndCacheDomainSuffix(context.Background(), getter, cliset); err != nil {
+		t.Fatalf("DetectAndCacheDomainSuffix() error = %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("the cached domain suffix was computed again, %d times", calls.Load())
+	}
+}
+
+func TestDetectAndCacheDomainSuffixTTL(t *testing.T) {
+	InvalidateDomainSuffixCache()
+	defer InvalidateDomainSuffixCache()
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	SetClock(fakeClock)
+	defer SetClock(nil)
+	SetDomainSuffixCacheTTL(time.Minute)
+	defer SetDomainSuffixCacheTTL(0)
+
+	var calls int
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "example.com",
+	})
+	getter := countingConfigMapGetter(configMap, &calls)
+	cliset := fake.NewSimpleClientset()
+	detect := func(wantCalls int) {
+		t.Helper()
+		domainSuffix, err := DetectAndCacheDomainSuffix(context.Background(), getter, cliset)
+		if err != nil || domainSuffix != "example.com" {
+			t.Fatalf("DetectAndCacheDomainSuffix() = %q, %v, want %q", domainSuffix, err, "example.com")
+		}
+		if calls != wantCalls {
+			t.Errorf("the domain suffix was computed %d times, want %d", calls, wantCalls)
+		}
+	}
+
+	detect(1)
+	fakeClock.Step(30 * time.Second)
+	detect(1)
+	fakeClock.Step(time.Minute)
+	detect(2)
+	InvalidateDomainSuffixCache()
+	detect(3)
+}
+
+func TestDetectAndCacheDomainSuffixDoesNotCacheErrors(t *testing.T) {
+	InvalidateDomainSuffixCache()
+	defer InvalidateDomainSuffixCache()
+
+	var calls int
+	getter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		calls++
+		return nil, errors.New("api server unavailable")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := DetectAndCacheDomainSuffix(context.Background(), getter, fake.NewSimpleClientset()); err == nil {
+			t.Fatalf("DetectAndCacheDomainSuffix() error = nil, want the configmap getter one")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("the domain suffix was computed %d times, want the error not cached", calls)
+	}
+}
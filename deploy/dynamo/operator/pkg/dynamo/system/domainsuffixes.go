@@ -0,0 +1,71 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type sharedAddressesKey struct{}
+
+// sharedAddresses holds the ingress addresses discovered once for the namespaces
+// of GetDomainSuffixes.
+type sharedAddresses struct {
+	addresses []IngressAddress
+}
+
+// GetDomainSuffixes returns the domain suffix of each namespace, as
+// GetDomainSuffix does, together with the errors of the namespaces it failed for.
+// The ingress addresses are discovered once, by the first namespace without a
+// configured domain suffix, and the suffixes of the next ones are composed from
+// them rather than probed again.
+func GetDomainSuffixes(ctx context.Context, namespaces []string, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (map[string]string, map[string]error) {
+	ctx = context.WithValue(ctx, sharedAddressesKey{}, &sharedAddresses{})
+	domainSuffixes := make(map[string]string, len(namespaces))
+	errs := make(map[string]error)
+	for _, namespace := range namespaces {
+		domainSuffix, err := GetDomainSuffix(WithNamespace(ctx, namespace), configmapGetter, cliset)
+		if err != nil {
+			errs[namespace] = err
+			continue
+		}
+		domainSuffixes[namespace] = domainSuffix
+	}
+	return domainSuffixes, errs
+}
+
+// discoverSharedIngressAddresses is discoverIngressAddresses, reusing the addresses
+// already discovered by GetDomainSuffixes. A failed discovery isn't shared, so
+// that the next namespace, which may have another network config, tries again.
+func discoverSharedIngressAddresses(ctx context.Context, configMap *corev1.ConfigMap, cliset kubernetes.Interface) ([]IngressAddress, error) {
+	shared, _ := ctx.Value(sharedAddressesKey{}).(*sharedAddresses)
+	if shared != nil && len(shared.addresses) > 0 {
+		return shared.addresses, nil
+	}
+	addresses, err := discoverIngressAddresses(ctx, configMap, cliset)
+	if err != nil {
+		return nil, err
+	}
+	if shared != nil {
+		shared.addresses = addresses
+	}
+	return addresses, nil
+}
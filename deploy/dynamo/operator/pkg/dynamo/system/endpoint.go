@@ -0,0 +1,144 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+const (
+	SchemeHTTP  = "http"
+	SchemeHTTPS = "https"
+
+	DefaultHTTPPort  int32 = 80
+	DefaultHTTPSPort int32 = 443
+)
+
+// ServiceEndpoint is the external endpoint of a service exposed through the
+// ingress, split into its components.
+type ServiceEndpoint struct {
+	Scheme string
+	Host   string
+	Port   int32
+}
+
+// GetServiceEndpoint returns the external endpoint of the service with the given
+// name and namespace under the domain suffix, `<name>.<namespace>.<domainSuffix>`.
+func GetServiceEndpoint(name, namespace, domainSuffix string, tls bool) ServiceEndpoint {
+	endpoint := ServiceEndpoint{
+		Scheme: SchemeHTTP,
+		Host:   joinDomain(name, namespace, domainSuffix),
+		Port:   DefaultHTTPPort,
+	}
+	if tls {
+		endpoint.Scheme = SchemeHTTPS
+		endpoint.Port = DefaultHTTPSPort
+	}
+	return endpoint
+}
+
+// URL formats the endpoint as a URL, omitting the port when it is the default
+// one for the scheme.
+func (e ServiceEndpoint) URL() string {
+	if (e.Scheme == SchemeHTTP && e.Port == DefaultHTTPPort) || (e.Scheme == SchemeHTTPS && e.Port == DefaultHTTPSPort) || e.Port == 0 {
+		return fmt.Sprintf("%s://%s", e.Scheme, e.Host)
+	}
+	return fmt.Sprintf("%s://%s", e.Scheme, net.JoinHostPort(e.Host, strconv.Itoa(int(e.Port))))
+}
+
+// BuildServiceURL returns the external URL of the service with the given name and
+// namespace under the domain suffix, `https://<name>.<namespace>.<domainSuffix>`
+// with tls. The stray dots of the components are dropped, and it returns "" if
+// the host isn't a valid DNS subdomain.
+func BuildServiceURL(name, namespace, domainSuffix string, tls bool) string {
+	endpoint := GetServiceEndpoint(name, namespace, domainSuffix, tls)
+	if len(validation.IsDNS1123Subdomain(endpoint.Host)) > 0 {
+		return ""
+	}
+	return endpoint.URL()
+}
+
+// BuildServiceURLFromConfig is BuildServiceURL under the domain suffix of the
+// network config, with the scheme its ingress is served with: https if
+// tlsSecretName, the TLS secret of the DynamoNimDeployment, or a cert-manager
+// issuer is set; otherwise if the TLS config covers the host or, without a TLS
+// config, if the TLS mode is auto or static. It fails if the TLS config doesn't
+// cover the host, which would otherwise be served without TLS unnoticed.
+func BuildServiceURLFromConfig(name, namespace string, cfg *NetworkConfig, tlsSecretName string) (string, error) {
+	if cfg.DomainSuffix == "" {
+		return "", errors.Errorf("no %s is set in the network config", consts.KubeConfigMapKeyNetworkConfigDomainSuffix)
+	}
+	host := joinDomain(name, namespace, cfg.DomainSuffix)
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+		return "", errors.Errorf("the host %q of the service isn't valid: %s", host, strings.Join(errs, ", "))
+	}
+
+	var tls bool
+	switch {
+	case tlsSecretName != "" || (cfg.Ingress != nil && cfg.Ingress.CertManagerIssuer != ""):
+		tls = true
+	case len(cfg.TLS) > 0:
+		if !tlsConfigCovers(cfg.TLS, host) {
+			var hosts []string
+			for _, t := range cfg.TLS {
+				hosts = append(hosts, t.Hosts...)
+			}
+			return "", errors.Errorf("the host %s of the service isn't covered by the TLS hosts %s", host, strings.Join(hosts, ", "))
+		}
+		tls = true
+	default:
+		tls = cfg.TLSMode == "auto" || cfg.TLSMode == "static"
+	}
+	return GetServiceEndpoint(name, namespace, cfg.DomainSuffix, tls).URL(), nil
+}
+
+// tlsConfigCovers reports whether the TLS config covers the host. An entry
+// without hosts, served with the default certificate of the controller, covers
+// them all.
+func tlsConfigCovers(tls []IngressTLSConfig, host string) bool {
+	for _, t := range tls {
+		if len(t.Hosts) == 0 {
+			return true
+		}
+		for _, tlsHost := range t.Hosts {
+			if tlsHostCovers(tlsHost, host) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func joinDomain(labels ...string) string {
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.Trim(strings.TrimSpace(label), ".")
+		if label != "" {
+			parts = append(parts, label)
+		}
+	}
+	return strings.Join(parts, ".")
+}
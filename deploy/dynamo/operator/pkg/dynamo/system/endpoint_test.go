@@ -0,0 +1,164 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildServiceURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		service      string
+		namespace    string
+		domainSuffix string
+		tls          bool
+		want         string
+	}{
+		{name: "http", service: "my-service", namespace: "team-a", domainSuffix: "example.com", want: "http://my-service.team-a.example.com"},
+		{name: "https", service: "my-service", namespace: "team-a", domainSuffix: "example.com", tls: true, want: "https://my-service.team-a.example.com"},
+		{name: "leading dot", service: "my-service", namespace: "team-a", domainSuffix: ".example.com", want: "http://my-service.team-a.example.com"},
+		{name: "trailing dot", service: "my-service", namespace: "team-a", domainSuffix: "example.com.", tls: true, want: "https://my-service.team-a.example.com"},
+		{name: "invalid host", service: "My_Service", namespace: "team-a", domainSuffix: "example.com", want: ""},
+		{name: "empty", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildServiceURL(tt.service, tt.namespace, tt.domainSuffix, tt.tls); got != tt.want {
+				t.Errorf("BuildServiceURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildServiceURLFromConfig(t *testing.T) {
+	tests := []struct {
+		name              string
+		domainSuffix      string
+		tls               []IngressTLSConfig
+		tlsMode           string
+		certManagerIssuer string
+		tlsSecretName     string
+		want              string
+		wantErr           string
+	}{
+		{name: "no TLS", domainSuffix: "example.com", want: "http://my-service.team-a.example.com"},
+		{
+			name:         "TLS covered",
+			domainSuffix: "example.com",
+			tls:          []IngressTLSConfig{{SecretName: "cert", Hosts: []string{"my-service.team-a.example.com"}}},
+			want:         "https://my-service.team-a.example.com",
+		},
+		{
+			name:         "TLS covered by a wildcard",
+			domainSuffix: "example.com",
+			tls:          []IngressTLSConfig{{SecretName: "other", Hosts: []string{"other.example.com"}}, {SecretName: "wildcard", Hosts: []string{"*.team-a.example.com"}}},
+			want:         "https://my-service.team-a.example.com",
+		},
+		{
+			name:         "TLS without hosts",
+			domainSuffix: "example.com",
+			tls:          []IngressTLSConfig{{SecretName: "default"}},
+			want:         "https://my-service.team-a.example.com",
+		},
+		{
+			name:         "TLS uncovered",
+			domainSuffix: "example.com",
+			tls:          []IngressTLSConfig{{SecretName: "cert", Hosts: []string{"*.example.com"}}},
+			wantErr:      "the host my-service.team-a.example.com of the service isn't covered by the TLS hosts *.example.com",
+		},
+		{name: "TLS mode auto", domainSuffix: "example.com", tlsMode: "auto", want: "https://my-service.team-a.example.com"},
+		{name: "TLS mode static", domainSuffix: "example.com", tlsMode: "static", want: "https://my-service.team-a.example.com"},
+		{name: "TLS mode none", domainSuffix: "example.com", tlsMode: "none", want: "http://my-service.team-a.example.com"},
+		{name: "cert-manager", domainSuffix: "example.com", certManagerIssuer: "letsencrypt", want: "https://my-service.team-a.example.com"},
+		{
+			name:          "TLS secret overriding an uncovering TLS config",
+			domainSuffix:  "example.com",
+			tls:           []IngressTLSConfig{{SecretName: "cert", Hosts: []string{"*.example.com"}}},
+			tlsSecretName: "my-cert",
+			want:          "https://my-service.team-a.example.com",
+		},
+		{name: "no domain suffix", wantErr: "no domain-suffix is set in the network config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &NetworkConfig{
+				Ingress:      &IngressConfig{CertManagerIssuer: tt.certManagerIssuer},
+				DomainSuffix: tt.domainSuffix,
+				TLS:          tt.tls,
+				TLSMode:      tt.tlsMode,
+			}
+			got, err := BuildServiceURLFromConfig("my-service", "team-a", cfg, tt.tlsSecretName)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("BuildServiceURLFromConfig() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildServiceURLFromConfig() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildServiceURLFromConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetServiceEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		tls  bool
+		want ServiceEndpoint
+	}{
+		{name: "http", want: ServiceEndpoint{Scheme: SchemeHTTP, Host: "my-service.team-a.example.com", Port: DefaultHTTPPort}},
+		{name: "https", tls: true, want: ServiceEndpoint{Scheme: SchemeHTTPS, Host: "my-service.team-a.example.com", Port: DefaultHTTPSPort}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetServiceEndpoint("my-service", "team-a", "example.com", tt.tls); got != tt.want {
+				t.Errorf("GetServiceEndpoint() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceEndpointURL(t *testing.T) {
+	tests := []struct {
+		endpoint ServiceEndpoint
+		want     string
+	}{
+		{endpoint: ServiceEndpoint{Scheme: SchemeHTTP, Host: "example.com", Port: DefaultHTTPPort}, want: "http://example.com"},
+		{endpoint: ServiceEndpoint{Scheme: SchemeHTTPS, Host: "example.com", Port: DefaultHTTPSPort}, want: "https://example.com"},
+		{endpoint: ServiceEndpoint{Scheme: SchemeHTTP, Host: "example.com"}, want: "http://example.com"},
+		{endpoint: ServiceEndpoint{Scheme: SchemeHTTPS, Host: "example.com", Port: 8443}, want: "https://example.com:8443"},
+		{endpoint: ServiceEndpoint{Scheme: SchemeHTTP, Host: "example.com", Port: DefaultHTTPSPort}, want: "http://example.com:443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.endpoint.URL(); got != tt.want {
+				t.Errorf("URL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
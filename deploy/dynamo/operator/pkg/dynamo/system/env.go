@@ -18,10 +18,18 @@
 package system
 
 import (
+	"context"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
 )
 
 const (
@@ -34,31 +42,170 @@ const (
 	DefaultNamespace = "yatai-deployment"
 	MagicDNSEnvKey   = "MAGIC_DNS"
 	DefaultMagicDNS  = "sslip.io"
+	// DomainSuffixEnvKey is the environment variable that forces the domain
+	// suffix, e.g. in local development and CI, over the network config and the
+	// discovery.
+	DomainSuffixEnvKey = "DYNAMO_DOMAIN_SUFFIX"
 )
 
 var (
 	once sync.Once
+
+	// serviceAccountNamespaceFile is the file the namespace of the pod is read
+	// from when NamespaceEnvKey isn't set, as client-go does in-cluster. It is a
+	// variable so that tests can override it.
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 )
 
 // GetNamespace returns the name of the K8s namespace where our system components
-// run.
+// run: the NamespaceEnvKey environment variable, then the namespace of the
+// service account of the pod, then DefaultNamespace.
 func GetNamespace() string {
 	if ns := os.Getenv(NamespaceEnvKey); ns != "" {
 		return ns
 	}
 
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+
 	once.Do(func() {
-		logrus.Infof("%s environment variable not set, using default namespace %s", NamespaceEnvKey, DefaultNamespace)
+		logrus.Infof("%s environment variable not set and not running in a pod, using default namespace %s", NamespaceEnvKey, DefaultNamespace)
 	})
 	return DefaultNamespace
 }
 
+type namespaceKey struct{}
+
+// WithNamespace returns a context that makes the discovery read the network config
+// of, and create its probe ingress in, the namespace instead of the system one.
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// namespaceFromContext returns the namespace carried by the context, or the
+// system namespace.
+func namespaceFromContext(ctx context.Context) string {
+	if namespace, _ := ctx.Value(namespaceKey{}).(string); namespace != "" {
+		return namespace
+	}
+	return GetNamespace()
+}
+
+type networkConfigNameKey struct{}
+
+// WithNetworkConfigName returns a context that makes the discovery read the
+// network configmap name instead of consts.KubeConfigMapNameNetworkConfig, so
+// that several operators can each have their own network config in a namespace.
+func WithNetworkConfigName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, networkConfigNameKey{}, name)
+}
+
+// networkConfigNameFromContext returns the network configmap name carried by the
+// context, or consts.KubeConfigMapNameNetworkConfig.
+func networkConfigNameFromContext(ctx context.Context) string {
+	if name, _ := ctx.Value(networkConfigNameKey{}).(string); name != "" {
+		return name
+	}
+	return consts.KubeConfigMapNameNetworkConfig
+}
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context that makes GetDomainSuffix return the domain suffix
+// it discovers without persisting it nor acting on it: the network configmap
+// isn't patched, and neither the ExternalName service, the post discovery hooks
+// nor the subscribers are run.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+func dryRunFromContext(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+type forceRediscoveryKey struct{}
+
+// WithForceRediscovery returns a context that makes GetDomainSuffix discover the
+// domain suffix even if the network config already has one, e.g. after the load
+// balancer IP changed, and replace it if it differs.
+func WithForceRediscovery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceRediscoveryKey{}, true)
+}
+
+func forceRediscoveryFromContext(ctx context.Context) bool {
+	force, _ := ctx.Value(forceRediscoveryKey{}).(bool)
+	return force
+}
+
+type patchOutKey struct{}
+
+// withPatchOut returns a dry run context that makes GetDomainSuffix store the
+// merge patch persisting the domain suffix it discovers into patch.
+func withPatchOut(ctx context.Context, patch *[]byte) context.Context {
+	return context.WithValue(WithDryRun(ctx), patchOutKey{}, patch)
+}
+
+func patchOutFromContext(ctx context.Context) *[]byte {
+	patch, _ := ctx.Value(patchOutKey{}).(*[]byte)
+	return patch
+}
+
+// ProgressFunc is called before each poll of a discovery, with the time elapsed
+// since the polls started and the number of the poll, from 1.
+type ProgressFunc func(elapsed time.Duration, attempt int)
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes the discovery call onProgress before
+// each poll, e.g. to show how long it has been waiting for the load balancer.
+// The waits with a watch, see discovery-watch, aren't polls.
+func WithProgress(ctx context.Context, onProgress ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, onProgress)
+}
+
+func progressFromContext(ctx context.Context) ProgressFunc {
+	onProgress, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return onProgress
+}
+
+type probeOwnerKey struct{}
+
+// WithProbeOwner returns a context that makes the discovery set the owner
+// reference on the probe ingress it creates, so that deleting the owner makes
+// the K8s garbage collector delete a leaked probe ingress. The owner must be
+// cluster scoped or in the namespace of the probe ingress.
+func WithProbeOwner(ctx context.Context, owner metav1.OwnerReference) context.Context {
+	return context.WithValue(ctx, probeOwnerKey{}, owner)
+}
+
+func probeOwnerFromContext(ctx context.Context) (owner metav1.OwnerReference, ok bool) {
+	owner, ok = ctx.Value(probeOwnerKey{}).(metav1.OwnerReference)
+	return
+}
+
 // GetResourceLabel returns the label key identifying K8s objects our system
 // components source their configuration from.
 func GetResourceLabel() string {
 	return os.Getenv(ResourceLabelEnvKey)
 }
 
+// getDomainSuffixOverride returns the domain suffix forced by DomainSuffixEnvKey,
+// or "" if it isn't set.
+func getDomainSuffixOverride() (string, error) {
+	domainSuffix := strings.TrimSuffix(strings.TrimSpace(os.Getenv(DomainSuffixEnvKey)), ".")
+	if domainSuffix == "" {
+		return "", nil
+	}
+	if errs := validation.IsDNS1123Subdomain(domainSuffix); len(errs) > 0 {
+		return "", errors.Errorf("invalid %s environment variable %q: %s", DomainSuffixEnvKey, domainSuffix, strings.Join(errs, ", "))
+	}
+	return domainSuffix, nil
+}
+
 func GetMagicDNS() string {
 	magicDNS := os.Getenv(MagicDNSEnvKey)
 	if magicDNS == "" {
@@ -0,0 +1,70 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetNamespace(t *testing.T) {
+	dir := t.TempDir()
+	saFile := filepath.Join(dir, "namespace")
+	missingFile := filepath.Join(dir, "missing")
+	if err := os.WriteFile(saFile, []byte("pod-namespace\n"), 0o600); err != nil {
+		t.Fatalf("failed to write the service account namespace file: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		env    string
+		saFile string
+		want   string
+	}{
+		{
+			name:   "environment variable",
+			env:    "env-namespace",
+			saFile: saFile,
+			want:   "env-namespace",
+		},
+		{
+			name:   "service account file",
+			saFile: saFile,
+			want:   "pod-namespace",
+		},
+		{
+			name:   "default",
+			saFile: missingFile,
+			want:   DefaultNamespace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(NamespaceEnvKey, tt.env)
+			previous := serviceAccountNamespaceFile
+			serviceAccountNamespaceFile = tt.saFile
+			defer func() { serviceAccountNamespaceFile = previous }()
+
+			if got := GetNamespace(); got != tt.want {
+				t.Errorf("GetNamespace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
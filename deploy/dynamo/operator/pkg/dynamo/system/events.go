@@ -0,0 +1,127 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+)
+
+// The reasons of the events recorded on the network configmap during the domain
+// suffix discovery.
+const (
+	EventReasonCreatingProbeIngress   = "CreatingProbeIngress"
+	EventReasonWaitingForLoadBalancer = "WaitingForLoadBalancer"
+	EventReasonDomainSuffixDetected   = "DomainSuffixDetected"
+	EventReasonDomainSuffixTimeout    = "DomainSuffixDetectionTimeout"
+	EventReasonMagicDNSDomainSuffix   = "MagicDNSDomainSuffix"
+)
+
+var (
+	eventRecorderMu sync.RWMutex
+	eventRecorder   record.EventRecorder
+)
+
+// SetEventRecorder sets the recorder of the discovery progress events, which are
+// recorded on the network configmap. No events are recorded without one.
+func SetEventRecorder(recorder record.EventRecorder) {
+	eventRecorderMu.Lock()
+	defer eventRecorderMu.Unlock()
+	eventRecorder = recorder
+}
+
+func recordEventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	eventRecorderMu.RLock()
+	recorder := eventRecorder
+	eventRecorderMu.RUnlock()
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+const (
+	// probeIngressEventLimit bounds the number of events listed for the probe
+	// ingress, and probeIngressEventTimeout how long listing them may take.
+	probeIngressEventLimit   = 50
+	probeIngressEventTimeout = 10 * time.Second
+)
+
+// describeProbeIngressEvent returns the most recent event of the probe ingress,
+// preferring warnings, in a form that can be appended to an error message. It
+// returns an empty string when there is no event or they can't be listed.
+func describeProbeIngressEvent(ctx context.Context, cliset kubernetes.Interface, namespace, name string) string {
+	ctx, cancel := context.WithTimeout(ctx, probeIngressEventTimeout)
+	defer cancel()
+
+	events, err := cliset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": "Ingress",
+			"involvedObject.name": name,
+		}.String(),
+		Limit: probeIngressEventLimit,
+	})
+	if err != nil {
+		contextLogger(ctx).Error(err, "Failed to list the events of the probe ingress", "ingress", name)
+		return ""
+	}
+
+	var latest *corev1.Event
+	for i := range events.Items {
+		event := &events.Items[i]
+		if latest == nil {
+			latest = event
+			continue
+		}
+		latestIsWarning := latest.Type == corev1.EventTypeWarning
+		isWarning := event.Type == corev1.EventTypeWarning
+		if isWarning != latestIsWarning {
+			if isWarning {
+				latest = event
+			}
+			continue
+		}
+		if eventTime(event).After(eventTime(latest)) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s: %s", latest.Type, latest.Reason, latest.Message)
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
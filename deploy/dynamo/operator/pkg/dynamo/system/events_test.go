@@ -0,0 +1,160 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestGetDomainSuffixRecordsEvents(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	SetEventRecorder(recorder)
+	defer SetEventRecorder(nil)
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+
+	events := drainEvents(recorder)
+	wantPrefixes := []string{
+		"Normal " + EventReasonCreatingProbeIngress + " ",
+		"Normal " + EventReasonWaitingForLoadBalancer + " ",
+		"Normal " + EventReasonDomainSuffixDetected + " Detected the domain suffix " + domainSuffix + " ",
+		"Warning " + EventReasonMagicDNSDomainSuffix + " The domain suffix " + domainSuffix + " ",
+	}
+	if len(events) != len(wantPrefixes) {
+		t.Fatalf("recorded events = %q, want %d events", events, len(wantPrefixes))
+	}
+	for i, prefix := range wantPrefixes {
+		if !strings.HasPrefix(events[i], prefix) {
+			t.Errorf("event %d = %q, want the prefix %q", i, events[i], prefix)
+		}
+	}
+}
+
+func TestGetIngressIPRecordsTimeoutEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	SetEventRecorder(recorder)
+	defer SetEventRecorder(nil)
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:  "50ms",
+	})
+	cliset := fake.NewSimpleClientset(&networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+	})
+
+	if _, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset); err == nil {
+		t.Fatal("GetIngressIP() error = nil, want a timeout")
+	}
+
+	events := drainEvents(recorder)
+	if len(events) == 0 || !strings.HasPrefix(events[len(events)-1], "Warning "+EventReasonDomainSuffixTimeout+" ") {
+		t.Errorf("recorded events = %q, want a %s warning last", events, EventReasonDomainSuffixTimeout)
+	}
+}
+
+func TestDescribeProbeIngressEvent(t *testing.T) {
+	now := time.Now()
+	newEvent := func(name, eventType, reason string, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "team-a", Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Ingress", Name: "probe"},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        reason + " message",
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		events []runtime.Object
+		want   string
+	}{
+		{name: "no events"},
+		{
+			name: "latest",
+			events: []runtime.Object{
+				newEvent("a", corev1.EventTypeNormal, "Sync", now.Add(-time.Minute)),
+				newEvent("b", corev1.EventTypeNormal, "Updated", now),
+			},
+			want: "Normal Updated: Updated message",
+		},
+		{
+			name: "warning first",
+			events: []runtime.Object{
+				newEvent("a", corev1.EventTypeWarning, "NoAddress", now.Add(-time.Minute)),
+				newEvent("b", corev1.EventTypeNormal, "Sync", now),
+			},
+			want: "Warning NoAddress: NoAddress message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := fake.NewSimpleClientset(tt.events...)
+			if got := describeProbeIngressEvent(context.Background(), cliset, "team-a", "probe"); got != tt.want {
+				t.Errorf("describeProbeIngressEvent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	cliset := fake.NewSimpleClientset(newEvent("a", corev1.EventTypeWarning, "NoAddress", now))
+	forbidList(cliset, "events")
+	if got := describeProbeIngressEvent(context.Background(), cliset, "team-a", "probe"); got != "" {
+		t.Errorf("describeProbeIngressEvent() = %q when the events can't be listed, want none", got)
+	}
+}
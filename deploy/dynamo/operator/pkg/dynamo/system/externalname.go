@@ -0,0 +1,99 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// EnsureExternalNameService creates or updates an ExternalName Service pointing at
+// externalName, so that in-cluster clients can reach the discovered domain under a
+// stable name. The owner, if any, must be in the same namespace as the Service.
+func EnsureExternalNameService(ctx context.Context, cliset kubernetes.Interface, namespace, name, externalName string, owner *metav1.OwnerReference) error {
+	serviceCli := cliset.CoreV1().Services(namespace)
+
+	svc, err := serviceCli.Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					consts.KubeLabelManagedBy: consts.KubeCreator,
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:         corev1.ServiceTypeExternalName,
+				ExternalName: externalName,
+			},
+		}
+		if owner != nil {
+			svc.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		if _, err = serviceCli.Create(ctx, svc, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create service %s/%s", namespace, name)
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get service %s/%s", namespace, name)
+	}
+
+	if svc.Spec.Type != corev1.ServiceTypeExternalName {
+		return errors.Errorf("the service %s/%s already exists with type %s", namespace, name, svc.Spec.Type)
+	}
+	if svc.Spec.ExternalName == externalName {
+		return nil
+	}
+
+	svc = svc.DeepCopy()
+	svc.Spec.ExternalName = externalName
+	if _, err = serviceCli.Update(ctx, svc, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update service %s/%s", namespace, name)
+	}
+	return nil
+}
+
+// ensureDomainSuffixExternalNameService points the ExternalName Service set in the
+// network config at the domain suffix. The Service is owned by the network
+// configmap when they share a namespace.
+func ensureDomainSuffixExternalNameService(ctx context.Context, cliset kubernetes.Interface, config *discoveryConfig, configMap *corev1.ConfigMap, domainSuffix string) error {
+	if config.ExternalNameService == "" {
+		return nil
+	}
+
+	namespace, name, err := parseNamespacedName(config.ExternalNameService, configMap.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryExternalNameService, consts.KubeConfigMapNameNetworkConfig)
+	}
+
+	var owner *metav1.OwnerReference
+	if namespace == configMap.Namespace && configMap.UID != "" {
+		owner = metav1.NewControllerRef(configMap, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	}
+
+	return EnsureExternalNameService(ctx, cliset, namespace, name, domainSuffix, owner)
+}
@@ -0,0 +1,88 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestEnsureExternalNameService(t *testing.T) {
+	ctx := context.Background()
+	cliset := fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-ip", Namespace: "team-a"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	})
+	configMap := newNetworkConfigMap(nil)
+	configMap.UID = types.UID("network-config-uid")
+	owner := metav1.NewControllerRef(configMap, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+
+	if err := EnsureExternalNameService(ctx, cliset, "team-a", "ingress", "a.example.com", owner); err != nil {
+		t.Fatalf("EnsureExternalNameService() error = %v", err)
+	}
+	svc, err := cliset.CoreV1().Services("team-a").Get(ctx, "ingress", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the service: %v", err)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeExternalName || svc.Spec.ExternalName != "a.example.com" {
+		t.Errorf("the service is %s to %q, want ExternalName to a.example.com", svc.Spec.Type, svc.Spec.ExternalName)
+	}
+	if !reflect.DeepEqual(svc.OwnerReferences, []metav1.OwnerReference{*owner}) {
+		t.Errorf("the service is owned by %v, want the network configmap", svc.OwnerReferences)
+	}
+	if svc.Labels[consts.KubeLabelManagedBy] != consts.KubeCreator {
+		t.Errorf("the service labels = %v, want it managed by %s", svc.Labels, consts.KubeCreator)
+	}
+
+	// Ensuring the same external name again changes nothing.
+	cliset.ClearActions()
+	if err = EnsureExternalNameService(ctx, cliset, "team-a", "ingress", "a.example.com", owner); err != nil {
+		t.Fatalf("EnsureExternalNameService() again error = %v", err)
+	}
+	for _, action := range cliset.Actions() {
+		if action.GetVerb() != "get" {
+			t.Errorf("EnsureExternalNameService() again made a %s call, want none", action.GetVerb())
+		}
+	}
+
+	if err = EnsureExternalNameService(ctx, cliset, "team-a", "ingress", "b.example.com", owner); err != nil {
+		t.Fatalf("EnsureExternalNameService() with a new external name error = %v", err)
+	}
+	if svc, err = cliset.CoreV1().Services("team-a").Get(ctx, "ingress", metav1.GetOptions{}); err != nil || svc.Spec.ExternalName != "b.example.com" {
+		t.Errorf("the updated service = %v, %v, want its external name b.example.com", svc, err)
+	}
+
+	if err = EnsureExternalNameService(ctx, cliset, "team-b", "ingress", "a.example.com", nil); err != nil {
+		t.Fatalf("EnsureExternalNameService() without an owner error = %v", err)
+	}
+	if svc, err = cliset.CoreV1().Services("team-b").Get(ctx, "ingress", metav1.GetOptions{}); err != nil || len(svc.OwnerReferences) != 0 {
+		t.Errorf("the service without an owner = %v, %v, want no owner references", svc, err)
+	}
+
+	if err = EnsureExternalNameService(ctx, cliset, "team-a", "cluster-ip", "a.example.com", nil); err == nil {
+		t.Error("EnsureExternalNameService() of a ClusterIP service error = nil, want an error")
+	}
+}
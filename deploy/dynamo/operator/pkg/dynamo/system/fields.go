@@ -0,0 +1,94 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"errors"
+)
+
+// fieldError attaches structured fields to an error without changing its message,
+// so that structured loggers can log them as key/values.
+type fieldError struct {
+	err    error
+	fields map[string]interface{}
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+// Cause makes github.com/pkg/errors.Cause see through the fields.
+func (e *fieldError) Cause() error {
+	return e.err
+}
+
+// Fields returns the fields attached to the error, not including those of the
+// errors it wraps; use ErrorFields for all of them.
+func (e *fieldError) Fields() map[string]interface{} {
+	return e.fields
+}
+
+// withFields attaches the key/value pairs to the error. A nil error stays nil.
+func withFields(err error, keysAndValues ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if key, ok := keysAndValues[i].(string); ok {
+			fields[key] = keysAndValues[i+1]
+		}
+	}
+	return &fieldError{err: err, fields: fields}
+}
+
+// ErrorFields returns the structured fields attached anywhere in the chain of the
+// error, the outermost value winning, or nil if there are none. The result can be
+// passed to logrus.WithFields or flattened into logr key/values.
+func ErrorFields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	for err != nil {
+		if e, ok := err.(interface{ Fields() map[string]interface{} }); ok {
+			for k, v := range e.Fields() {
+				if fields == nil {
+					fields = make(map[string]interface{})
+				}
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+		}
+		err = unwrap(err)
+	}
+	return fields
+}
+
+// unwrap follows both the standard and the github.com/pkg/errors wrapping.
+func unwrap(err error) error {
+	if next := errors.Unwrap(err); next != nil {
+		return next
+	}
+	if c, ok := err.(interface{ Cause() error }); ok {
+		return c.Cause()
+	}
+	return nil
+}
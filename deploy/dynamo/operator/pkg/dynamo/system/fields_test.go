@@ -0,0 +1,58 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestWithFields(t *testing.T) {
+	if err := withFields(nil, "ingress", "probe"); err != nil {
+		t.Errorf("withFields(nil) = %v, want nil", err)
+	}
+
+	err := withFields(io.EOF, "ingress", "probe", 42, "not a key", "dangling")
+	if err.Error() != io.EOF.Error() {
+		t.Errorf("Error() = %q, want the message of the wrapped error", err.Error())
+	}
+	if !errors.Is(err, io.EOF) || errors.Cause(err) != io.EOF {
+		t.Errorf("withFields() = %v, want it to wrap io.EOF for both errors.Is and errors.Cause", err)
+	}
+	if fields := err.(*fieldError).Fields(); !reflect.DeepEqual(fields, map[string]interface{}{"ingress": "probe"}) {
+		t.Errorf("Fields() = %v, want only the string keys with a value", fields)
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	if fields := ErrorFields(io.EOF); fields != nil {
+		t.Errorf("ErrorFields() = %v of an error without fields, want nil", fields)
+	}
+
+	inner := withFields(io.EOF, "ingress", "inner", "namespace", "team-a")
+	// Both the github.com/pkg/errors and the standard wrapping are followed,
+	// and the outermost value wins.
+	err := withFields(fmt.Errorf("wait: %w", errors.Wrap(inner, "discover")), "ingress", "outer")
+	want := map[string]interface{}{"ingress": "outer", "namespace": "team-a"}
+	if fields := ErrorFields(err); !reflect.DeepEqual(fields, want) {
+		t.Errorf("ErrorFields() = %v, want %v", fields, want)
+	}
+}
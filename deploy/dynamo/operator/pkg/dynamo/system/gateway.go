@@ -0,0 +1,207 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayclient "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+var (
+	gatewayClientMu sync.RWMutex
+	gatewayClient   gatewayclient.Interface
+)
+
+// SetGatewayClient sets the Gateway API client used by the discovery in the
+// gateway network mode.
+func SetGatewayClient(client gatewayclient.Interface) {
+	gatewayClientMu.Lock()
+	defer gatewayClientMu.Unlock()
+	gatewayClient = client
+}
+
+func getGatewayClient() gatewayclient.Interface {
+	gatewayClientMu.RLock()
+	defer gatewayClientMu.RUnlock()
+	return gatewayClient
+}
+
+// getGatewayAddresses creates a probe Gateway and HTTPRoute, waits for the Gateway
+// to get an address and returns its addresses.
+func getGatewayAddresses(ctx context.Context, logger logr.Logger, namespace, correlationID string, discoveryConfig *discoveryConfig) (addresses []IngressAddress, err error) {
+	cli := getGatewayClient()
+	if cli == nil {
+		err = errors.Errorf("the %s network mode requires a Gateway API client, set one with SetGatewayClient", NetworkModeGateway)
+		return
+	}
+
+	if discoveryConfig.ProbeBackendPortName != "" {
+		// The backend of an HTTPRoute can only reference a port by number.
+		err = errors.Errorf("the %s network mode doesn't support %s, set %s instead", NetworkModeGateway, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort)
+		return
+	}
+
+	probeHost, err := renderProbeHost(logger, namespace, discoveryConfig)
+	if err != nil {
+		return
+	}
+
+	annotations := map[string]string{
+		consts.KubeAnnotationDynamoDiscoveryCorrelationID: correlationID,
+	}
+	if discoveryConfig.ProbeTTL > 0 {
+		annotations[consts.KubeAnnotationDynamoProbeIngressTTL] = discoveryConfig.ProbeTTL.String()
+	}
+
+	// The fake clients don't support generateName, so the name is generated here.
+	name := probeIngressGenerateName + utilrand.String(5)
+	gatewayCli := cli.GatewayV1().Gateways(namespace)
+	routeCli := cli.GatewayV1().HTTPRoutes(namespace)
+
+	logger.Info("Creating a gateway to get a gateway IP automatically", "gateway", name, "namespace", namespace)
+	probeGateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: gatewayv1.ObjectName(*discoveryConfig.GatewayClass),
+			Listeners: []gatewayv1.Listener{{
+				Name:     "http",
+				Port:     80,
+				Protocol: gatewayv1.HTTPProtocolType,
+			}},
+		},
+	}
+	var gateway *gatewayv1.Gateway
+	err = retryCreate(discoveryConfig.CreateRetries, func() (err error) {
+		gateway, err = gatewayCli.Create(ctx, probeGateway, metav1.CreateOptions{})
+		return
+	})
+	if err != nil {
+		err = &ProbeCreateError{Kind: "gateway", Name: name, Err: err}
+		return
+	}
+
+	var done func()
+	ctx, done, err = trackProbe(ctx, func() {
+		deleteProbeGateway(logger, cli, namespace, name)
+	})
+	if err != nil {
+		return
+	}
+	defer done()
+
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{
+					Name: gatewayv1.ObjectName(name),
+				}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{
+					BackendRef: gatewayv1.BackendRef{
+						BackendObjectReference: gatewayv1.BackendObjectReference{
+							Name: gatewayv1.ObjectName(discoveryConfig.ProbeBackendService),
+							Port: ptr.To(gatewayv1.PortNumber(discoveryConfig.ProbeBackendPort)),
+						},
+					},
+				}},
+			}},
+		},
+	}
+	if probeHost != "" {
+		route.Spec.Hostnames = []gatewayv1.Hostname{gatewayv1.Hostname(probeHost)}
+	}
+	if err = retryCreate(discoveryConfig.CreateRetries, func() error {
+		_, err := routeCli.Create(ctx, route, metav1.CreateOptions{})
+		return err
+	}); err != nil {
+		err = &ProbeCreateError{Kind: "httproute", Name: name, Err: err}
+		return
+	}
+
+	logger.Info("Waiting for the gateway to be ready", "gateway", name)
+	if err = pollProbe(ctx, discoveryConfig, func(ctx context.Context) (bool, error) {
+		gateway, err = gatewayCli.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return true, err
+		}
+		return len(gateway.Status.Addresses) > 0, nil
+	}); err != nil {
+		err = errors.Wrapf(err, "failed to wait for gateway %s to be ready", name)
+		return
+	}
+	logger.Info("The gateway is ready", "gateway", name)
+
+	addresses, err = resolveLoadBalancerAddresses(ctx, gatewayAddresses(gateway), fmt.Sprintf("the gateway %s", name), discoveryConfig)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the address of the gateway %s", name)
+		return
+	}
+
+	if err = waitForAddressesReachable(ctx, logger, addressIPs(addresses), "", discoveryConfig); err != nil {
+		addresses = nil
+	}
+	return
+}
+
+// gatewayAddresses converts the Gateway status addresses to load balancer ingress
+// entries, so that they are selected and resolved like those of an ingress.
+func gatewayAddresses(gateway *gatewayv1.Gateway) []networkingv1.IngressLoadBalancerIngress {
+	entries := make([]networkingv1.IngressLoadBalancerIngress, 0, len(gateway.Status.Addresses))
+	for _, address := range gateway.Status.Addresses {
+		if address.Type != nil && *address.Type == gatewayv1.HostnameAddressType {
+			entries = append(entries, networkingv1.IngressLoadBalancerIngress{Hostname: address.Value})
+		} else {
+			entries = append(entries, networkingv1.IngressLoadBalancerIngress{IP: address.Value})
+		}
+	}
+	return entries
+}
+
+func deleteProbeGateway(logger logr.Logger, cli gatewayclient.Interface, namespace, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeIngressDeleteTimeout)
+	defer cancel()
+	if err := cli.GatewayV1().HTTPRoutes(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete the probe httproute", "httproute", namespace+"/"+name)
+	}
+	if err := cli.GatewayV1().Gateways(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete the probe gateway", "gateway", namespace+"/"+name)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestGetIngressIPGateway(t *testing.T) {
+	gatewayCli := gatewayfake.NewSimpleClientset()
+	gatewayCli.PrependReactor("create", "gateways", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gateway := action.(k8stesting.CreateAction).GetObject().(*gatewayv1.Gateway)
+		gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{{Value: "10.0.0.7"}}
+		return false, nil, nil
+	})
+	SetGatewayClient(gatewayCli)
+	defer SetGatewayClient(nil)
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigNetworkMode:              string(NetworkModeGateway),
+		consts.KubeConfigMapKeyNetworkConfigGatewayClass:             "istio",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), fake.NewSimpleClientset())
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.7" {
+		t.Errorf("GetIngressIP() = %q, want %q", ip, "10.0.0.7")
+	}
+
+	var createdGateway, createdRoute bool
+	for _, action := range gatewayCli.Actions() {
+		if action.GetVerb() != "create" {
+			continue
+		}
+		switch obj := action.(k8stesting.CreateAction).GetObject().(type) {
+		case *gatewayv1.Gateway:
+			createdGateway = true
+			if obj.Spec.GatewayClassName != "istio" {
+				t.Errorf("gateway class = %q, want %q", obj.Spec.GatewayClassName, "istio")
+			}
+		case *gatewayv1.HTTPRoute:
+			createdRoute = true
+			if len(obj.Spec.ParentRefs) != 1 || string(obj.Spec.ParentRefs[0].Name) != obj.Name {
+				t.Errorf("httproute parent refs = %v, want the probe gateway", obj.Spec.ParentRefs)
+			}
+		}
+	}
+	if !createdGateway || !createdRoute {
+		t.Errorf("created gateway = %v, created httproute = %v, want both", createdGateway, createdRoute)
+	}
+
+	gateways, err := gatewayCli.GatewayV1().Gateways(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list gateways: %v", err)
+	}
+	if len(gateways.Items) != 0 {
+		t.Errorf("%d probe gateways left behind, want 0", len(gateways.Items))
+	}
+	routes, err := gatewayCli.GatewayV1().HTTPRoutes(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list httproutes: %v", err)
+	}
+	if len(routes.Items) != 0 {
+		t.Errorf("%d probe httproutes left behind, want 0", len(routes.Items))
+	}
+}
+
+func TestGetIngressIPGatewayWithoutClient(t *testing.T) {
+	SetGatewayClient(nil)
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigNetworkMode:  string(NetworkModeGateway),
+		consts.KubeConfigMapKeyNetworkConfigGatewayClass: "istio",
+	})
+
+	if _, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), fake.NewSimpleClientset()); err == nil {
+		t.Error("GetIngressIP() error = nil, want an error without a gateway client")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// probeIngressGenerateName is the name prefix of the probe ingresses created to
+// discover the ingress address.
+const probeIngressGenerateName = "default-domain-"
+
+// StartProbeIngressGC periodically deletes the probe ingresses in the system
+// namespace that are older than olderThan, or than the TTL annotated on them at
+// creation, until ctx is done. olderThan should exceed the discovery wait timeout
+// so that in-flight probes are left alone; zero disables the global threshold.
+//
+// The runs are jittered, and a zero interval uses the one set with RegisterFlags.
+func StartProbeIngressGC(ctx context.Context, cliset kubernetes.Interface, interval, olderThan time.Duration) {
+	if interval <= 0 {
+		interval = defaultDiscoveryTunables.GCInterval
+	}
+	go jitterUntil(ctx, func(ctx context.Context) {
+		logger := contextLogger(ctx)
+		deleted, err := collectProbeIngresses(ctx, cliset, GetNamespace(), olderThan, time.Now())
+		if err != nil {
+			logger.Error(err, "Failed to garbage collect the probe ingresses")
+			return
+		}
+		if deleted > 0 {
+			logger.Info("Garbage collected the probe ingresses", "deleted", deleted)
+		}
+	}, interval)
+}
+
+// RunProbeIngressGC runs StartProbeIngressGC at the interval set with
+// RegisterFlags, deleting the probe ingresses older than the discovery wait
+// timeout, and blocks until ctx is done. It is a manager.RunnableFunc, so that the
+// operator only collects the probes while it is the leader.
+func RunProbeIngressGC(ctx context.Context, cliset kubernetes.Interface) error {
+	StartProbeIngressGC(ctx, cliset, 0, defaultDiscoveryTunables.WaitTimeout)
+	<-ctx.Done()
+	return nil
+}
+
+// CleanupProbeIngresses deletes the probe ingresses in the namespace that are
+// older than olderThan, or than the TTL annotated on them, e.g. those left behind
+// by a crash of the operator, and returns how many were deleted. It is meant to
+// run on startup; a zero olderThan uses the discovery wait timeout, so that the
+// probes of in-flight discoveries are left alone.
+func CleanupProbeIngresses(ctx context.Context, cliset kubernetes.Interface, namespace string, olderThan time.Duration) (deleted int, err error) {
+	if olderThan <= 0 {
+		olderThan = defaultDiscoveryTunables.WaitTimeout
+	}
+	return collectProbeIngresses(ctx, cliset, namespace, olderThan, time.Now())
+}
+
+// isProbeIngress reports whether the ingress is a probe, by its name prefix or by
+// the domain probe label of the reusable probes.
+func isProbeIngress(ing *networkingv1.Ingress) bool {
+	return strings.HasPrefix(ing.Name, probeIngressGenerateName) || ing.Labels[consts.KubeLabelDynamoPurpose] == consts.KubeLabelValueDomainProbe
+}
+
+func collectProbeIngresses(ctx context.Context, cliset kubernetes.Interface, namespace string, olderThan time.Duration, now time.Time) (deleted int, err error) {
+	ingressCli := getIngressClient(cliset, namespace)
+
+	ingresses, err := ingressCli.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "failed to list ingresses in namespace %s", namespace)
+		return
+	}
+
+	for i := range ingresses.Items {
+		ing := &ingresses.Items[i]
+		if !isProbeIngress(ing) || !isProbeIngressExpired(contextLogger(ctx), ing, olderThan, now) {
+			continue
+		}
+		if err = ingressCli.Delete(ctx, ing.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			err = errors.Wrapf(err, "failed to delete probe ingress %s", ing.Name)
+			return
+		}
+		err = nil
+		deleted++
+	}
+
+	return
+}
+
+func isProbeIngressExpired(logger logr.Logger, ing *networkingv1.Ingress, olderThan time.Duration, now time.Time) bool {
+	age := now.Sub(ing.CreationTimestamp.Time)
+
+	// The persistent probe is meant to outlive the global threshold, and only
+	// expires with its own TTL.
+	persistent := ing.Annotations[consts.KubeAnnotationDynamoPersistentProbeIngress] == consts.KubeLabelValueTrue
+
+	if olderThan > 0 && age > olderThan && !persistent {
+		return true
+	}
+
+	if ttl_, ok := ing.Annotations[consts.KubeAnnotationDynamoProbeIngressTTL]; ok {
+		ttl, err := time.ParseDuration(ttl_)
+		if err != nil {
+			logger.Error(err, "Ignoring the invalid TTL annotation of the probe ingress", "annotation", consts.KubeAnnotationDynamoProbeIngressTTL, "ingress", ing.Name, "ttl", ttl_)
+			return false
+		}
+		return age > ttl
+	}
+
+	return false
+}
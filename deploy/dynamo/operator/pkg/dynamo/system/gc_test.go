@@ -0,0 +1,195 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func newAgedIngress(name string, age time.Duration, labels map[string]string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         GetNamespace(),
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+	}
+}
+
+func TestCleanupProbeIngresses(t *testing.T) {
+	probeLabels := map[string]string{consts.KubeLabelDynamoPurpose: consts.KubeLabelValueDomainProbe}
+	objects := []runtime.Object{
+		// Orphaned probes, by name prefix and by label.
+		newAgedIngress(probeIngressGenerateName+"orphan", 2*time.Hour, nil),
+		newAgedIngress("labeled-orphan", 2*time.Hour, probeLabels),
+		// A probe of an in-flight discovery.
+		newAgedIngress(probeIngressGenerateName+"inflight", time.Minute, nil),
+		// An old ingress that isn't a probe.
+		newAgedIngress("my-service", 2*time.Hour, map[string]string{consts.KubeLabelDynamoPurpose: "other"}),
+	}
+	cliset := fake.NewSimpleClientset(objects...)
+
+	deleted, err := CleanupProbeIngresses(context.Background(), cliset, GetNamespace(), time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupProbeIngresses() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("CleanupProbeIngresses() deleted %d, want 2", deleted)
+	}
+
+	ingresses, err := cliset.NetworkingV1().Ingresses(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list ingresses: %v", err)
+	}
+	var remaining []string
+	for _, ing := range ingresses.Items {
+		remaining = append(remaining, ing.Name)
+	}
+	sort.Strings(remaining)
+	want := []string{probeIngressGenerateName + "inflight", "my-service"}
+	if len(remaining) != len(want) || remaining[0] != want[0] || remaining[1] != want[1] {
+		t.Errorf("remaining ingresses = %v, want %v", remaining, want)
+	}
+}
+
+func TestCleanupProbeIngressesDefaultThreshold(t *testing.T) {
+	cliset := fake.NewSimpleClientset(
+		newAgedIngress(probeIngressGenerateName+"stale", defaultDiscoveryTunables.WaitTimeout+time.Minute, nil),
+		newAgedIngress(probeIngressGenerateName+"fresh", defaultDiscoveryTunables.WaitTimeout-time.Minute, nil),
+	)
+
+	deleted, err := CleanupProbeIngresses(context.Background(), cliset, GetNamespace(), 0)
+	if err != nil {
+		t.Fatalf("CleanupProbeIngresses() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("CleanupProbeIngresses() deleted %d, want only the one older than the wait timeout", deleted)
+	}
+}
+
+func TestRunProbeIngressGC(t *testing.T) {
+	cliset := fake.NewSimpleClientset(
+		newAgedIngress(probeIngressGenerateName+"stale", defaultDiscoveryTunables.WaitTimeout+time.Minute, nil),
+		newAgedIngress(probeIngressGenerateName+"fresh", defaultDiscoveryTunables.WaitTimeout-time.Minute, nil),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- RunProbeIngressGC(ctx, cliset)
+	}()
+
+	// The first run is immediate.
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, time.Second, true, func(ctx context.Context) (bool, error) {
+		ingresses, err := cliset.NetworkingV1().Ingresses(GetNamespace()).List(ctx, metav1.ListOptions{})
+		return err == nil && len(ingresses.Items) == 1 && ingresses.Items[0].Name == probeIngressGenerateName+"fresh", err
+	})
+	if err != nil {
+		t.Errorf("the stale probe ingress wasn't collected: %v", err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("RunProbeIngressGC() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("RunProbeIngressGC() didn't return once the context was done")
+	}
+}
+
+func TestIsProbeIngressExpired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		age         time.Duration
+		annotations map[string]string
+		olderThan   time.Duration
+		want        bool
+	}{
+		{
+			name:      "older than the threshold",
+			age:       2 * time.Hour,
+			olderThan: time.Hour,
+			want:      true,
+		},
+		{
+			name:      "younger than the threshold",
+			age:       time.Minute,
+			olderThan: time.Hour,
+		},
+		{
+			name:        "older than its TTL",
+			age:         10 * time.Minute,
+			annotations: map[string]string{consts.KubeAnnotationDynamoProbeIngressTTL: "5m"},
+			olderThan:   time.Hour,
+			want:        true,
+		},
+		{
+			name:        "younger than its TTL",
+			age:         time.Minute,
+			annotations: map[string]string{consts.KubeAnnotationDynamoProbeIngressTTL: "5m"},
+		},
+		{
+			name:        "invalid TTL",
+			age:         10 * time.Minute,
+			annotations: map[string]string{consts.KubeAnnotationDynamoProbeIngressTTL: "five minutes"},
+			olderThan:   time.Hour,
+		},
+		{
+			name:        "persistent past the threshold",
+			age:         2 * time.Hour,
+			annotations: map[string]string{consts.KubeAnnotationDynamoPersistentProbeIngress: consts.KubeLabelValueTrue},
+			olderThan:   time.Hour,
+		},
+		{
+			name: "persistent past its TTL",
+			age:  2 * time.Hour,
+			annotations: map[string]string{
+				consts.KubeAnnotationDynamoPersistentProbeIngress: consts.KubeLabelValueTrue,
+				consts.KubeAnnotationDynamoProbeIngressTTL:        "90m",
+			},
+			olderThan: time.Hour,
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ing := newAgedIngress(probeIngressGenerateName+"ttl", 0, nil)
+			ing.CreationTimestamp = metav1.NewTime(now.Add(-tt.age))
+			ing.Annotations = tt.annotations
+			if got := isProbeIngressExpired(logr.Discard(), ing, tt.olderThan, now); got != tt.want {
+				t.Errorf("isProbeIngressExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
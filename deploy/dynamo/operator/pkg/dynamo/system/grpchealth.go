@@ -0,0 +1,152 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// defaultGRPCHealthTimeout bounds each gRPC health check of the discovered
+// addresses, unless set in the network config.
+const defaultGRPCHealthTimeout = 2 * time.Second
+
+// grpcHealthCheckPath is the method of the standard gRPC health service,
+// grpc.health.v1.Health.
+const grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// grpcHealthServing is the SERVING status of a grpc.health.v1.HealthCheckResponse.
+const grpcHealthServing = 1
+
+// checkGRPCHealth calls the gRPC health service at the address, over plaintext
+// HTTP/2 with the authority, if set, as the :authority of the request so that
+// the ingress controller routes it to the probe backend. It fails unless the
+// service reports SERVING. The connection is opened with the dialer set with
+// SetDialer.
+func checkGRPCHealth(ctx context.Context, address, authority, service string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return getDialer().DialContext(ctx, network, addr)
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+address+grpcHealthCheckPath, bytes.NewReader(grpcHealthCheckRequest(service)))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the gRPC health check request")
+	}
+	if authority != "" {
+		req.Host = authority
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to call the gRPC health service")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("the gRPC health service answered with HTTP status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the gRPC health check response")
+	}
+
+	// A failed call may have no body, and its status in the headers rather
+	// than in the trailers.
+	status, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if status != "0" {
+		return errors.Errorf("the gRPC health check failed with status %s: %s", status, message)
+	}
+
+	servingStatus, err := parseGRPCHealthCheckResponse(body)
+	if err != nil {
+		return err
+	}
+	if servingStatus != grpcHealthServing {
+		return errors.Errorf("the gRPC health service reports the status %d, not SERVING", servingStatus)
+	}
+	return nil
+}
+
+// grpcHealthCheckRequest returns the length-prefixed message of a
+// grpc.health.v1.HealthCheckRequest for the service.
+func grpcHealthCheckRequest(service string) []byte {
+	var message []byte
+	if service != "" {
+		message = protowire.AppendTag(message, 1, protowire.BytesType)
+		message = protowire.AppendString(message, service)
+	}
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+// parseGRPCHealthCheckResponse returns the status of the length-prefixed
+// grpc.health.v1.HealthCheckResponse message.
+func parseGRPCHealthCheckResponse(body []byte) (status uint64, err error) {
+	if len(body) < 5 {
+		return 0, errors.Errorf("the gRPC health check response is truncated: %d bytes", len(body))
+	}
+	if body[0] != 0 {
+		return 0, errors.New("the gRPC health check response is compressed")
+	}
+	message := body[5:]
+	if length := binary.BigEndian.Uint32(body[1:5]); int(length) != len(message) {
+		return 0, errors.Errorf("the gRPC health check response has %d bytes, want %d", len(message), length)
+	}
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return 0, errors.Wrap(protowire.ParseError(n), "failed to parse the gRPC health check response")
+		}
+		message = message[n:]
+		if num == 1 && typ == protowire.VarintType {
+			status, n = protowire.ConsumeVarint(message)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, message)
+		}
+		if n < 0 {
+			return 0, errors.Wrap(protowire.ParseError(n), "failed to parse the gRPC health check response")
+		}
+		message = message[n:]
+	}
+	return status, nil
+}
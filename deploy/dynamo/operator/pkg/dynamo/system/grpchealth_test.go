@@ -0,0 +1,199 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/encoding/protowire"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// grpcHealthResponder is a plaintext HTTP/2 server answering the gRPC health
+// checks with the status of each service, and NOT_FOUND for the others.
+type grpcHealthResponder struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	statuses    map[string]uint64
+	block       bool
+	authorities []string
+}
+
+func newGRPCHealthResponder(t *testing.T, statuses map[string]uint64) *grpcHealthResponder {
+	r := &grpcHealthResponder{statuses: statuses}
+	r.Server = httptest.NewServer(h2c.NewHandler(http.HandlerFunc(r.serveHTTP), &http2.Server{}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *grpcHealthResponder) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.authorities = append(r.authorities, req.Host)
+	block, statuses := r.block, r.statuses
+	r.mu.Unlock()
+	if block {
+		<-req.Context().Done()
+		return
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if req.URL.Path != grpcHealthCheckPath || req.Header.Get("Content-Type") != "application/grpc" || len(body) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var service string
+	if message := body[5:]; len(message) > 0 {
+		_, _, n := protowire.ConsumeTag(message)
+		service, _ = protowire.ConsumeString(message[n:])
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	status, ok := statuses[service]
+	if !ok {
+		w.Header().Set("Grpc-Status", "5")
+		w.Header().Set("Grpc-Message", "unknown service")
+		return
+	}
+	message := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), status)
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	_, _ = w.Write(append(frame, message...))
+	w.Header().Set("Grpc-Status", "0")
+}
+
+func (r *grpcHealthResponder) address() string {
+	return strings.TrimPrefix(r.URL, "http://")
+}
+
+func TestCheckGRPCHealth(t *testing.T) {
+	responder := newGRPCHealthResponder(t, map[string]uint64{"": grpcHealthServing, "inference": 2})
+
+	tests := []struct {
+		name    string
+		service string
+		wantErr string
+	}{
+		{name: "serving", service: ""},
+		{name: "not serving", service: "inference", wantErr: "not SERVING"},
+		{name: "unknown service", service: "other", wantErr: "status 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkGRPCHealth(context.Background(), responder.address(), "probe.example.com", tt.service, time.Second)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkGRPCHealth() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkGRPCHealth() error = %v, want one about %s", err, tt.wantErr)
+			}
+		})
+	}
+
+	for _, authority := range responder.authorities {
+		if authority != "probe.example.com" {
+			t.Errorf("the health checks had the authority %q, want %q", authority, "probe.example.com")
+		}
+	}
+}
+
+func TestCheckGRPCHealthTimeout(t *testing.T) {
+	responder := newGRPCHealthResponder(t, nil)
+	responder.block = true
+
+	start := time.Now()
+	err := checkGRPCHealth(context.Background(), responder.address(), "", "", 50*time.Millisecond)
+	if err == nil {
+		t.Fatalf("checkGRPCHealth() error = nil, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("checkGRPCHealth() took %s, want it bounded by its timeout", elapsed)
+	}
+}
+
+// redirectDialer dials the address for every connection, e.g. a local test
+// server in place of the load balancer IP.
+type redirectDialer struct {
+	address string
+}
+
+func (d redirectDialer) DialContext(ctx context.Context, network, _ string) (net.Conn, error) {
+	return (&net.Dialer{}).DialContext(ctx, network, d.address)
+}
+
+func TestGetIngressIPGRPCHealthCheck(t *testing.T) {
+	responder := newGRPCHealthResponder(t, map[string]uint64{"": 2})
+	SetDialer(redirectDialer{address: responder.address()})
+	defer SetDialer(nil)
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:     "2s",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort:        "8080",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthCheck: "true",
+	})
+
+	// The backend turns SERVING after a few checks.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		responder.mu.Lock()
+		defer responder.mu.Unlock()
+		responder.statuses = map[string]uint64{"": grpcHealthServing}
+	}()
+
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.1" {
+		t.Errorf("GetIngressIP() = %q, want %q", ip, "10.0.0.1")
+	}
+	responder.mu.Lock()
+	defer responder.mu.Unlock()
+	if len(responder.authorities) < 2 {
+		t.Errorf("the backend was checked %d times, want it checked until it is SERVING", len(responder.authorities))
+	}
+}
+
+func TestParseDiscoveryConfigGRPCHealthCheckRequiresDialPort(t *testing.T) {
+	_, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthCheck: "true",
+	}))
+	if err == nil || !strings.Contains(err.Error(), consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort) {
+		t.Errorf("parseDiscoveryConfig() error = %v, want one requiring %s", err, consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort)
+	}
+}
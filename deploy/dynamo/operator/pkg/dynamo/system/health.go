@@ -0,0 +1,62 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// CheckIngressReady checks that the domain suffix can be discovered, e.g. as a
+// readiness gate of the operator: the network configmap exists and parses, and
+// the ingress class it sets exists, unless the ingress class check is turned
+// off. It neither creates a probe ingress nor waits on a load balancer, so it is
+// cheap enough to run on every readiness probe.
+func CheckIngressReady(ctx context.Context, cliset kubernetes.Interface, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) error {
+	if domainSuffix, err := getDomainSuffixOverride(); err != nil || domainSuffix != "" {
+		return err
+	}
+
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
+	}
+
+	ingressConfig, err := ParseIngressConfig(configMap)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get ingress config")
+	}
+
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get discovery config")
+	}
+
+	if ingressConfig.ClassName != nil && discoveryConfig.IngressClassCheck && discoveryConfig.NetworkMode != NetworkModeGateway {
+		if err := ValidateIngressClass(ctx, cliset, *ingressConfig.ClassName); err != nil {
+			return errors.Wrapf(err, "failed to check the ingress class")
+		}
+	}
+
+	return nil
+}
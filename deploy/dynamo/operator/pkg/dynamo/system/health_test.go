@@ -0,0 +1,105 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestCheckIngressReady(t *testing.T) {
+	notFound := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return nil, k8serrors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+
+	tests := []struct {
+		name            string
+		configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+		wantErr         string
+	}{
+		{
+			name: "ready",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+			})),
+		},
+		{
+			name:            "default ingress class",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(nil)),
+		},
+		{
+			name:            "missing configmap",
+			configmapGetter: notFound,
+			wantErr:         "failed to get configmap network",
+		},
+		{
+			name: "invalid ingress config",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"example.com/a": 1}`,
+			})),
+			wantErr: "failed to get ingress config",
+		},
+		{
+			name: "invalid discovery config",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort: "70000",
+			})),
+			wantErr: "failed to get discovery config",
+		},
+		{
+			name: "missing ingress class",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "missing",
+			})),
+			wantErr: `the ingress class "missing" does not exist`,
+		},
+		{
+			name: "ingress class check off",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:               "missing",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassCheck: "false",
+			})),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset()
+
+			err := CheckIngressReady(context.Background(), cliset, tt.configmapGetter)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("CheckIngressReady() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("CheckIngressReady() error = %v, want one containing %q", err, tt.wantErr)
+			}
+
+			for _, action := range cliset.Actions() {
+				if verb := action.GetVerb(); verb != "get" && verb != "list" {
+					t.Errorf("unexpected %s of %s in a readiness check", verb, action.GetResource().Resource)
+				}
+			}
+		})
+	}
+}
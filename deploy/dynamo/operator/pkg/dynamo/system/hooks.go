@@ -0,0 +1,119 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PostDiscoveryHookEnvDomainSuffix, PostDiscoveryHookEnvIP and
+	// PostDiscoveryHookEnvNamespace pass the discovery result to the post
+	// discovery command.
+	PostDiscoveryHookEnvDomainSuffix = "DYNAMO_DOMAIN_SUFFIX"
+	PostDiscoveryHookEnvIP           = "DYNAMO_INGRESS_IP"
+	PostDiscoveryHookEnvNamespace    = "DYNAMO_NAMESPACE"
+
+	postDiscoveryCommandTimeout = time.Minute
+)
+
+// DiscoveryResult is the outcome of a successful domain suffix discovery.
+type DiscoveryResult struct {
+	Namespace     string
+	DomainSuffix  string
+	IP            string
+	CorrelationID string
+	// ReverseNames are the reverse DNS names of the IP, if looked up.
+	ReverseNames []string
+}
+
+// PostDiscoveryHook is invoked after a domain suffix was discovered and persisted,
+// e.g. to refresh a DNS zone or notify another controller.
+type PostDiscoveryHook func(ctx context.Context, result DiscoveryResult) error
+
+var (
+	postDiscoveryHooksMu sync.RWMutex
+	postDiscoveryHooks   []PostDiscoveryHook
+)
+
+// RegisterPostDiscoveryHook adds a hook to run after every successful domain
+// suffix discovery.
+func RegisterPostDiscoveryHook(hook PostDiscoveryHook) {
+	postDiscoveryHooksMu.Lock()
+	defer postDiscoveryHooksMu.Unlock()
+	postDiscoveryHooks = append(postDiscoveryHooks, hook)
+}
+
+// runPostDiscoveryHooks runs the registered hooks and the command of the operator
+// flags. Hook errors are logged, and only returned when the network config makes
+// them fatal.
+func runPostDiscoveryHooks(ctx context.Context, logger logr.Logger, config *discoveryConfig, result DiscoveryResult) error {
+	postDiscoveryHooksMu.RLock()
+	hooks := make([]PostDiscoveryHook, 0, len(postDiscoveryHooks)+1)
+	hooks = append(hooks, postDiscoveryHooks...)
+	postDiscoveryHooksMu.RUnlock()
+
+	if command := defaultDiscoveryTunables.PostHookCommand; command != "" {
+		hooks = append(hooks, commandPostDiscoveryHook(logger, command))
+	}
+
+	for _, hook := range hooks {
+		if err := hook(ctx, result); err != nil {
+			if config.PostHookFatal {
+				return errors.Wrap(err, "post discovery hook failed")
+			}
+			logger.Error(err, "Post discovery hook failed")
+		}
+	}
+	return nil
+}
+
+// commandPostDiscoveryHook runs the command with the discovery result in its
+// environment. The command is split on whitespace and not run through a shell.
+func commandPostDiscoveryHook(logger logr.Logger, command string) PostDiscoveryHook {
+	return func(ctx context.Context, result DiscoveryResult) error {
+		args := strings.Fields(command)
+
+		ctx, cancel := context.WithTimeout(ctx, postDiscoveryCommandTimeout)
+		defer cancel()
+
+		// nolint: gosec
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Env = append(os.Environ(),
+			fmt.Sprintf("%s=%s", PostDiscoveryHookEnvDomainSuffix, result.DomainSuffix),
+			fmt.Sprintf("%s=%s", PostDiscoveryHookEnvIP, result.IP),
+			fmt.Sprintf("%s=%s", PostDiscoveryHookEnvNamespace, result.Namespace),
+		)
+
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return errors.Wrapf(err, "failed to run post discovery command %q: %s", command, strings.TrimSpace(string(output)))
+		}
+		logger.Info("Post discovery command succeeded", "command", command)
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// TestPostDiscoveryHookHelperProcess is the stub post discovery command: the test
+// binary run again by hookCommand. It writes its arguments and the discovery
+// environment to DYNAMO_HOOK_OUTPUT, and then sleeps or fails as asked.
+func TestPostDiscoveryHookHelperProcess(t *testing.T) {
+	if os.Getenv("DYNAMO_HOOK_HELPER") != "1" {
+		return
+	}
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	lines := append([]string{strings.Join(args, " ")},
+		os.Getenv(PostDiscoveryHookEnvDomainSuffix),
+		os.Getenv(PostDiscoveryHookEnvIP),
+		os.Getenv(PostDiscoveryHookEnvNamespace),
+	)
+	if err := os.WriteFile(os.Getenv("DYNAMO_HOOK_OUTPUT"), []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		os.Exit(2)
+	}
+	switch os.Getenv("DYNAMO_HOOK_MODE") {
+	case "fail":
+		os.Exit(1)
+	case "sleep":
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+// hookCommand sets the post discovery command to the stub one, running in mode,
+// and returns the file it writes to.
+func hookCommand(t *testing.T, mode string, args ...string) string {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "hook")
+	t.Setenv("DYNAMO_HOOK_HELPER", "1")
+	t.Setenv("DYNAMO_HOOK_OUTPUT", output)
+	t.Setenv("DYNAMO_HOOK_MODE", mode)
+
+	previous := defaultDiscoveryTunables.PostHookCommand
+	defaultDiscoveryTunables.PostHookCommand = strings.Join(append([]string{os.Args[0], "-test.run=^TestPostDiscoveryHookHelperProcess$", "--"}, args...), " ")
+	t.Cleanup(func() { defaultDiscoveryTunables.PostHookCommand = previous })
+	return output
+}
+
+func TestRunPostDiscoveryHooksCommand(t *testing.T) {
+	output := hookCommand(t, "", "first", "second")
+	result := DiscoveryResult{Namespace: "team-a", DomainSuffix: "10.0.0.1.sslip.io", IP: "10.0.0.1"}
+
+	if err := runPostDiscoveryHooks(context.Background(), logr.Discard(), &discoveryConfig{}, result); err != nil {
+		t.Fatalf("runPostDiscoveryHooks() error = %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("the post discovery command didn't run: %v", err)
+	}
+	want := "first second\n10.0.0.1.sslip.io\n10.0.0.1\nteam-a"
+	if string(got) != want {
+		t.Errorf("the post discovery command got %q, want %q", got, want)
+	}
+}
+
+func TestRunPostDiscoveryHooksFailure(t *testing.T) {
+	hookCommand(t, "fail")
+
+	for _, fatal := range []bool{false, true} {
+		err := runPostDiscoveryHooks(context.Background(), logr.Discard(), &discoveryConfig{PostHookFatal: fatal}, DiscoveryResult{})
+		if (err != nil) != fatal {
+			t.Errorf("runPostDiscoveryHooks() with fatal %t error = %v", fatal, err)
+		}
+	}
+}
+
+func TestRunPostDiscoveryHooksTimeout(t *testing.T) {
+	hookCommand(t, "sleep")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := runPostDiscoveryHooks(ctx, logr.Discard(), &discoveryConfig{PostHookFatal: true}, DiscoveryResult{}); err == nil {
+		t.Fatal("runPostDiscoveryHooks() error = nil, want the command to be killed")
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Second {
+		t.Errorf("runPostDiscoveryHooks() took %s, want the command killed at the deadline", elapsed)
+	}
+}
@@ -21,39 +21,506 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/rs/xid"
-	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
 )
 
+// IngressTLSConfig is a TLS section of the generated ingresses.
+type IngressTLSConfig struct {
+	SecretName string   `json:"secretName"`
+	Hosts      []string `json:"hosts,omitempty"`
+}
+
+// IngressPath is a path of the generated ingresses, optionally routed to another
+// backend than the default one.
+type IngressPath struct {
+	Path string `json:"path"`
+	// PathType defaults to the path type of the ingress config.
+	PathType networkingv1.PathType `json:"pathType,omitempty"`
+	// Service and Port, if set, override the default backend.
+	Service string `json:"service,omitempty"`
+	Port    int32  `json:"port,omitempty"`
+	// Host, if set, is the host of the rule of the path instead of the default
+	// one, e.g. for a path served over TLS on another host than the others.
+	Host string `json:"host,omitempty"`
+}
+
+// IngressDefaultBackend is the backend of the generated ingresses for the requests
+// that match none of their rules, a service port by number or by name.
+type IngressDefaultBackend struct {
+	Service  string `json:"service"`
+	Port     int32  `json:"port,omitempty"`
+	PortName string `json:"portName,omitempty"`
+}
+
+// IngressBackend returns the default backend of the ingress spec.
+func (b *IngressDefaultBackend) IngressBackend() *networkingv1.IngressBackend {
+	return &networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: b.Service,
+			Port: networkingv1.ServiceBackendPort{Number: b.Port, Name: b.PortName},
+		},
+	}
+}
+
 type IngressConfig struct {
 	ClassName   *string
 	Annotations map[string]string
-	Path        string
-	PathType    networkingv1.PathType
+	// AnnotationsExplicit reports whether the annotations key was set in the
+	// network config, even if only to an empty object ("{}").
+	AnnotationsExplicit bool
+	// RemoveAnnotations are the annotation keys deleted from the merged
+	// annotations, whichever layer sets them, e.g. to opt out of an operator
+	// default.
+	RemoveAnnotations []string
+	Path              string
+	PathType          networkingv1.PathType
+	// PathTypeExplicit reports whether the path type was set in the network
+	// config rather than defaulted.
+	PathTypeExplicit bool
+	// Paths, if any, replace the single Path.
+	Paths []IngressPath
+	TLS   []IngressTLSConfig
+	// BackendProtocol, if set, is the protocol the ingress controller speaks to
+	// the backend service with.
+	BackendProtocol BackendProtocol
+	// DefaultBackend, if set, is the default backend of the generated ingresses,
+	// next to their rules.
+	DefaultBackend *IngressDefaultBackend
+	// CertManagerIssuer, if set, is the cert-manager cluster issuer that
+	// provisions the certificates of the generated ingresses, see WithCertManager.
+	// The probe ingresses don't get one.
+	CertManagerIssuer string
+	// RewriteTarget, if set, is the path the ingress controller rewrites the
+	// requests to, through RewriteMiddleware for traefik, see
+	// RewriteTargetIngressAnnotations.
+	RewriteTarget     string
+	RewriteMiddleware string
+
+	// strippedAnnotations are the keys of the annotations of the network config
+	// the allowlist stripped, see LogStrippedAnnotations.
+	strippedAnnotations []string
+}
+
+// LogStrippedAnnotations warns about the annotations of the network configmap
+// the allowlist stripped, see SetAnnotationAllowlist.
+func (c *IngressConfig) LogStrippedAnnotations(logger logr.Logger, configMap *corev1.ConfigMap) {
+	if len(c.strippedAnnotations) > 0 {
+		logger.Info("Ignoring the ingress annotations of the network config, which aren't allowed", "configmap", configMap.Namespace+"/"+configMap.Name, "annotations", c.strippedAnnotations)
+	}
+}
+
+// ApplyControllerDefaults defaults the path type to the one best supported by the
+// ingress controller, unless it was set explicitly in the network config.
+func (c *IngressConfig) ApplyControllerDefaults(controllerType IngressControllerType) {
+	if c.PathTypeExplicit {
+		return
+	}
+	if pathType, ok := controllerPreferredPathTypes[controllerType]; ok {
+		c.PathType = pathType
+	}
+}
+
+// InheritAnnotations fills in annotations from a lower-precedence layer (such as
+// ingress class parameters or controller defaults) that are not already set.
+//
+// The precedence is: annotations from the network config always win over
+// inherited ones, and when the annotations key is set explicitly in the network
+// config, nothing is inherited at all, so `{}` means "no annotations" while an
+// absent key means "use the defaults".
+func (c *IngressConfig) InheritAnnotations(defaults map[string]string) {
+	if c.AnnotationsExplicit {
+		return
+	}
+	if c.Annotations == nil {
+		c.Annotations = make(map[string]string, len(defaults))
+	}
+	for k, v := range defaults {
+		if _, ok := c.Annotations[k]; !ok {
+			c.Annotations[k] = v
+		}
+	}
+	c.removeAnnotations()
+}
+
+// removeAnnotations deletes the RemoveAnnotations from the annotations; a key
+// that isn't set is ignored.
+func (c *IngressConfig) removeAnnotations() {
+	for _, key := range c.RemoveAnnotations {
+		delete(c.Annotations, key)
+	}
+}
+
+var (
+	defaultAnnotationsMu sync.RWMutex
+	defaultAnnotations   map[string]string
+)
+
+// SetDefaultAnnotations sets the operator-managed annotations, such as a
+// cert-manager cluster issuer, that every ingress config gets unless the network
+// config sets them to another value.
+func SetDefaultAnnotations(annotations map[string]string) {
+	copied := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		copied[k] = v
+	}
+	defaultAnnotationsMu.Lock()
+	defer defaultAnnotationsMu.Unlock()
+	defaultAnnotations = copied
+}
+
+func getDefaultAnnotations() map[string]string {
+	defaultAnnotationsMu.RLock()
+	defer defaultAnnotationsMu.RUnlock()
+	return defaultAnnotations
+}
+
+// MergeDefaultAnnotations adds the operator-managed annotations under the ones of
+// the network config, which win on conflict. Unlike InheritAnnotations, they are
+// merged even when the annotations key is set explicitly, so the precedence is:
+// the network config, then the operator defaults, then the inherited annotations.
+func (c *IngressConfig) MergeDefaultAnnotations(defaults map[string]string) {
+	if c.Annotations == nil {
+		c.Annotations = make(map[string]string, len(defaults))
+	}
+	for k, v := range defaults {
+		if _, ok := c.Annotations[k]; !ok {
+			c.Annotations[k] = v
+		}
+	}
+}
+
+// HTTPIngressPaths returns the paths of the generated ingresses for the ingress
+// config, see HTTPIngressPaths.
+func (c *IngressConfig) HTTPIngressPaths(defaultBackend networkingv1.IngressServiceBackend) []networkingv1.HTTPIngressPath {
+	return HTTPIngressPaths(c.Paths, c.Path, c.PathType, defaultBackend)
+}
+
+// HTTPIngressPaths returns one ingress path per entry of paths, or the single
+// path when there are none, for backward compatibility. The entries default to
+// the path type and to the backend given.
+func HTTPIngressPaths(paths []IngressPath, path string, pathType networkingv1.PathType, defaultBackend networkingv1.IngressServiceBackend) []networkingv1.HTTPIngressPath {
+	if len(paths) == 0 {
+		paths = []IngressPath{{Path: path}}
+	}
+
+	httpPaths := make([]networkingv1.HTTPIngressPath, 0, len(paths))
+	for _, p := range paths {
+		entryPathType := p.PathType
+		if entryPathType == "" {
+			entryPathType = pathType
+		}
+		backend := defaultBackend.DeepCopy()
+		if p.Service != "" {
+			backend.Name = p.Service
+		}
+		if p.Port != 0 {
+			backend.Port = networkingv1.ServiceBackendPort{Number: p.Port}
+		}
+		httpPaths = append(httpPaths, networkingv1.HTTPIngressPath{
+			Path:     p.Path,
+			PathType: &entryPathType,
+			Backend:  networkingv1.IngressBackend{Service: backend},
+		})
+	}
+	return httpPaths
+}
+
+// IngressRules returns the rules of the generated ingresses for the ingress
+// config, see IngressRules.
+func (c *IngressConfig) IngressRules(defaultHost string, defaultBackend networkingv1.IngressServiceBackend) []networkingv1.IngressRule {
+	return IngressRules(c.Paths, c.Path, c.PathType, defaultHost, defaultBackend)
+}
+
+// IngressRules returns one rule per host of paths, in the order they first
+// appear, with the paths of that host as HTTPIngressPaths does. The paths without
+// a host, or the single path when there are none, go to the default host.
+func IngressRules(paths []IngressPath, path string, pathType networkingv1.PathType, defaultHost string, defaultBackend networkingv1.IngressServiceBackend) []networkingv1.IngressRule {
+	if len(paths) == 0 {
+		paths = []IngressPath{{Path: path}}
+	}
+
+	var hosts []string
+	pathsByHost := make(map[string][]IngressPath)
+	for _, p := range paths {
+		host := p.Host
+		if host == "" {
+			host = defaultHost
+		}
+		if _, ok := pathsByHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		pathsByHost[host] = append(pathsByHost[host], p)
+	}
+
+	rules := make([]networkingv1.IngressRule, 0, len(hosts))
+	for _, host := range hosts {
+		rules = append(rules, networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: HTTPIngressPaths(pathsByHost[host], path, pathType, defaultBackend),
+				},
+			},
+		})
+	}
+	return rules
 }
 
+// ValidateIngressTLSHosts checks that every host of the TLS sections is the host
+// of one of the rules, or a wildcard covering one, so that only some hosts of an
+// ingress may be served over TLS but no TLS host is left without a rule.
+func ValidateIngressTLSHosts(tls []networkingv1.IngressTLS, rules []networkingv1.IngressRule) error {
+	var missing []string
+	for _, t := range tls {
+		for _, host := range t.Hosts {
+			if !ingressRulesCoverHost(rules, host) {
+				missing = append(missing, host)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("the TLS hosts %s are not the host of any ingress rule", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func ingressRulesCoverHost(rules []networkingv1.IngressRule, host string) bool {
+	for _, rule := range rules {
+		if tlsHostCovers(host, rule.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsHostCovers reports whether the TLS host, which may be a wildcard of a single
+// label, e.g. `*.example.com`, covers the host.
+func tlsHostCovers(tlsHost, host string) bool {
+	if tlsHost == host {
+		return true
+	}
+	wildcardSuffix, isWildcard := strings.CutPrefix(tlsHost, "*")
+	return isWildcard && strings.HasSuffix(host, wildcardSuffix) && !strings.Contains(strings.TrimSuffix(host, wildcardSuffix), ".")
+}
+
+// ParseIngressPaths parses the multi-path list of the network config, which is
+// empty when the key isn't set.
+func ParseIngressPaths(configMap *corev1.ConfigMap) (paths []IngressPath, err error) {
+	paths_ := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressPaths])
+	if paths_ == "" {
+		return
+	}
+	err = json.Unmarshal([]byte(paths_), &paths)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to json unmarshal %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigIngressPaths, consts.KubeConfigMapNameNetworkConfig, paths_)
+		return
+	}
+	for _, p := range paths {
+		if p.Path == "" {
+			err = errors.Errorf("the %s in configmap %s has an entry without a path", consts.KubeConfigMapKeyNetworkConfigIngressPaths, consts.KubeConfigMapNameNetworkConfig)
+			return
+		}
+		if err = ValidateIngressPath(p.Path); err != nil {
+			err = errors.Wrapf(err, "invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigIngressPaths, consts.KubeConfigMapNameNetworkConfig)
+			return
+		}
+		if errs := validation.IsDNS1123Subdomain(p.Host); p.Host != "" && len(errs) > 0 {
+			err = errors.Errorf("invalid host %q of %s in configmap %s: %s", p.Host, consts.KubeConfigMapKeyNetworkConfigIngressPaths, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+			return
+		}
+	}
+	return
+}
+
+// ValidateIngressPath checks that the path of an ingress rule is absolute, which
+// the API server doesn't check for the ImplementationSpecific path type, but most
+// controllers then reject.
+func ValidateIngressPath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return errors.Errorf("the path %q must start with /", path)
+	}
+	return nil
+}
+
+// ParseIngressTLS parses the TLS sections of the network config, one per secret
+// and its hosts, which is empty when the key isn't set. The hosts without an
+// entry aren't served over TLS.
+func ParseIngressTLS(configMap *corev1.ConfigMap) (tls []IngressTLSConfig, err error) {
+	tls_ := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressTLS])
+	if tls_ == "" {
+		return
+	}
+	err = json.Unmarshal([]byte(tls_), &tls)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to json unmarshal %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigIngressTLS, consts.KubeConfigMapNameNetworkConfig, tls_)
+		return nil, err
+	}
+	for _, t := range tls {
+		if t.SecretName == "" && len(t.Hosts) > 0 {
+			err = errors.Errorf("the %s in configmap %s has hosts %s without a secretName", consts.KubeConfigMapKeyNetworkConfigIngressTLS, consts.KubeConfigMapNameNetworkConfig, strings.Join(t.Hosts, ", "))
+			return nil, err
+		}
+	}
+	return
+}
+
+// ParseIngressDefaultBackend parses the default backend of the network config,
+// which is nil when the key isn't set.
+func ParseIngressDefaultBackend(configMap *corev1.ConfigMap) (backend *IngressDefaultBackend, err error) {
+	backend_ := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressDefaultBackend])
+	if backend_ == "" {
+		return
+	}
+	backend = &IngressDefaultBackend{}
+	err = json.Unmarshal([]byte(backend_), backend)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to json unmarshal %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigIngressDefaultBackend, consts.KubeConfigMapNameNetworkConfig, backend_)
+		return nil, err
+	}
+
+	if (backend.Port == 0) == (backend.PortName == "") {
+		err = errors.Errorf("the %s in configmap %s must set exactly one of port and portName", consts.KubeConfigMapKeyNetworkConfigIngressDefaultBackend, consts.KubeConfigMapNameNetworkConfig)
+		return nil, err
+	}
+	errs := validation.IsDNS1035Label(backend.Service)
+	if backend.Port != 0 {
+		errs = append(errs, validation.IsValidPortNum(int(backend.Port))...)
+	} else {
+		errs = append(errs, validation.IsValidPortName(backend.PortName)...)
+	}
+	if len(errs) > 0 {
+		err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigIngressDefaultBackend, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+		return nil, err
+	}
+	return
+}
+
+// IngressTLS returns the TLS section of the generated ingresses.
+func (c *IngressConfig) IngressTLS() []networkingv1.IngressTLS {
+	if len(c.TLS) == 0 {
+		return nil
+	}
+	tls := make([]networkingv1.IngressTLS, 0, len(c.TLS))
+	for _, t := range c.TLS {
+		tls = append(tls, networkingv1.IngressTLS{
+			Hosts:      t.Hosts,
+			SecretName: t.SecretName,
+		})
+	}
+	return tls
+}
+
+// reservedProbeAnnotations are the annotation keys the operator manages on the
+// probe ingress, which can't be set from the network config.
+var reservedProbeAnnotations = []string{
+	consts.KubeLabelManagedBy,
+	consts.KubeAnnotationDynamoDiscoveryCorrelationID,
+	consts.KubeAnnotationDynamoProbeIngressTTL,
+	consts.KubeAnnotationDynamoPersistentProbeIngress,
+}
+
+// StripReservedAnnotations removes the annotations reserved for the operator, so
+// that its own values take precedence, and returns the removed keys.
+func (c *IngressConfig) StripReservedAnnotations() (conflicts []string) {
+	for _, key := range reservedProbeAnnotations {
+		if _, ok := c.Annotations[key]; ok {
+			delete(c.Annotations, key)
+			conflicts = append(conflicts, key)
+		}
+	}
+	return
+}
+
+// GetIngressConfig fetches the network configmap and parses its ingress config,
+// see ParseIngressConfig. The keys of the configmap that aren't recognized are
+// logged as a warning, see ValidateNetworkConfigKeys.
 func GetIngressConfig(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) (ingressConfig *IngressConfig, err error) {
-	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	configMap, err := getNetworkConfigConfigMapOrDefault(ctx, configmapGetter)
+	if err != nil {
+		return
+	}
+	logger := contextLogger(ctx)
+	warnUnknownNetworkConfigKeys(logger, configMap)
+	ingressConfig, err = ParseIngressConfig(configMap)
+	if err != nil {
+		return
+	}
+	ingressConfig.LogStrippedAnnotations(logger, configMap)
+	return
+}
+
+// ConfigSource is where the value of a field of the effective config comes from.
+type ConfigSource string
+
+const (
+	// ConfigSourceConfig is a value set in the network config.
+	ConfigSourceConfig ConfigSource = "config"
+	// ConfigSourceDefault is a value defaulted by the operator.
+	ConfigSourceDefault ConfigSource = "default"
+)
+
+// GetIngressConfigWithSource is GetIngressConfig also returning the source of each
+// field of the IngressConfig, keyed by field name, to explain the effective config.
+func GetIngressConfigWithSource(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) (ingressConfig *IngressConfig, sources map[string]ConfigSource, err error) {
+	configMap, err := getNetworkConfigConfigMapOrDefault(ctx, configmapGetter)
+	if err != nil {
+		return
+	}
+
+	networkConfig, err := parseNetworkConfig(configMap)
 	if err != nil {
-		err = errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
 		return
 	}
+	ingressConfig = networkConfig.Ingress
+	sources = ingressConfigSources(configMap)
+	return
+}
+
+func ingressConfigSources(configMap *corev1.ConfigMap) map[string]ConfigSource {
+	source := func(key string) ConfigSource {
+		if strings.TrimSpace(configMap.Data[key]) != "" {
+			return ConfigSourceConfig
+		}
+		return ConfigSourceDefault
+	}
+	return map[string]ConfigSource{
+		"ClassName":         source(consts.KubeConfigMapKeyNetworkConfigIngressClass),
+		"Annotations":       annotationsSource(configMap),
+		"RemoveAnnotations": source(consts.KubeConfigMapKeyNetworkConfigIngressRemoveAnnotations),
+		"Path":              source(consts.KubeConfigMapKeyNetworkConfigIngressPath),
+		"PathType":          source(consts.KubeConfigMapKeyNetworkConfigIngressPathType),
+		"Paths":             source(consts.KubeConfigMapKeyNetworkConfigIngressPaths),
+		"TLS":               source(consts.KubeConfigMapKeyNetworkConfigIngressTLS),
+		"BackendProtocol":   source(consts.KubeConfigMapKeyNetworkConfigIngressBackendProtocol),
+		"DefaultBackend":    source(consts.KubeConfigMapKeyNetworkConfigIngressDefaultBackend),
+		"CertManagerIssuer": source(consts.KubeConfigMapKeyNetworkConfigCertManagerIssuer),
+		"RewriteTarget":     source(consts.KubeConfigMapKeyNetworkConfigIngressRewriteTarget),
+		"RewriteMiddleware": source(consts.KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware),
+	}
+}
 
+// ParseIngressConfig parses the ingress config of the network configmap, for the
+// callers that already have it at hand. The annotations are checked against the
+// allowlist, see SetAnnotationAllowlist, and the default annotations are merged
+// in.
+func ParseIngressConfig(configMap *corev1.ConfigMap) (ingressConfig *IngressConfig, err error) {
 	var className *string
 
 	className_ := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressClass])
@@ -61,21 +528,35 @@ func GetIngressConfig(ctx context.Context, configmapGetter func(ctx context.Cont
 		className = &className_
 	}
 
-	annotations := make(map[string]string)
+	annotations, annotationsExplicit, err := ParseIngressAnnotations(configMap)
+	if err != nil {
+		return
+	}
+	annotations, strippedAnnotations, err := FilterAllowedAnnotations(configMap, annotations)
+	if err != nil {
+		return
+	}
 
-	annotations_ := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressAnnotations])
-	if annotations_ != "" {
-		err = json.Unmarshal([]byte(annotations_), &annotations)
-		if err != nil {
-			err = errors.Wrapf(err, "failed to json unmarshal %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigIngressAnnotations, consts.KubeConfigMapNameNetworkConfig, annotations_)
+	var removeAnnotations []string
+	for _, key := range strings.Split(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressRemoveAnnotations], ",") {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+			err = errors.Errorf("invalid %s in configmap %s, annotation key %q is invalid: %s", consts.KubeConfigMapKeyNetworkConfigIngressRemoveAnnotations, consts.KubeConfigMapNameNetworkConfig, key, strings.Join(errs, ", "))
 			return
 		}
+		removeAnnotations = append(removeAnnotations, key)
 	}
 
 	path := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressPath])
 	if path == "" {
 		path = "/"
 	}
+	if err = ValidateIngressPath(path); err != nil {
+		err = errors.Wrapf(err, "invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigIngressPath, consts.KubeConfigMapNameNetworkConfig)
+		return
+	}
 
 	pathType := networkingv1.PathTypeImplementationSpecific
 
@@ -84,61 +565,830 @@ func GetIngressConfig(ctx context.Context, configmapGetter func(ctx context.Cont
 		pathType = networkingv1.PathType(pathType_)
 	}
 
+	paths, err := ParseIngressPaths(configMap)
+	if err != nil {
+		return
+	}
+
+	backendProtocol, err := ParseBackendProtocol(configMap)
+	if err != nil {
+		return
+	}
+
+	defaultBackend, err := ParseIngressDefaultBackend(configMap)
+	if err != nil {
+		return
+	}
+
+	certManagerIssuer, err := ParseCertManagerIssuer(configMap)
+	if err != nil {
+		return
+	}
+
+	rewriteTarget, rewriteMiddleware, err := ParseRewriteTarget(configMap)
+	if err != nil {
+		return
+	}
+
+	tls, err := ParseIngressTLS(configMap)
+	if err != nil {
+		return
+	}
+
 	ingressConfig = &IngressConfig{
-		ClassName:   className,
-		Annotations: annotations,
-		Path:        path,
-		PathType:    pathType,
+		ClassName:           className,
+		Annotations:         annotations,
+		AnnotationsExplicit: annotationsExplicit,
+		RemoveAnnotations:   removeAnnotations,
+		Path:                path,
+		PathType:            pathType,
+		PathTypeExplicit:    pathType_ != "",
+		Paths:               paths,
+		TLS:                 tls,
+		BackendProtocol:     backendProtocol,
+		DefaultBackend:      defaultBackend,
+		CertManagerIssuer:   certManagerIssuer,
+		RewriteTarget:       rewriteTarget,
+		RewriteMiddleware:   rewriteMiddleware,
+		strippedAnnotations: strippedAnnotations,
+	}
+	ingressConfig.MergeDefaultAnnotations(getDefaultAnnotations())
+	ingressConfig.removeAnnotations()
+
+	// The API server would only reject them with the probe ingress, and with a
+	// less telling error.
+	if err = apivalidation.ValidateAnnotationsSize(ingressConfig.Annotations); err != nil {
+		err = errors.Wrapf(err, "the ingress annotations of configmap %s exceed the size limit of the K8s object metadata", consts.KubeConfigMapNameNetworkConfig)
+		ingressConfig = nil
+		return
 	}
 
 	return
 }
 
-func GetIngressIP(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset *kubernetes.Clientset) (ip string, err error) {
-	ingressConfig, err := GetIngressConfig(ctx, configmapGetter)
+// ParseIngressConfigFromMap is ParseIngressConfig for the network config data
+// stored elsewhere than in the network configmap, e.g. in the spec of a custom
+// resource, with the keys of the network configmap.
+func ParseIngressConfigFromMap(data map[string]string) (*IngressConfig, error) {
+	return ParseIngressConfig(&corev1.ConfigMap{Data: data})
+}
+
+// ParseIngressAnnotations parses the ingress annotations of the network config:
+// the JSON object of the ingress-annotations key, layered over the one of the
+// ingress-annotations-base key, e.g. shared by the environments, and the flat
+// entries of the `ingress.annotation.` prefix, which win over the JSON ones.
+// Since a configmap key can't contain a `/`, the first `_` of a flat entry
+// separates the prefix of the annotation key from its name:
+//
+//	ingress.annotation.nginx.ingress.kubernetes.io_ssl-redirect: "false"
+//
+// is the nginx.ingress.kubernetes.io/ssl-redirect annotation. explicit reports
+// whether any annotation was set, even if only to an empty JSON object.
+func ParseIngressAnnotations(configMap *corev1.ConfigMap) (annotations map[string]string, explicit bool, err error) {
+	annotations = make(map[string]string)
+
+	for _, configKey := range []string{consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase, consts.KubeConfigMapKeyNetworkConfigIngressAnnotations} {
+		annotations_ := strings.TrimSpace(configMap.Data[configKey])
+		if annotations_ == "" {
+			continue
+		}
+		explicit = true
+		var layer map[string]string
+		layer, err = parseIngressAnnotations(annotations_)
+		if err != nil {
+			err = errors.Wrapf(err, "invalid %s in configmap %s", configKey, consts.KubeConfigMapNameNetworkConfig)
+			return nil, false, err
+		}
+		for key, annotation := range layer {
+			annotations[key] = annotation
+		}
+	}
+
+	for configKey, annotation := range configMap.Data {
+		name, ok := strings.CutPrefix(configKey, consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation)
+		if !ok {
+			continue
+		}
+		explicit = true
+		key := strings.Replace(name, "_", "/", 1)
+		if err = validateIngressAnnotation(key, annotation); err != nil {
+			err = errors.Wrapf(err, "invalid %s in configmap %s", configKey, consts.KubeConfigMapNameNetworkConfig)
+			return nil, false, err
+		}
+		annotations[key] = annotation
+	}
+	return
+}
+
+// annotationsSource returns where the ingress annotations come from.
+func annotationsSource(configMap *corev1.ConfigMap) ConfigSource {
+	for key, value := range configMap.Data {
+		if (key == consts.KubeConfigMapKeyNetworkConfigIngressAnnotations || key == consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase) && strings.TrimSpace(value) != "" {
+			return ConfigSourceConfig
+		}
+		if strings.HasPrefix(key, consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation) {
+			return ConfigSourceConfig
+		}
+	}
+	return ConfigSourceDefault
+}
+
+// parseIngressAnnotations parses the JSON object of the ingress annotations,
+// reporting the offending key rather than the whole object when a key isn't a
+// valid annotation key or a value isn't a non-empty string.
+func parseIngressAnnotations(value string) (map[string]string, error) {
+	// An array or a scalar is a common copy-paste mistake, that would
+	// otherwise fail with an opaque unmarshaling error.
+	if kind := jsonValueKind(value); kind != "" {
+		return nil, errors.Errorf("ingress annotations must be a JSON object, not %s", kind)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, errors.Wrapf(err, "malformed JSON at offset %d", syntaxErr.Offset)
+		}
+		return nil, errors.Wrap(err, "not a JSON object of annotations")
+	}
+
+	annotations := make(map[string]string, len(raw))
+	for key, rawValue := range raw {
+		var annotation string
+		if err := json.Unmarshal(rawValue, &annotation); err != nil {
+			return nil, errors.Errorf("the value of annotation %q is not a string: %s", key, rawValue)
+		}
+		if err := validateIngressAnnotation(key, annotation); err != nil {
+			return nil, err
+		}
+		annotations[key] = annotation
+	}
+	return annotations, nil
+}
+
+// jsonValueKind returns the kind of the JSON value, or "" if it is an object,
+// null or malformed, which are left to json.Unmarshal.
+func jsonValueKind(value string) string {
+	token, err := json.NewDecoder(strings.NewReader(value)).Token()
+	if err != nil {
+		return ""
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			return "an array"
+		}
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return ""
+}
+
+func validateIngressAnnotation(key, annotation string) error {
+	if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+		return errors.Errorf("annotation key %q is invalid: %s", key, strings.Join(errs, ", "))
+	}
+	if annotation == "" {
+		return errors.Errorf("the value of annotation %q is empty", key)
+	}
+	// The templates are checked here so that a typo fails like any other
+	// invalid config, not only when an ingress is built.
+	_, err := renderAnnotation(key, annotation, AnnotationTemplateData{})
+	return err
+}
+
+// GetIngressIP discovers the IP of the ingress load balancer, or its hostname if
+// discovery-prefer-hostname is set in the network config.
+func GetIngressIP(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (ip string, err error) {
+	return GetIngressIPWithOptions(ctx, IngressIPOptions{Clientset: cliset, ConfigMapGetter: configmapGetter})
+}
+
+// GetIngressAddress is GetIngressIP, also returning the hostname the load balancer
+// reports, e.g. to point a CNAME record at, and whether the IP was resolved from
+// it.
+func GetIngressAddress(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (address IngressAddress, err error) {
+	addresses, err := getIngressAddresses(ctx, configmapGetter, cliset)
+	if err != nil {
+		return
+	}
+	address = addresses[0]
+	return
+}
+
+// GetIngressIPs discovers every address of the ingress load balancer: the IPs of
+// its status, and the resolved hostnames. The addresses are deduplicated and
+// sorted, the preferred address family first.
+func GetIngressIPs(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (ips []string, err error) {
+	addresses, err := getIngressAddresses(ctx, configmapGetter, cliset)
+	if err != nil {
+		return
+	}
+	ips = addressIPs(addresses)
+	return
+}
+
+func getIngressAddresses(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (addresses []IngressAddress, err error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		err = withFields(errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig), "namespace", namespaceFromContext(ctx), "correlation_id", correlationID)
+		return
+	}
+
+	return discoverIngressAddresses(ctx, configMap, cliset)
+}
+
+// discoverIngressAddresses is getIngressAddresses with the network configmap
+// already fetched.
+func discoverIngressAddresses(ctx context.Context, configMap *corev1.ConfigMap, cliset kubernetes.Interface) (addresses []IngressAddress, err error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	namespace := namespaceFromContext(ctx)
+	logger := discoveryLogger(ctx, correlationID).WithValues("namespace", namespace)
+	ctx, span := startSpan(ctx, spanGetIngressIP, spanAttrNamespace.String(namespace))
+
+	var probeName string
+	start := time.Now()
+	defer func() {
+		recordDetection(start, err)
+		err = withFields(err, "namespace", namespace, "correlation_id", correlationID)
+		if probeName != "" {
+			err = withFields(err, "probe_ingress", probeName)
+			span.SetAttributes(spanAttrProbeIngress.String(probeName))
+		}
+		endSpan(span, err)
+	}()
+
+	ingressConfig, err := ParseIngressConfig(configMap)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to get ingress config")
 		return
 	}
+	ingressConfig.LogStrippedAnnotations(logger, configMap)
+
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get discovery config")
+		return
+	}
+	for _, ignoredErr := range discoveryConfig.ignoredErrs {
+		logger.Error(ignoredErr, "Ignoring an invalid discovery tunable of the network config")
+	}
+
+	if discoveryConfig.ExternalIP != "" {
+		logger.Info("Using the external IP of the network config", "ip", discoveryConfig.ExternalIP)
+		addresses = []IngressAddress{{IP: discoveryConfig.ExternalIP}}
+		return
+	}
+
+	if discoveryConfig.NetworkMode == NetworkModeClusterIP {
+		controllerType := GetIngressControllerType(ctx, cliset, ingressConfig.ClassName)
+		recordConfigInfo(namespace, ingressConfig.ClassName, controllerType, discoveryConfig.NetworkMode)
+		var ip string
+		ip, err = getIngressControllerClusterIP(ctx, cliset, discoveryConfig, ingressConfig.ClassName)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get the ingress controller cluster IP")
+			return
+		}
+		addresses = []IngressAddress{{IP: ip}}
+		return
+	}
+
+	if err = checkDeadlineBudget(ctx, discoveryConfig); err != nil {
+		return
+	}
+
+	release, err := acquireDiscoverySlot(ctx)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	// The events of the probe ingress are listed with the parent context, so
+	// that they are reported even when the provisioning budget runs out.
+	parentCtx := ctx
+	if discoveryConfig.ProvisioningBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, discoveryConfig.ProvisioningBudget)
+		defer cancel()
+	}
+
+	if discoveryConfig.NetworkMode == NetworkModeGateway {
+		recordConfigInfo(namespace, discoveryConfig.GatewayClass, IngressControllerUnknown, discoveryConfig.NetworkMode)
+		addresses, err = getGatewayAddresses(ctx, logger, namespace, correlationID, discoveryConfig)
+		return
+	}
 
 	ingressClassName := ingressConfig.ClassName
-	ingressAnnotations := ingressConfig.Annotations
 
-	ingressCli := cliset.NetworkingV1().Ingresses(GetNamespace())
+	if ingressClassName == nil && len(discoveryConfig.ProbeIngressClasses) == 0 {
+		var defaultClass *IngressClassInfo
+		defaultClass, err = GetDefaultIngressClass(ctx, cliset)
+		if err != nil {
+			// The probe may still be admitted, so let the discovery run.
+			logger.Error(err, "Skipping the default ingress class check")
+			err = nil
+		} else if defaultClass == nil {
+			err = ErrNoDefaultIngressClass
+			return
+		} else {
+			// The probe names the class rather than relying on the admission
+			// defaulting it, so that the checks below apply to it too.
+			logger.Info("No ingress class is configured, so the default ingress class of the cluster is used", "ingressClass", defaultClass.Name)
+			ingressClassName = &defaultClass.Name
+			ingressConfig.ClassName = ingressClassName
+		}
+	}
 
-	ingName := "default-domain-"
-	pathType := networkingv1.PathTypeImplementationSpecific
+	if ingressClassName != nil {
+		span.SetAttributes(spanAttrIngressClass.String(*ingressClassName))
+	} else if len(discoveryConfig.ProbeIngressClasses) > 0 {
+		span.SetAttributes(spanAttrIngressClass.String(strings.Join(discoveryConfig.ProbeIngressClasses, ",")))
+	}
+
+	if len(discoveryConfig.ProbeIngressClasses) > 0 {
+		// The preflight and controller checks are skipped, as they can't tell
+		// which of the classes is going to get an address.
+		addresses, probeName, err = discoverMultiClassIngressAddresses(ctx, parentCtx, logger, configMap, cliset, namespace, correlationID, ingressConfig, discoveryConfig)
+		return
+	}
+
+	if ingressClassName != nil && discoveryConfig.IngressClassCheck {
+		if err = ValidateIngressClass(ctx, cliset, *ingressClassName); err != nil {
+			err = errors.Wrapf(err, "failed to check the ingress class, %s in configmap %s turns the check off", consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassCheck, consts.KubeConfigMapNameNetworkConfig)
+			return
+		}
+	}
+
+	if discoveryConfig.Preflight {
+		if err = PreflightDiscovery(ctx, cliset, ingressClassName); err != nil {
+			var preflightErr *PreflightError
+			if discoveryConfig.NodeAddressFallback == "" || !errors.As(err, &preflightErr) || preflightErr.Reason != PreflightReasonNoLoadBalancerProvider {
+				return
+			}
+			// No probe would ever get an address.
+			logger.Info("No load balancer provider is found, falling back to the node addresses", "addressType", discoveryConfig.NodeAddressFallback)
+			addresses, err = getNodeAddresses(ctx, cliset, discoveryConfig.NodeAddressFallback)
+			return
+		}
+	}
+
+	if discoveryConfig.ControllerCheck {
+		if err = CheckIngressControllerReady(ctx, cliset, ingressClassName, discoveryConfig.ControllerSelector); err != nil {
+			return
+		}
+	}
+
+	controllerType := GetIngressControllerType(ctx, cliset, ingressClassName)
+	recordConfigInfo(namespace, ingressClassName, controllerType, discoveryConfig.NetworkMode)
+	probeNamespace, err := getProbeNamespace(ctx, cliset, namespace, discoveryConfig)
+	if err != nil {
+		return
+	}
+	discoveryConfig = lookupProbeBackendPort(ctx, logger, cliset, probeNamespace, discoveryConfig)
+	probe, err := renderProbeIngress(logger, probeNamespace, correlationID, ingressConfig, discoveryConfig, controllerType)
+	if err != nil {
+		return
+	}
+	if owner, ok := probeOwnerFromContext(ctx); ok {
+		probe.OwnerReferences = []metav1.OwnerReference{owner}
+	}
+
+	ingressCli := getIngressClient(cliset, probeNamespace)
+	readinessChecker := getReadinessChecker(controllerType, discoveryConfig)
+
+	var ing *networkingv1.Ingress
+	if discoveryConfig.ProbeIngress != "" {
+		logger.Info("Reading the existing ingress to get a ingress IP automatically", "ingress", discoveryConfig.ProbeIngress)
+		ing, err = ingressCli.Get(ctx, discoveryConfig.ProbeIngress, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			err = errors.Wrapf(err, "the ingress %s/%s set in %s of configmap %s does not exist, and it is never created", probeNamespace, discoveryConfig.ProbeIngress, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress, consts.KubeConfigMapNameNetworkConfig)
+			return
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get ingress %s", discoveryConfig.ProbeIngress)
+			return
+		}
+		probeName = ing.Name
+	} else if discoveryConfig.PersistentProbe {
+		logger.Info("Applying the persistent ingress to get a ingress IP automatically", "ingress", probe.Name)
+		err = traceSpan(ctx, spanCreateProbeIngress, func(ctx context.Context) error {
+			return retryCreate(discoveryConfig.CreateRetries, func() (err error) {
+				ing, err = applyPersistentProbeIngress(ctx, ingressCli, probe)
+				return
+			})
+		}, spanAttrProbeIngress.String(probe.Name))
+		if err != nil {
+			err = &ProbeCreateError{Kind: "ingress", Name: probe.Name, Err: err}
+			return
+		}
+		probeName = ing.Name
+	} else if discoveryConfig.ReuseProbe {
+		ing, err = findReusableProbeIngress(ctx, ingressCli)
+		if err != nil {
+			return
+		}
+		if ing != nil {
+			logger.Info("Reusing the ingress to get a ingress IP automatically", "ingress", ing.Name)
+		} else {
+			logger.Info("Creating the reusable ingress to get a ingress IP automatically", "ingress", probe.GenerateName)
+			recordEventf(configMap, corev1.EventTypeNormal, EventReasonCreatingProbeIngress, "Creating the probe ingress %s to discover the domain suffix", probe.GenerateName)
+			err = traceSpan(ctx, spanCreateProbeIngress, func(ctx context.Context) error {
+				return retryCreate(discoveryConfig.CreateRetries, func() (err error) {
+					ing, err = ingressCli.Create(ctx, probe, metav1.CreateOptions{})
+					return
+				})
+			}, spanAttrProbeIngress.String(probe.GenerateName))
+			if err != nil {
+				err = &ProbeCreateError{Kind: "ingress", Name: probe.GenerateName, Err: err}
+				return
+			}
+		}
+		probeName = ing.Name
+	} else if discoveryConfig.ProbeHosts > 1 {
+		var shards []*networkingv1.Ingress
+		shards, err = probeIngressShards(logger, probe, discoveryConfig.ProbeHosts)
+		if err != nil {
+			return
+		}
+		names := make([]string, 0, len(shards))
+		for _, shard := range shards {
+			logger.Info("Creating ingress to get a ingress IP automatically", "ingress", shard.GenerateName, "host", shard.Spec.Rules[0].Host)
+			recordEventf(configMap, corev1.EventTypeNormal, EventReasonCreatingProbeIngress, "Creating the probe ingress %s to discover the domain suffix", shard.GenerateName)
+			var created *networkingv1.Ingress
+			err = traceSpan(ctx, spanCreateProbeIngress, func(ctx context.Context) error {
+				return retryCreate(discoveryConfig.CreateRetries, func() (err error) {
+					created, err = ingressCli.Create(ctx, shard, metav1.CreateOptions{})
+					return
+				})
+			}, spanAttrProbeIngress.String(shard.GenerateName))
+			if err != nil {
+				err = &ProbeCreateError{Kind: "ingress", Name: shard.GenerateName, Err: err}
+				return
+			}
+			var done func()
+			ctx, done, err = trackProbeIngress(ctx, cliset, probeNamespace, created.Name)
+			if err != nil {
+				return
+			}
+			defer done()
+			names = append(names, created.Name)
+		}
+		probeName = strings.Join(names, ",")
+
+		logger.Info("Waiting for any of the ingresses to be ready", "ingresses", names)
+		recordEventf(configMap, corev1.EventTypeNormal, EventReasonWaitingForLoadBalancer, "Waiting for the load balancer of any of the probe ingresses %s", probeName)
+		err = traceWait(ctx, spanWaitForIngressReady, func(ctx context.Context) (err error) {
+			ing, err = waitForAnyIngressReady(ctx, ingressCli, names, discoveryConfig.PollInterval, discoveryConfig.WaitTimeout, discoveryConfig.PollImmediately, readinessChecker)
+			return
+		}, spanAttrProbeIngress.String(probeName))
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				recordEventf(configMap, corev1.EventTypeWarning, EventReasonDomainSuffixTimeout, "None of the probe ingresses %s got a load balancer address in time", probeName)
+			}
+			err = errors.Wrapf(err, "failed to wait for any of the ingresses %s to be ready", probeName)
+			return
+		}
+		probeName = ing.Name
+	} else {
+		logger.Info("Creating ingress to get a ingress IP automatically", "ingress", probe.GenerateName)
+		recordEventf(configMap, corev1.EventTypeNormal, EventReasonCreatingProbeIngress, "Creating the probe ingress %s to discover the domain suffix", probe.GenerateName)
+		err = traceSpan(ctx, spanCreateProbeIngress, func(ctx context.Context) error {
+			return retryCreate(discoveryConfig.CreateRetries, func() (err error) {
+				ing, err = ingressCli.Create(ctx, probe, metav1.CreateOptions{})
+				return
+			})
+		}, spanAttrProbeIngress.String(probe.GenerateName))
+		if k8serrors.IsAlreadyExists(err) {
+			// The existing ingress is another discovery's, so it is used but
+			// left for its owner, or the probe GC, to delete.
+			var existing *networkingv1.Ingress
+			existing, err = getExistingProbeIngress(ctx, ingressCli, probe, err)
+			if err != nil {
+				return
+			}
+			logger.Info("The ingress already exists, so it is used instead", "ingress", existing.Name)
+			ing = existing
+		} else if err != nil {
+			err = &ProbeCreateError{Kind: "ingress", Name: probe.GenerateName, Err: err}
+			return
+		} else {
+			var done func()
+			ctx, done, err = trackProbeIngress(ctx, cliset, probeNamespace, ing.Name)
+			if err != nil {
+				return
+			}
+			defer done()
+		}
+		probeName = ing.Name
+	}
+
+	logger.Info("Waiting for ingress to be ready", "ingress", ing.Name)
+	recordEventf(configMap, corev1.EventTypeNormal, EventReasonWaitingForLoadBalancer, "Waiting for the load balancer of the probe ingress %s", ing.Name)
+	statusOwner := fmt.Sprintf("the ingress %s", ing.Name)
+	// Wait for the Ingress to be Ready, unless a reused one already is.
+	if readyIng, ok := readyIngress(readinessChecker, ing); ok {
+		ing = readyIng
+		logger.Info("Ingress is already ready", "ingress", ing.Name)
+	} else if err = traceWait(ctx, spanWaitForIngressReady, func(ctx context.Context) error {
+		if discoveryConfig.ReadyCondition == "" && discoveryConfig.ServiceStatusFallback > 0 {
+			fallbackAt := time.Now().Add(discoveryConfig.ServiceStatusFallback)
+			return pollProbe(ctx, discoveryConfig, func(ctx context.Context) (done bool, err error) {
+				ing, err = ingressCli.Get(ctx, ing.Name, metav1.GetOptions{})
+				if err != nil {
+					return true, err
+				}
+				if hasLoadBalancerAddress(ing.Status.LoadBalancer.Ingress) || time.Now().Before(fallbackAt) {
+					return hasLoadBalancerAddress(ing.Status.LoadBalancer.Ingress), nil
+				}
+				svc, err := findIngressControllerLoadBalancerService(ctx, cliset, discoveryConfig, ingressClassName)
+				if err != nil {
+					logger.Error(err, "Cannot fall back to the ingress controller service status")
+					return false, nil
+				}
+				if entries := serviceLoadBalancerEntries(svc); hasLoadBalancerAddress(entries) {
+					logger.Info("Falling back to the load balancer status of the ingress controller service", "ingress", ing.Name, "service", svc.Namespace+"/"+svc.Name)
+					ing = ing.DeepCopy()
+					ing.Status.LoadBalancer.Ingress = entries
+					statusOwner = fmt.Sprintf("the service %s/%s", svc.Namespace, svc.Name)
+					return true, nil
+				}
+				return false, nil
+			})
+		}
+		if discoveryConfig.ReadyCondition == "" && discoveryConfig.Watch {
+			readyIng, err := watchOrWaitForIngressReady(ctx, logger, ingressCli, ing.Name, discoveryConfig, readinessChecker)
+			if err != nil {
+				return err
+			}
+			ing = readyIng
+			return nil
+		}
+		if discoveryConfig.ReadyCondition == "" {
+			readyIng, err := waitForIngressReady(ctx, ingressCli, ing.Name, discoveryConfig.PollInterval, discoveryConfig.WaitTimeout, discoveryConfig.PollImmediately, readinessChecker)
+			if err != nil {
+				return err
+			}
+			ing = readyIng
+			return nil
+		}
+
+		return pollProbe(ctx, discoveryConfig, func(ctx context.Context) (done bool, err error) {
+			// Some controllers report readiness through a status condition
+			// before (or instead of) populating the load balancer status.
+			var conditions []metav1.Condition
+			ing, conditions, err = getIngressWithConditions(ctx, cliset, probeNamespace, ing.Name)
+			if err != nil {
+				return true, err
+			}
+			return hasLoadBalancerAddress(ing.Status.LoadBalancer.Ingress) || isConditionTrue(conditions, discoveryConfig.ReadyCondition), nil
+		})
+	}, spanAttrProbeIngress.String(ing.Name)); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			recordEventf(configMap, corev1.EventTypeWarning, EventReasonDomainSuffixTimeout, "The probe ingress %s got no load balancer address in time", ing.Name)
+			if discoveryConfig.NodeAddressFallback != "" {
+				// The provisioning budget may be what ran out.
+				nodeAddresses, nodeErr := getNodeAddresses(parentCtx, cliset, discoveryConfig.NodeAddressFallback)
+				if nodeErr == nil {
+					logger.Info("The probe ingress got no load balancer address in time, falling back to the node addresses", "ingress", ing.Name, "addressType", discoveryConfig.NodeAddressFallback)
+					addresses, err = nodeAddresses, nil
+					return
+				}
+				logger.Error(nodeErr, "Cannot fall back to the node addresses")
+			}
+		}
+		// The controller often explains why it didn't admit the probe in an
+		// event, which is more useful than a bare timeout.
+		if event := describeProbeIngressEvent(parentCtx, cliset, probeNamespace, ing.Name); event != "" {
+			err = errors.Wrapf(err, "failed to wait for ingress %s to be ready, last event: %s", ing.Name, event)
+			return
+		}
+		err = errors.Wrapf(err, "failed to wait for ingress %s to be ready", ing.Name)
+		return
+	}
+	logger.Info("Ingress is ready", "ingress", ing.Name)
+
+	if !hasLoadBalancerAddress(ing.Status.LoadBalancer.Ingress) {
+		err = errors.Wrapf(ErrIngressNoAddress, "the ingress %s reports the %s condition", ing.Name, discoveryConfig.ReadyCondition)
+		return
+	}
+
+	err = traceSpan(ctx, spanResolveIngressAddresses, func(ctx context.Context) (err error) {
+		addresses, err = resolveLoadBalancerAddresses(ctx, ing.Status.LoadBalancer.Ingress, statusOwner, discoveryConfig)
+		return
+	}, spanAttrProbeIngress.String(ing.Name))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the address of %s", statusOwner)
+		return
+	}
+
+	var probeHost string
+	if len(ing.Spec.Rules) > 0 {
+		probeHost = ing.Spec.Rules[0].Host
+	}
+	if err = waitForAddressesReachable(ctx, logger, addressIPs(addresses), probeHost, discoveryConfig); err != nil {
+		addresses = nil
+		return
+	}
+
+	return
+}
+
+// getProbeNamespace returns the namespace the probe ingress is created in,
+// checking that the operator may create them there if it isn't the one of the
+// discovery.
+func getProbeNamespace(ctx context.Context, cliset kubernetes.Interface, namespace string, discoveryConfig *discoveryConfig) (string, error) {
+	if discoveryConfig.ProbeNamespace == "" || discoveryConfig.ProbeNamespace == namespace {
+		return namespace, nil
+	}
+	if err := checkCanCreateIngresses(ctx, cliset, discoveryConfig.ProbeNamespace); err != nil {
+		return "", errors.Wrapf(err, "cannot create the probe ingress in the namespace %s set in %s of configmap %s", discoveryConfig.ProbeNamespace, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace, consts.KubeConfigMapNameNetworkConfig)
+	}
+	return discoveryConfig.ProbeNamespace, nil
+}
+
+// renderProbeHost returns the host of the probe rule, or an empty string for a
+// catch-all rule.
+func renderProbeHost(logger logr.Logger, namespace string, discoveryConfig *discoveryConfig) (string, error) {
+	if discoveryConfig.ProbeCatchAll {
+		// A rule without a host matches every request the controller doesn't
+		// route otherwise, so this only lives as long as the probe does.
+		return "", nil
+	}
 
 	podName := os.Getenv("POD_NAME")
-	if podName == "" {
+	if discoveryConfig.ProbeHostSeed != "" {
+		// The same host for every discovery of the namespace, whatever the
+		// pod, so that their probe ingresses can be deduplicated.
+		podName = deterministicProbeHostLabel(namespace, discoveryConfig.ProbeHostSeed)
+	} else if podName == "" {
 		// random string
 		guid := xid.New()
 		podName = fmt.Sprintf("a%s", strings.ToLower(guid.String()))
 	}
 
-	logrus.Infof("Creating ingress %s to get a ingress IP automatically", ingName)
-	ing, err := ingressCli.Create(ctx, &networkingv1.Ingress{
+	if discoveryConfig.PersistentProbe {
+		// The persistent probe is updated in place, so its host must not
+		// change between discoveries.
+		podName = persistentProbeHostLabel
+	}
+
+	return buildProbeHost(logger, podName, discoveryConfig.ProbeHostSuffix)
+}
+
+// WaitForIngressReady waits for the ingress name to be programmed, that is for its
+// status to report a load balancer address, polling every pollInterval until
+// timeout. It returns the ready ingress so that callers can inspect its status.
+func WaitForIngressReady(ctx context.Context, ingressCli IngressClient, name string, pollInterval, timeout time.Duration) (*networkingv1.Ingress, error) {
+	return waitForIngressReady(ctx, ingressCli, name, pollInterval, timeout, true, LoadBalancerStatusChecker{})
+}
+
+func waitForIngressReady(ctx context.Context, ingressCli IngressClient, name string, pollInterval, timeout time.Duration, immediate bool, checker ReadinessChecker) (ing *networkingv1.Ingress, err error) {
+	err = pollJittered(ctx, pollInterval, timeout, immediate, func(ctx context.Context) (bool, error) {
+		current, err := ingressCli.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return true, err
+		}
+		var ready bool
+		ing, ready = readyIngress(checker, current)
+		return ready, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ing, nil
+}
+
+// waitForAnyIngressReady is waitForIngressReady for the first of several
+// ingresses to be programmed.
+func waitForAnyIngressReady(ctx context.Context, ingressCli IngressClient, names []string, pollInterval, timeout time.Duration, immediate bool, checker ReadinessChecker) (ing *networkingv1.Ingress, err error) {
+	err = pollJittered(ctx, pollInterval, timeout, immediate, func(ctx context.Context) (bool, error) {
+		for _, name := range names {
+			candidate, err := ingressCli.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return true, err
+			}
+			if ready, ok := readyIngress(checker, candidate); ok {
+				ing = ready
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ing, nil
+}
+
+// ErrInsufficientTimeout is returned by the discoveries whose context has less
+// time left than the minimum budget, before they create anything.
+var ErrInsufficientTimeout = errors.New("the context deadline leaves too little time for the discovery")
+
+func checkDeadlineBudget(ctx context.Context, discoveryConfig *discoveryConfig) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	if left := time.Until(deadline); left < discoveryConfig.MinBudget {
+		return errors.Wrapf(ErrInsufficientTimeout, "%s left, less than the %s of %s in configmap %s", left.Round(time.Millisecond), discoveryConfig.MinBudget, consts.KubeConfigMapKeyNetworkConfigDiscoveryMinBudget, consts.KubeConfigMapNameNetworkConfig)
+	}
+	return nil
+}
+
+// pollProbe waits for the probe to be ready, as reported by condition, with the
+// poll interval and timeout of the discovery config.
+func pollProbe(ctx context.Context, discoveryConfig *discoveryConfig, condition wait.ConditionWithContextFunc) error {
+	return pollJittered(ctx, discoveryConfig.PollInterval, discoveryConfig.WaitTimeout, discoveryConfig.PollImmediately, condition)
+}
+
+// renderProbeIngress returns the probe ingress for the network config, with the
+// defaults of the ingress controller applied.
+func renderProbeIngress(logger logr.Logger, namespace, correlationID string, ingressConfig *IngressConfig, discoveryConfig *discoveryConfig, controllerType IngressControllerType) (*networkingv1.Ingress, error) {
+	ingressConfig.ApplyControllerDefaults(controllerType)
+	ingressConfig.InheritAnnotations(ControllerDefaultAnnotations(controllerType))
+	if !ingressConfig.ApplyLoadBalancerSettings(controllerType, discoveryConfig.LBScheme, discoveryConfig.LBSubnets) {
+		logger.Info("Ignoring the load balancer settings of the network config, the load balancer of the ingress controller is configured on its service", "keys", []string{consts.KubeConfigMapKeyNetworkConfigLBScheme, consts.KubeConfigMapKeyNetworkConfigLBSubnets}, "controllerType", controllerType)
+	}
+	if conflicts := ingressConfig.StripReservedAnnotations(); len(conflicts) > 0 {
+		logger.Info("Ignoring the annotations of the network config managed by the operator", "key", consts.KubeConfigMapKeyNetworkConfigIngressAnnotations, "annotations", conflicts)
+	}
+	ingName := probeIngressGenerateName
+	if discoveryConfig.PersistentProbe {
+		ingName = persistentProbeIngressName
+	}
+	ingressAnnotations, err := RenderAnnotations(ingressConfig.Annotations, AnnotationTemplateData{Namespace: namespace, IngressName: ingName})
+	if err != nil {
+		return nil, err
+	}
+	ingressAnnotations[consts.KubeAnnotationDynamoDiscoveryCorrelationID] = correlationID
+	if discoveryConfig.ProbeTTL > 0 {
+		ingressAnnotations[consts.KubeAnnotationDynamoProbeIngressTTL] = discoveryConfig.ProbeTTL.String()
+	}
+
+	// The probe is routed like the ingresses it stands for, so that it is
+	// admitted by the controllers restricting the path (type).
+	path := ingressConfig.Path
+	if path == "" {
+		path = "/"
+	}
+	pathType := ingressConfig.PathType
+	if pathType == "" {
+		pathType = networkingv1.PathTypeImplementationSpecific
+	}
+
+	// Whatever the network config, so that the probes can be attributed to the
+	// operator, e.g. for cost allocation, and selected.
+	ingressLabels := map[string]string{
+		consts.KubeLabelManagedBy:       consts.KubeLabelValueDynamoOperator,
+		consts.KubeLabelDynamoComponent: consts.KubeLabelValueDomainProbe,
+	}
+	if discoveryConfig.PersistentProbe || discoveryConfig.ReuseProbe {
+		// Both outlive the discovery, so the garbage collection only expires
+		// them with their TTL.
+		ingressAnnotations[consts.KubeAnnotationDynamoPersistentProbeIngress] = consts.KubeLabelValueTrue
+	}
+	if discoveryConfig.ReuseProbe {
+		ingressLabels[consts.KubeLabelDynamoPurpose] = consts.KubeLabelValueDomainProbe
+	}
+
+	probeHost, err := renderProbeHost(logger, namespace, discoveryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressClassName := ingressConfig.ClassName
+	if discoveryConfig.IngressClassMode == IngressClassModeAnnotation && ingressClassName != nil {
+		ingressAnnotations[consts.KubeAnnotationIngressClass] = *ingressClassName
+		ingressClassName = nil
+	}
+
+	probe := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: ingName,
-			Namespace:    GetNamespace(),
+			Namespace:    namespace,
+			Labels:       ingressLabels,
 			Annotations:  ingressAnnotations,
 		},
 		Spec: networkingv1.IngressSpec{
 			IngressClassName: ingressClassName,
+			TLS:              ingressConfig.IngressTLS(),
 			Rules: []networkingv1.IngressRule{{
-				Host: fmt.Sprintf("%s.this-is-yatai-in-order-to-generate-the-default-domain-suffix.yeah", podName),
+				Host: probeHost,
 				IngressRuleValue: networkingv1.IngressRuleValue{
 					HTTP: &networkingv1.HTTPIngressRuleValue{
 						Paths: []networkingv1.HTTPIngressPath{
 							{
-								Path:     "/",
+								Path:     path,
 								PathType: &pathType,
 								Backend: networkingv1.IngressBackend{
 									Service: &networkingv1.IngressServiceBackend{
-										Name: "default-domain-service",
-										Port: networkingv1.ServiceBackendPort{
-											Number: consts.BentoServicePort,
-										},
+										Name: discoveryConfig.ProbeBackendService,
+										Port: discoveryConfig.probeBackendPort(),
 									},
 								},
 							},
@@ -147,87 +1397,302 @@ func GetIngressIP(ctx context.Context, configmapGetter func(ctx context.Context,
 				},
 			}},
 		},
-	}, metav1.CreateOptions{})
-	if err != nil && !k8serrors.IsAlreadyExists(err) {
-		err = errors.Wrapf(err, "failed to create ingress %s", ingName)
-		return
 	}
+
+	if discoveryConfig.PersistentProbe {
+		probe.GenerateName = ""
+		probe.Name = persistentProbeIngressName
+	}
+
+	return probe, nil
+}
+
+// getExistingProbeIngress returns the ingress the creation of the probe failed
+// with createErr, an already exists error, for: the one of its name, or else of
+// the name generated for it, as reported by the error.
+func getExistingProbeIngress(ctx context.Context, ingressCli IngressClient, probe *networkingv1.Ingress, createErr error) (*networkingv1.Ingress, error) {
+	name := probe.Name
+	var status k8serrors.APIStatus
+	if name == "" && errors.As(createErr, &status) && status.Status().Details != nil {
+		name = status.Status().Details.Name
+	}
+	if name == "" {
+		return nil, &ProbeCreateError{Kind: "ingress", Name: probe.GenerateName, Err: createErr}
+	}
+	ing, err := ingressCli.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the existing ingress %s", name)
+	}
+	return ing, nil
+}
+
+// GetDomainSuffixPatch is GetDomainSuffix for callers that may not mutate the
+// network configmap: rather than persisting the domain suffix it discovers, it
+// returns the JSON merge patch of the network configmap that would, for the
+// caller to apply. Like in a dry run, nothing else is acted on. The patch is nil
+// if the domain suffix is already set.
+func GetDomainSuffixPatch(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (domainSuffix string, patch []byte, err error) {
+	domainSuffix, err = GetDomainSuffix(withPatchOut(ctx, &patch), configmapGetter, cliset)
+	if err != nil {
+		return "", nil, err
+	}
+	return domainSuffix, patch, nil
+}
+
+// domainSuffixPatch returns the JSON merge patch setting the domain suffix of a
+// configmap.
+func domainSuffixPatch(domainSuffix string) ([]byte, error) {
+	patch, err := json.Marshal(map[string]map[string]string{
+		"data": {consts.KubeConfigMapKeyNetworkConfigDomainSuffix: domainSuffix},
+	})
+	return patch, errors.Wrap(err, "failed to marshal the domain suffix patch")
+}
+
+func GetDomainSuffix(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (domainSuffix string, err error) {
+	result, err := GetDomainSuffixResult(ctx, configmapGetter, cliset)
+	return result.DomainSuffix, err
+}
+
+// DomainSuffixResult is the result of GetDomainSuffixResult.
+type DomainSuffixResult struct {
+	DomainSuffix string
+	// Outcome is where the domain suffix comes from: the network config (or the
+	// status configmap), the environment, or the magic DNS domain of the
+	// discovered IP.
+	Outcome DiscoveryOutcome
+	// IP is the IP the domain suffix was discovered from, or else the one of
+	// the magic DNS domain suffix that was set, if any.
+	IP string
+	// Changed reports whether the domain suffix was persisted to a configmap.
+	Changed bool
+}
+
+// GetDomainSuffixResult is GetDomainSuffix also returning where the domain
+// suffix comes from, its IP and whether it was persisted.
+func GetDomainSuffixResult(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (result DomainSuffixResult, err error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	namespace := namespaceFromContext(ctx)
+	logger := discoveryLogger(ctx, correlationID).WithValues("namespace", namespace)
+	ctx, span := startSpan(ctx, spanGetDomainSuffix, spanAttrNamespace.String(namespace))
+
+	var domainSuffix, ip, magicDNS string
+	var changed bool
+	outcome := DiscoveryOutcomeConfigured
+	var reverseNames []string
+	dryRun := dryRunFromContext(ctx)
 	defer func() {
-		_ = ingressCli.Delete(ctx, ing.Name, metav1.DeleteOptions{})
+		span.SetAttributes(spanAttrOutcome.String(string(outcome)))
+		endSpan(span, err)
 	}()
+	defer func() {
+		result = DomainSuffixResult{DomainSuffix: domainSuffix, Outcome: outcome, IP: ip, Changed: changed}
+		if ip == "" {
+			result.IP, _ = ParseMagicDNSSuffix(domainSuffix, GetMagicDNS())
+		}
+		err = withFields(err, "namespace", namespace, "correlation_id", correlationID, "outcome", outcome)
+		if dryRun {
+			// The suffix of a dry run would otherwise be taken as the known
+			// one, and not notified when it is actually persisted.
+			return
+		}
+		defaultDiscoveryRegistry.record(namespace, correlationID, outcome, domainSuffix, reverseNames, err, func(changed bool) {
+			recordDiscoveryInfo(namespace, domainSuffix, result.IP, magicDNS)
+			if !changed {
+				return
+			}
+			ip, _ := ParseMagicDNSSuffix(domainSuffix, GetMagicDNS())
+			notifySubscribers(DiscoveryResult{
+				Namespace:     namespace,
+				DomainSuffix:  domainSuffix,
+				IP:            ip,
+				CorrelationID: correlationID,
+				ReverseNames:  reverseNames,
+			})
+		})
+	}()
+
+	// The override skips the network config altogether, so that it works
+	// without one, and an invalid one fails rather than being discovered over.
+	domainSuffix, err = getDomainSuffixOverride()
+	if err != nil || domainSuffix != "" {
+		if domainSuffix != "" {
+			outcome = DiscoveryOutcomeOverridden
+			logger.Info("The domain suffix is set by the environment", "env", DomainSuffixEnvKey, "domainSuffix", domainSuffix)
+		}
+		return
+	}
 
-	// Interval to poll for objects.
-	pollInterval := 10 * time.Second
-	// How long to wait for objects.
-	waitTimeout := 20 * time.Minute
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
+		return
+	}
 
-	logrus.Infof("Waiting for ingress %s to be ready", ing.Name)
-	// Wait for the Ingress to be Ready.
-	if err = wait.PollUntilContextTimeout(ctx, pollInterval, waitTimeout, false, func(ctx context.Context) (done bool, err error) {
-		ing, err = ingressCli.Get(
-			ctx, ing.Name, metav1.GetOptions{})
+	force := forceRediscoveryFromContext(ctx)
+	domainSuffix = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix])
+	stale := domainSuffix
+	if domainSuffix != "" && force {
+		logger.Info("Discovering the domain suffix again, though it has already been set in the network config", "key", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, "domainSuffix", domainSuffix)
+		domainSuffix = ""
+	}
+	if domainSuffix != "" {
+		logger.Info("The domain suffix has already been set in the network config", "key", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, "domainSuffix", domainSuffix)
+		var verify bool
+		verify, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryVerifyDomainSuffix, false)
 		if err != nil {
-			return true, err
+			return
+		}
+		if verify {
+			err = verifyDomainSuffix(ctx, domainSuffix)
 		}
-		return len(ing.Status.LoadBalancer.Ingress) > 0, nil
-	}); err != nil {
-		err = errors.Wrapf(err, "failed to wait for ingress %s to be ready", ing.Name)
 		return
 	}
-	logrus.Infof("Ingress %s is ready", ing.Name)
 
-	address := ing.Status.LoadBalancer.Ingress[0]
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get discovery config")
+		return
+	}
 
-	ip = address.IP
-	if ip == "" {
-		if address.Hostname == "" {
-			err = errors.Errorf("the ingress %s status has no IP or hostname", ing.Name)
+	if discoveryConfig.StatusConfigMap != "" {
+		domainSuffix, err = getStatusDomainSuffix(ctx, configmapGetter, configMap.Namespace, discoveryConfig.StatusConfigMap)
+		if err != nil {
 			return
 		}
-		var ipAddr *net.IPAddr
-		ipAddr, err = net.ResolveIPAddr("ip4", address.Hostname)
-		if err != nil {
-			err = errors.Wrapf(err, "failed to resolve ip address for hostname %s", address.Hostname)
+		if domainSuffix != "" && force {
+			stale, domainSuffix = domainSuffix, ""
+		}
+		if domainSuffix != "" {
+			logger.Info("The domain suffix has already been set in the status configmap", "key", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, "configmap", discoveryConfig.StatusConfigMap, "domainSuffix", domainSuffix)
 			return
 		}
-		ip = ipAddr.String()
 	}
 
-	return
-}
+	outcome = DiscoveryOutcomeDiscovered
 
-func GetDomainSuffix(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset *kubernetes.Clientset) (domainSuffix string, err error) {
-	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	// The configmap is passed down instead of fetched again.
+	addresses, err := discoverSharedIngressAddresses(ctx, configMap, cliset)
 	if err != nil {
-		err = errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
 		return
 	}
+	// The "IP" is the hostname if it is preferred, which the suffix is then
+	// composed from alike.
+	ip = addresses[0].Host()
 
-	domainSuffix = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix])
-	if domainSuffix != "" {
-		logrus.Infof("The %s in the network config has already set to `%s`", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, domainSuffix)
+	ip, err = reverifyIngressIP(ctx, cliset, discoveryConfig, configMap, ip)
+	if err != nil {
 		return
 	}
 
-	magicDNS := GetMagicDNS()
+	magicDNS = discoveryMagicDNS(discoveryConfig)
+	domainSuffix, ip, changed, err = composeAndPersistDomainSuffix(ctx, logger, cliset, configMap, discoveryConfig, ip)
+	if err != nil || dryRun {
+		return
+	}
+	if stale != "" && stale != domainSuffix {
+		logger.Info("Replaced the stale domain suffix", "stale", stale, "domainSuffix", domainSuffix)
+	}
 
-	var ip string
+	if discoveryConfig.ReverseLookup {
+		reverseNames = reverseLookup(ctx, ip)
+		if len(reverseNames) > 0 {
+			logger.Info("The ingress IP maps to reverse DNS names", "ip", ip, "reverseNames", reverseNames)
+		}
+	}
 
-	ip, err = GetIngressIP(ctx, configmapGetter, cliset)
+	err = ensureDomainSuffixExternalNameService(ctx, cliset, discoveryConfig, configMap, domainSuffix)
 	if err != nil {
 		return
 	}
 
-	domainSuffix = fmt.Sprintf("%s.%s", ip, magicDNS)
+	err = runPostDiscoveryHooks(ctx, logger, discoveryConfig, DiscoveryResult{
+		Namespace:     configMap.Namespace,
+		DomainSuffix:  domainSuffix,
+		IP:            ip,
+		CorrelationID: correlationID,
+		ReverseNames:  reverseNames,
+	})
+
+	return
+}
 
-	logrus.Infof("you have not set the %s in the network config, so use magic DNS to generate a domain suffix automatically: `%s`, and set it to the network config", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, domainSuffix)
+// ComposeAndPersistDomainSuffix composes the magic DNS domain suffix of the ip,
+// e.g. an ingress IP the caller already has, and persists it to the network
+// configmap as GetDomainSuffix does, without probing for the ingress IP. The
+// domain suffix persisted by a concurrent discovery, if any, is returned
+// instead.
+func ComposeAndPersistDomainSuffix(ctx context.Context, cliset kubernetes.Interface, configMap *corev1.ConfigMap, ip string) (domainSuffix string, err error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	logger := discoveryLogger(ctx, correlationID).WithValues("namespace", configMap.Namespace)
 
-	configMapCli := cliset.CoreV1().ConfigMaps(configMap.Namespace)
-	_, err = configMapCli.Patch(ctx, configMap.Name, types.MergePatchType, []byte(fmt.Sprintf(`{"data":{"%s":"%s"}}`, consts.KubeConfigMapKeyNetworkConfigDomainSuffix, domainSuffix)), metav1.PatchOptions{})
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
 	if err != nil {
-		err = errors.Wrapf(err, "failed to patch configmap %s", consts.KubeConfigMapNameNetworkConfig)
+		err = errors.Wrapf(err, "failed to get discovery config")
 		return
 	}
-
+	domainSuffix, _, _, err = composeAndPersistDomainSuffix(ctx, logger, cliset, configMap, discoveryConfig, ip)
 	return
 }
+
+// discoveryMagicDNS returns the magic DNS domain, or the template, the domain
+// suffix is composed with.
+func discoveryMagicDNS(discoveryConfig *discoveryConfig) string {
+	if discoveryConfig.MagicDNSTemplate != "" {
+		return discoveryConfig.MagicDNSTemplate
+	}
+	return GetMagicDNS()
+}
+
+// composeAndPersistDomainSuffix composes the magic DNS domain suffix of the ip,
+// checks it and persists it, unless in dry run. It returns the domain suffix
+// persisted and the IP it was composed from, which are the ones of another
+// discovery if it persisted its domain suffix first, and whether a configmap
+// was changed.
+func composeAndPersistDomainSuffix(ctx context.Context, logger logr.Logger, cliset kubernetes.Interface, configMap *corev1.ConfigMap, discoveryConfig *discoveryConfig, ip string) (domainSuffix, suffixIP string, changed bool, err error) {
+	// A misconfigured magic DNS would break the host of every ingress, so the
+	// suffix is checked before it is persisted.
+	label, err := ComposeMagicDNSLabel(ip, discoveryConfig.MagicDNSIPFormat)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to compose the domain suffix, check %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigMagicDNSIPFormat, consts.KubeConfigMapNameNetworkConfig)
+		return
+	}
+	magicDNS := discoveryMagicDNS(discoveryConfig)
+	generated := formatDomainSuffix(label, magicDNS)
+	if errs := validation.IsDNS1123Subdomain(generated); len(errs) > 0 {
+		err = errors.Wrapf(errors.New(strings.Join(errs, ", ")), "the domain suffix %q generated with the magic DNS %q is not a valid DNS name", generated, magicDNS)
+		return
+	}
+	domainSuffix = generated
+
+	logger.Info("you have not set the domain suffix in the network config, so use magic DNS to generate a domain suffix automatically, and set it to the network config", "key", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, "domainSuffix", domainSuffix, "ip", ip)
+
+	if dryRunFromContext(ctx) {
+		logger.Info("Skipping the patch of the network config in dry run", "key", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, "domainSuffix", domainSuffix)
+		if patch := patchOutFromContext(ctx); patch != nil {
+			*patch, err = domainSuffixPatch(domainSuffix)
+		}
+		return domainSuffix, ip, false, err
+	}
+
+	var persisted string
+	err = traceSpan(ctx, spanPatchDomainSuffix, func(ctx context.Context) (err error) {
+		persisted, changed, err = PersistDomainSuffix(ctx, cliset, configMap, discoveryConfig.StatusConfigMap, domainSuffix, forceRediscoveryFromContext(ctx))
+		return
+	}, spanAttrNamespace.String(configMap.Namespace))
+	if err != nil {
+		return
+	}
+	if persisted != domainSuffix {
+		logger.Info("Another discovery persisted its domain suffix first, so it is used instead", "domainSuffix", persisted, "discarded", domainSuffix)
+		domainSuffix = persisted
+		if persistedIP, ok := ParseMagicDNSSuffix(persisted, GetMagicDNS()); ok {
+			ip = persistedIP
+		}
+	}
+	recordEventf(configMap, corev1.EventTypeNormal, EventReasonDomainSuffixDetected, "Detected the domain suffix %s from the ingress IP %s", domainSuffix, ip)
+	// Magic DNS is meant for development, the warning event is easier to
+	// notice than the normal one above.
+	logger.Info("The domain suffix is generated with magic DNS, set a real domain in production", "domainSuffix", domainSuffix, "key", consts.KubeConfigMapKeyNetworkConfigDomainSuffix)
+	recordEventf(configMap, corev1.EventTypeWarning, EventReasonMagicDNSDomainSuffix, "The domain suffix %s is generated with magic DNS, set %s to a real domain in production", domainSuffix, consts.KubeConfigMapKeyNetworkConfigDomainSuffix)
+	return domainSuffix, ip, changed, nil
+}
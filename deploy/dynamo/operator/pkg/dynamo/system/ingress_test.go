@@ -0,0 +1,1701 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func newNetworkConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      consts.KubeConfigMapNameNetworkConfig,
+			Namespace: GetNamespace(),
+		},
+		Data: data,
+	}
+}
+
+// staticConfigMapGetter returns a configmap getter always returning the configmap.
+func staticConfigMapGetter(configMap *corev1.ConfigMap) func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return configMap, nil
+	}
+}
+
+func TestParseIngressConfig(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:       " nginx ",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"example.com/a": "1"}`,
+		consts.KubeConfigMapKeyNetworkConfigIngressPath:        "/inference",
+		consts.KubeConfigMapKeyNetworkConfigIngressPathType:    "Prefix",
+	})
+
+	ingressConfig, err := ParseIngressConfig(configMap)
+	if err != nil {
+		t.Fatalf("ParseIngressConfig() error = %v", err)
+	}
+	if ingressConfig.ClassName == nil || *ingressConfig.ClassName != "nginx" {
+		t.Errorf("ClassName = %v, want nginx", ingressConfig.ClassName)
+	}
+	if !reflect.DeepEqual(ingressConfig.Annotations, map[string]string{"example.com/a": "1"}) {
+		t.Errorf("Annotations = %v, want example.com/a", ingressConfig.Annotations)
+	}
+	if ingressConfig.Path != "/inference" || ingressConfig.PathType != networkingv1.PathTypePrefix || !ingressConfig.PathTypeExplicit {
+		t.Errorf("Path, PathType = %q, %q, want /inference, Prefix", ingressConfig.Path, ingressConfig.PathType)
+	}
+
+	fetched, err := GetIngressConfig(context.Background(), staticConfigMapGetter(configMap))
+	if err != nil {
+		t.Fatalf("GetIngressConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(fetched, ingressConfig) {
+		t.Errorf("GetIngressConfig() = %+v, want the ParseIngressConfig() one %+v", fetched, ingressConfig)
+	}
+
+	configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressPaths] = "not json"
+	if _, err := ParseIngressConfig(configMap); err == nil {
+		t.Error("ParseIngressConfig() with malformed paths error = nil, want an error")
+	}
+}
+
+func TestParseIngressConfigTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     string
+		want    []networkingv1.IngressTLS
+		wantErr bool
+	}{
+		{
+			name: "no tls",
+			tls:  "",
+			want: nil,
+		},
+		{
+			name: "secret and hosts",
+			tls:  `[{"secretName": "wildcard-tls", "hosts": ["*.example.com"]}]`,
+			want: []networkingv1.IngressTLS{{SecretName: "wildcard-tls", Hosts: []string{"*.example.com"}}},
+		},
+		{
+			name: "secret without hosts",
+			tls:  `[{"secretName": "default-tls"}]`,
+			want: []networkingv1.IngressTLS{{SecretName: "default-tls"}},
+		},
+		{
+			name:    "hosts without secret",
+			tls:     `[{"hosts": ["a.example.com"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			tls:     `{"secretName": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressTLS: tt.tls,
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := ingressConfig.IngressTLS(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IngressTLS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDomainSuffixRejectsInvalidMagicDNS(t *testing.T) {
+	t.Setenv(MagicDNSEnvKey, "sslip .io.")
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	if _, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset); err == nil {
+		t.Fatal("GetDomainSuffix() error = nil, want an invalid domain suffix error")
+	}
+
+	for _, action := range cliset.Actions() {
+		if action.GetResource().Resource == "configmaps" && (action.GetVerb() == "patch" || action.GetVerb() == "update") {
+			t.Errorf("the network configmap was patched with an invalid domain suffix")
+		}
+	}
+	persisted, err := cliset.CoreV1().ConfigMaps(configMap.Namespace).Get(context.Background(), configMap.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the network configmap: %v", err)
+	}
+	if suffix, ok := persisted.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; ok {
+		t.Errorf("persisted %s = %q, want none", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, suffix)
+	}
+}
+
+func TestGetDomainSuffixDryRun(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		configMap := newNetworkConfigMap(map[string]string{
+			consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+			consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+			consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+		})
+		cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+		if err := cliset.Tracker().Add(configMap); err != nil {
+			t.Fatalf("failed to add the network configmap: %v", err)
+		}
+
+		ctx := context.Background()
+		if dryRun {
+			ctx = WithDryRun(ctx)
+		}
+		domainSuffix, err := GetDomainSuffix(ctx, staticConfigMapGetter(configMap), cliset)
+		if err != nil {
+			t.Fatalf("GetDomainSuffix() with dry run %t error = %v", dryRun, err)
+		}
+		want := ComposeMagicDNSSuffix("10.0.0.1", GetMagicDNS())
+		if domainSuffix != want {
+			t.Errorf("GetDomainSuffix() with dry run %t = %q, want %q", dryRun, domainSuffix, want)
+		}
+
+		persisted, err := cliset.CoreV1().ConfigMaps(configMap.Namespace).Get(context.Background(), configMap.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get the network configmap: %v", err)
+		}
+		suffix, ok := persisted.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]
+		if dryRun && ok {
+			t.Errorf("persisted %s = %q in dry run, want none", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, suffix)
+		}
+		if !dryRun && suffix != want {
+			t.Errorf("persisted %s = %q, want %q", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, suffix, want)
+		}
+	}
+}
+
+func TestParseIngressConfigDefaultAnnotations(t *testing.T) {
+	SetDefaultAnnotations(map[string]string{
+		"cert-manager.io/cluster-issuer": "operator-issuer",
+		"example.com/managed":            "true",
+	})
+	defer SetDefaultAnnotations(nil)
+
+	tests := []struct {
+		name        string
+		annotations string
+		want        map[string]string
+	}{
+		{
+			name:        "additive",
+			annotations: `{"nginx.ingress.kubernetes.io/proxy-body-size": "0"}`,
+			want: map[string]string{
+				"cert-manager.io/cluster-issuer":              "operator-issuer",
+				"example.com/managed":                         "true",
+				"nginx.ingress.kubernetes.io/proxy-body-size": "0",
+			},
+		},
+		{
+			name:        "user value wins",
+			annotations: `{"cert-manager.io/cluster-issuer": "user-issuer"}`,
+			want: map[string]string{
+				"cert-manager.io/cluster-issuer": "user-issuer",
+				"example.com/managed":            "true",
+			},
+		},
+		{
+			name:        "explicitly empty",
+			annotations: `{}`,
+			want: map[string]string{
+				"cert-manager.io/cluster-issuer": "operator-issuer",
+				"example.com/managed":            "true",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: tt.annotations,
+			}))
+			if err != nil {
+				t.Fatalf("ParseIngressConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(ingressConfig.Annotations, tt.want) {
+				t.Errorf("Annotations = %v, want %v", ingressConfig.Annotations, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIngressConfigRemoveAnnotations(t *testing.T) {
+	SetDefaultAnnotations(map[string]string{
+		"cert-manager.io/cluster-issuer": "operator-issuer",
+		"example.com/managed":            "true",
+	})
+	defer SetDefaultAnnotations(nil)
+
+	tests := []struct {
+		name        string
+		annotations string
+		remove      string
+		want        map[string]string
+		wantErr     bool
+	}{
+		{
+			name:   "defaulted key",
+			remove: "cert-manager.io/cluster-issuer",
+			want: map[string]string{
+				"example.com/managed":   "true",
+				"example.com/inherited": "true",
+			},
+		},
+		{
+			name:   "defaulted and inherited keys",
+			remove: " example.com/inherited,example.com/managed ",
+			want: map[string]string{
+				"cert-manager.io/cluster-issuer": "operator-issuer",
+			},
+		},
+		{
+			name:        "configured key",
+			annotations: `{"example.com/user": "true", "example.com/kept": "true"}`,
+			remove:      "example.com/user",
+			want: map[string]string{
+				"cert-manager.io/cluster-issuer": "operator-issuer",
+				"example.com/managed":            "true",
+				"example.com/kept":               "true",
+			},
+		},
+		{
+			name:   "missing key",
+			remove: "example.com/missing",
+			want: map[string]string{
+				"cert-manager.io/cluster-issuer": "operator-issuer",
+				"example.com/managed":            "true",
+				"example.com/inherited":          "true",
+			},
+		},
+		{
+			name:    "invalid key",
+			remove:  "example.com/not valid",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations:       tt.annotations,
+				consts.KubeConfigMapKeyNetworkConfigIngressRemoveAnnotations: tt.remove,
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			ingressConfig.InheritAnnotations(map[string]string{"example.com/inherited": "true"})
+			if !reflect.DeepEqual(ingressConfig.Annotations, tt.want) {
+				t.Errorf("Annotations = %v, want %v", ingressConfig.Annotations, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyControllerDefaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           map[string]string
+		controllerType IngressControllerType
+		want           networkingv1.PathType
+	}{
+		{name: "preferred", controllerType: IngressControllerNginx, want: networkingv1.PathTypePrefix},
+		{name: "unknown controller", controllerType: IngressControllerUnknown, want: networkingv1.PathTypeImplementationSpecific},
+		{
+			name:           "explicit",
+			data:           map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressPathType: string(networkingv1.PathTypeExact)},
+			controllerType: IngressControllerNginx,
+			want:           networkingv1.PathTypeExact,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(tt.data))
+			if err != nil {
+				t.Fatalf("ParseIngressConfig() error = %v", err)
+			}
+			ingressConfig.ApplyControllerDefaults(tt.controllerType)
+			if ingressConfig.PathType != tt.want {
+				t.Errorf("PathType = %s, want %s", ingressConfig.PathType, tt.want)
+			}
+		})
+	}
+}
+
+func TestInheritAnnotations(t *testing.T) {
+	defaults := map[string]string{"example.com/a": "default", "example.com/b": "default"}
+	tests := []struct {
+		name   string
+		config IngressConfig
+		want   map[string]string
+	}{
+		{
+			name:   "no annotations",
+			config: IngressConfig{},
+			want:   map[string]string{"example.com/a": "default", "example.com/b": "default"},
+		},
+		{
+			name:   "network config wins",
+			config: IngressConfig{Annotations: map[string]string{"example.com/a": "config"}},
+			want:   map[string]string{"example.com/a": "config", "example.com/b": "default"},
+		},
+		{
+			name:   "explicit annotations",
+			config: IngressConfig{Annotations: map[string]string{}, AnnotationsExplicit: true},
+			want:   map[string]string{},
+		},
+		{
+			name:   "removed",
+			config: IngressConfig{RemoveAnnotations: []string{"example.com/b", "example.com/missing"}},
+			want:   map[string]string{"example.com/a": "default"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.InheritAnnotations(defaults)
+			if !reflect.DeepEqual(tt.config.Annotations, tt.want) {
+				t.Errorf("Annotations = %v, want %v", tt.config.Annotations, tt.want)
+			}
+		})
+	}
+	if len(defaults) != 2 || defaults["example.com/b"] != "default" {
+		t.Errorf("InheritAnnotations() modified the defaults: %v", defaults)
+	}
+}
+
+func TestParseIngressConfigFromMap(t *testing.T) {
+	// The spec of a DynamoNetworkConfig custom resource, flattened to the keys
+	// of the network configmap.
+	data := map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:       "nginx",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"cert-manager.io/cluster-issuer": "letsencrypt"}`,
+		consts.KubeConfigMapKeyNetworkConfigIngressPath:        "/inference",
+		consts.KubeConfigMapKeyNetworkConfigIngressPaths:       `[{"path": "/v1"}, {"path": "/v2", "pathType": "Exact"}]`,
+	}
+
+	ingressConfig, err := ParseIngressConfigFromMap(data)
+	if err != nil {
+		t.Fatalf("ParseIngressConfigFromMap() error = %v", err)
+	}
+	want, err := ParseIngressConfig(newNetworkConfigMap(data))
+	if err != nil {
+		t.Fatalf("ParseIngressConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(ingressConfig, want) {
+		t.Errorf("ParseIngressConfigFromMap() = %+v, want the ParseIngressConfig() one %+v", ingressConfig, want)
+	}
+	if ingressConfig.ClassName == nil || *ingressConfig.ClassName != "nginx" || ingressConfig.Path != "/inference" || len(ingressConfig.Paths) != 2 {
+		t.Errorf("ParseIngressConfigFromMap() = %+v, want the ingress config of the map", ingressConfig)
+	}
+
+	if _, err := ParseIngressConfigFromMap(map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressPath: "inference"}); err == nil {
+		t.Errorf("ParseIngressConfigFromMap() with a relative path error = nil, want an error")
+	}
+
+	ingressConfig, err = ParseIngressConfigFromMap(nil)
+	if err != nil {
+		t.Fatalf("ParseIngressConfigFromMap(nil) error = %v", err)
+	}
+	if ingressConfig.ClassName != nil || ingressConfig.Path != "/" {
+		t.Errorf("ParseIngressConfigFromMap(nil) = %+v, want the default ingress config", ingressConfig)
+	}
+}
+
+func TestParseIngressConfigPathValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		paths    string
+		wantPath string
+		wantErr  bool
+	}{
+		{name: "valid", path: "/v1", wantPath: "/v1"},
+		{name: "empty", path: "", wantPath: "/"},
+		{name: "relative", path: "v1", wantErr: true},
+		{name: "valid paths", paths: `[{"path": "/v1"}, {"path": "/v2"}]`, wantPath: "/"},
+		{name: "relative paths entry", paths: `[{"path": "/v1"}, {"path": "v2"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressPath:  tt.path,
+				consts.KubeConfigMapKeyNetworkConfigIngressPaths: tt.paths,
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "must start with /") {
+					t.Errorf("ParseIngressConfig() error = %v, want one about the leading /", err)
+				}
+				return
+			}
+			if ingressConfig.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", ingressConfig.Path, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestIngressConfigPartialTLS(t *testing.T) {
+	defaultBackend := networkingv1.IngressServiceBackend{
+		Name: "default",
+		Port: networkingv1.ServiceBackendPort{Name: "http"},
+	}
+	ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressPaths: `[{"path": "/public"}, {"path": "/secure", "host": "secure.example.com"}, {"path": "/metrics"}]`,
+		consts.KubeConfigMapKeyNetworkConfigIngressTLS:   `[{"secretName": "secure-tls", "hosts": ["secure.example.com"]}]`,
+	}))
+	if err != nil {
+		t.Fatalf("ParseIngressConfig() error = %v", err)
+	}
+
+	rules := ingressConfig.IngressRules("public.example.com", defaultBackend)
+	var hosts []string
+	paths := make(map[string][]string)
+	for _, rule := range rules {
+		hosts = append(hosts, rule.Host)
+		for _, p := range rule.HTTP.Paths {
+			paths[rule.Host] = append(paths[rule.Host], p.Path)
+		}
+	}
+	if want := []string{"public.example.com", "secure.example.com"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("IngressRules() hosts = %v, want %v", hosts, want)
+	}
+	wantPaths := map[string][]string{
+		"public.example.com": {"/public", "/metrics"},
+		"secure.example.com": {"/secure"},
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("IngressRules() paths = %v, want %v", paths, wantPaths)
+	}
+
+	tls := ingressConfig.IngressTLS()
+	if len(tls) != 1 || !reflect.DeepEqual(tls[0].Hosts, []string{"secure.example.com"}) {
+		t.Errorf("IngressTLS() = %v, want only the secure host", tls)
+	}
+	if err := ValidateIngressTLSHosts(tls, rules); err != nil {
+		t.Errorf("ValidateIngressTLSHosts() error = %v", err)
+	}
+}
+
+func TestValidateIngressTLSHosts(t *testing.T) {
+	rules := []networkingv1.IngressRule{{Host: "public.example.com"}, {Host: "secure.example.com"}}
+
+	tests := []struct {
+		name    string
+		hosts   []string
+		wantErr string
+	}{
+		{name: "no TLS"},
+		{name: "some hosts", hosts: []string{"secure.example.com"}},
+		{name: "wildcard", hosts: []string{"*.example.com"}},
+		{name: "host missing from the rules", hosts: []string{"secure.example.com", "other.example.com"}, wantErr: "other.example.com"},
+		{name: "wildcard covering no rule", hosts: []string{"*.other.com"}, wantErr: "*.other.com"},
+		{name: "wildcard of a deeper host", hosts: []string{"*.com"}, wantErr: "*.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tls []networkingv1.IngressTLS
+			if len(tt.hosts) > 0 {
+				tls = []networkingv1.IngressTLS{{Hosts: tt.hosts, SecretName: "tls"}}
+			}
+			err := ValidateIngressTLSHosts(tls, rules)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateIngressTLSHosts() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateIngressTLSHosts() error = %v, want one about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseIngressPathsInvalidHost(t *testing.T) {
+	_, err := ParseIngressPaths(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressPaths: `[{"path": "/secure", "host": "Not_A_Host"}]`,
+	}))
+	if err == nil {
+		t.Errorf("ParseIngressPaths() error = nil, want an error for the invalid host")
+	}
+}
+
+func TestIngressConfigHTTPIngressPaths(t *testing.T) {
+	defaultBackend := networkingv1.IngressServiceBackend{
+		Name: "default",
+		Port: networkingv1.ServiceBackendPort{Name: "http"},
+	}
+	prefix := networkingv1.PathTypePrefix
+	exact := networkingv1.PathTypeExact
+
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    []networkingv1.HTTPIngressPath
+		wantErr bool
+	}{
+		{
+			name: "single path fallback",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressPath:     "/api",
+				consts.KubeConfigMapKeyNetworkConfigIngressPathType: "Prefix",
+			},
+			want: []networkingv1.HTTPIngressPath{{
+				Path:     "/api",
+				PathType: &prefix,
+				Backend:  networkingv1.IngressBackend{Service: &defaultBackend},
+			}},
+		},
+		{
+			name: "multi path expansion",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressPathType: "Prefix",
+				consts.KubeConfigMapKeyNetworkConfigIngressPaths: `[
+					{"path": "/v1"},
+					{"path": "/metrics", "service": "metrics", "port": 9090},
+					{"path": "/health", "pathType": "Exact"}
+				]`,
+			},
+			want: []networkingv1.HTTPIngressPath{
+				{
+					Path:     "/v1",
+					PathType: &prefix,
+					Backend:  networkingv1.IngressBackend{Service: &defaultBackend},
+				},
+				{
+					Path:     "/metrics",
+					PathType: &prefix,
+					Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+						Name: "metrics",
+						Port: networkingv1.ServiceBackendPort{Number: 9090},
+					}},
+				},
+				{
+					Path:     "/health",
+					PathType: &exact,
+					Backend:  networkingv1.IngressBackend{Service: &defaultBackend},
+				},
+			},
+		},
+		{
+			name:    "entry without a path",
+			data:    map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressPaths: `[{"service": "metrics"}]`},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := ingressConfig.HTTPIngressPaths(defaultBackend); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HTTPIngressPaths() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForIngressReady(t *testing.T) {
+	newIngress := func(status ...networkingv1.IngressLoadBalancerIngress) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: GetNamespace()},
+			Status: networkingv1.IngressStatus{
+				LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: status},
+			},
+		}
+	}
+
+	t.Run("already ready", func(t *testing.T) {
+		cliset := fake.NewSimpleClientset(newIngress(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"}))
+
+		// The interval is longer than the test timeout, so only the immediate
+		// check can succeed.
+		ing, err := WaitForIngressReady(context.Background(), cliset.NetworkingV1().Ingresses(GetNamespace()), "app", time.Hour, 2*time.Hour)
+		if err != nil {
+			t.Fatalf("WaitForIngressReady() error = %v", err)
+		}
+		if len(ing.Status.LoadBalancer.Ingress) != 1 || ing.Status.LoadBalancer.Ingress[0].IP != "10.0.0.1" {
+			t.Errorf("WaitForIngressReady() status = %v, want 10.0.0.1", ing.Status.LoadBalancer.Ingress)
+		}
+		if gets := len(cliset.Actions()); gets != 1 {
+			t.Errorf("the ingress was fetched %d times, want 1", gets)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		cliset := fake.NewSimpleClientset(newIngress())
+
+		ing, err := WaitForIngressReady(context.Background(), cliset.NetworkingV1().Ingresses(GetNamespace()), "app", 5*time.Millisecond, 30*time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("WaitForIngressReady() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		if ing != nil {
+			t.Errorf("WaitForIngressReady() = %v, want nil", ing)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		cliset := fake.NewSimpleClientset()
+
+		if _, err := WaitForIngressReady(context.Background(), cliset.NetworkingV1().Ingresses(GetNamespace()), "app", 5*time.Millisecond, time.Second); !k8serrors.IsNotFound(err) {
+			t.Fatalf("WaitForIngressReady() error = %v, want a not found error", err)
+		}
+	})
+}
+
+func TestGetIngressConfigWithSource(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:    "nginx",
+		consts.KubeConfigMapKeyNetworkConfigIngressPathType: string(networkingv1.PathTypePrefix),
+		consts.KubeConfigMapKeyNetworkConfigIngressTLS:      `[{"secretName": "wildcard-tls"}]`,
+		// Blank values are defaulted like missing ones.
+		consts.KubeConfigMapKeyNetworkConfigIngressPath: " ",
+	})
+
+	ingressConfig, sources, err := GetIngressConfigWithSource(context.Background(), staticConfigMapGetter(configMap))
+	if err != nil {
+		t.Fatalf("GetIngressConfigWithSource() error = %v", err)
+	}
+	if ingressConfig.Path != "/" || ingressConfig.PathType != networkingv1.PathTypePrefix {
+		t.Errorf("GetIngressConfigWithSource() path = %q %q, want / Prefix", ingressConfig.Path, ingressConfig.PathType)
+	}
+
+	want := map[string]ConfigSource{
+		"ClassName":         ConfigSourceConfig,
+		"Annotations":       ConfigSourceDefault,
+		"RemoveAnnotations": ConfigSourceDefault,
+		"Path":              ConfigSourceDefault,
+		"PathType":          ConfigSourceConfig,
+		"Paths":             ConfigSourceDefault,
+		"TLS":               ConfigSourceConfig,
+		"BackendProtocol":   ConfigSourceDefault,
+		"DefaultBackend":    ConfigSourceDefault,
+		"CertManagerIssuer": ConfigSourceDefault,
+		"RewriteTarget":     ConfigSourceDefault,
+		"RewriteMiddleware": ConfigSourceDefault,
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("GetIngressConfigWithSource() sources = %v, want %v", sources, want)
+	}
+
+	missing := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return nil, k8serrors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+	if _, _, err := GetIngressConfigWithSource(context.Background(), missing); !errors.Is(err, ErrNetworkConfigNotFound) {
+		t.Errorf("GetIngressConfigWithSource() error = %v, want %v", err, ErrNetworkConfigNotFound)
+	}
+	ingressConfig, sources, err = GetIngressConfigWithSource(WithAllowMissingNetworkConfig(context.Background()), missing)
+	if err != nil {
+		t.Fatalf("GetIngressConfigWithSource() with a missing network config allowed error = %v", err)
+	}
+	if ingressConfig.Path != "/" || sources["ClassName"] != ConfigSourceDefault {
+		t.Errorf("GetIngressConfigWithSource() with a missing network config allowed = %+v %v, want the defaults", ingressConfig, sources)
+	}
+}
+
+func TestParseIngressConfigAnnotationsValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations string
+		wantErr     string
+	}{
+		{name: "valid", annotations: `{"nginx.ingress.kubernetes.io/ssl-redirect": "false"}`},
+		{name: "null", annotations: `null`},
+		{name: "trailing comma", annotations: `{"example.com/a": "1",}`, wantErr: "malformed JSON at offset"},
+		{name: "array", annotations: `["example.com/a", "example.com/b"]`, wantErr: "ingress annotations must be a JSON object, not an array"},
+		{name: "string", annotations: `"example.com/a"`, wantErr: "ingress annotations must be a JSON object, not a string"},
+		{name: "number", annotations: `3`, wantErr: "ingress annotations must be a JSON object, not a number"},
+		{name: "boolean", annotations: `true`, wantErr: "ingress annotations must be a JSON object, not a boolean"},
+		{name: "truncated array", annotations: `["example.com/a"`, wantErr: "ingress annotations must be a JSON object, not an array"},
+		{name: "malformed key", annotations: `{"example.com/a b": "1"}`, wantErr: `annotation key "example.com/a b" is invalid`},
+		{name: "malformed prefix", annotations: `{"-example.com/a": "1"}`, wantErr: `annotation key "-example.com/a" is invalid`},
+		{name: "number value", annotations: `{"example.com/replicas": 3}`, wantErr: `the value of annotation "example.com/replicas" is not a string`},
+		{name: "boolean value", annotations: `{"example.com/enabled": true}`, wantErr: `the value of annotation "example.com/enabled" is not a string`},
+		{name: "empty value", annotations: `{"example.com/a": ""}`, wantErr: `the value of annotation "example.com/a" is empty`},
+		{name: "template", annotations: `{"example.com/owner": "{{ .Namespace }}/{{ .IngressName }}"}`},
+		{name: "unknown template variable", annotations: `{"example.com/owner": "{{ .Tenant }}"}`, wantErr: `the value of annotation "example.com/owner" can't be expanded`},
+		{name: "malformed template", annotations: `{"example.com/owner": "{{ .Namespace"}`, wantErr: `the value of annotation "example.com/owner" is not a valid template`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseIngressConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: tt.annotations,
+			}))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseIngressConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseIngressConfig() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPOversizedAnnotations(t *testing.T) {
+	annotations, err := json.Marshal(map[string]string{
+		"example.com/a": strings.Repeat("a", 200<<10),
+		"example.com/b": strings.Repeat("b", 100<<10),
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal the annotations: %v", err)
+	}
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	var creates int
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		creates++
+		return false, nil, nil
+	})
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations:    string(annotations),
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+
+	_, err = GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err == nil || !strings.Contains(err.Error(), "exceed the size limit") {
+		t.Errorf("GetIngressIP() error = %v, want one about the annotations size", err)
+	}
+	if creates != 0 {
+		t.Errorf("GetIngressIP() created %d probe ingresses, want none", creates)
+	}
+}
+
+func TestGetIngressIPIngressClassCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		class       string
+		check       string
+		wantErr     string
+		wantCreated bool
+	}{
+		{name: "exists", class: "nginx", wantCreated: true},
+		{name: "does not exist", class: "missing", wantErr: `the ingress class "missing" does not exist, available ingress classes: nginx`},
+		{name: "skipped", class: "missing", check: "false", wantCreated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:               tt.class,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassCheck: tt.check,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:      "10ms",
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+
+			_, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("GetIngressIP() error = %v, want one containing %q", err, tt.wantErr)
+			}
+
+			created := false
+			for _, action := range cliset.Actions() {
+				if action.GetResource().Resource == "ingresses" && action.GetVerb() == "create" {
+					created = true
+				}
+			}
+			if created != tt.wantCreated {
+				t.Errorf("probe ingress created = %v, want %v", created, tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestGetDomainSuffixEnvironmentOverride(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "override", env: "apps.ci.example.com", want: "apps.ci.example.com"},
+		{name: "trailing dot", env: "apps.ci.example.com.", want: "apps.ci.example.com"},
+		{name: "invalid", env: "apps ci.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DomainSuffixEnvKey, tt.env)
+
+			var calls int
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "apps.example.com",
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+
+			domainSuffix, err := GetDomainSuffix(context.Background(), countingConfigMapGetter(configMap, &calls), cliset)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), DomainSuffixEnvKey) {
+					t.Errorf("GetDomainSuffix() error = %v, want one naming %s", err, DomainSuffixEnvKey)
+				}
+			} else if err != nil {
+				t.Fatalf("GetDomainSuffix() error = %v", err)
+			}
+			if domainSuffix != tt.want {
+				t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, tt.want)
+			}
+			if calls != 0 {
+				t.Errorf("the network configmap was read %d times, want none", calls)
+			}
+			if actions := cliset.Actions(); len(actions) != 0 {
+				t.Errorf("unexpected actions %v with the domain suffix set by the environment", actions)
+			}
+		})
+	}
+}
+
+func TestParseIngressConfigDefaultBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		want    *networkingv1.IngressBackend
+		wantErr bool
+	}{
+		{name: "unset"},
+		{
+			name:    "port number",
+			backend: `{"service": "fallback", "port": 8080}`,
+			want: &networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+				Name: "fallback",
+				Port: networkingv1.ServiceBackendPort{Number: 8080},
+			}},
+		},
+		{
+			name:    "port name",
+			backend: `{"service": "fallback", "portName": "http"}`,
+			want: &networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+				Name: "fallback",
+				Port: networkingv1.ServiceBackendPort{Name: "http"},
+			}},
+		},
+		{name: "no port", backend: `{"service": "fallback"}`, wantErr: true},
+		{name: "both ports", backend: `{"service": "fallback", "port": 8080, "portName": "http"}`, wantErr: true},
+		{name: "invalid service", backend: `{"service": "Fallback", "port": 8080}`, wantErr: true},
+		{name: "invalid port", backend: `{"service": "fallback", "port": 70000}`, wantErr: true},
+		{name: "malformed", backend: `{"service": "fallback"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := ParseIngressConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressPaths:          `[{"path": "/api"}]`,
+				consts.KubeConfigMapKeyNetworkConfigIngressDefaultBackend: tt.backend,
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if tt.want == nil {
+				if ingressConfig.DefaultBackend != nil {
+					t.Errorf("DefaultBackend = %+v, want nil", ingressConfig.DefaultBackend)
+				}
+			} else if got := ingressConfig.DefaultBackend.IngressBackend(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IngressBackend() = %+v, want %+v", got, tt.want)
+			}
+
+			// The default backend only catches what the rules don't route.
+			paths := ingressConfig.HTTPIngressPaths(networkingv1.IngressServiceBackend{Name: "my-service"})
+			if len(paths) != 1 || paths[0].Path != "/api" || paths[0].Backend.Service.Name != "my-service" {
+				t.Errorf("HTTPIngressPaths() = %+v, want the /api path to my-service", paths)
+			}
+		})
+	}
+}
+
+func TestParseIngressAnnotationsFlatEntries(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]string
+		want         map[string]string
+		wantExplicit bool
+		wantErr      string
+	}{
+		{name: "none", want: map[string]string{}},
+		{
+			name:         "empty JSON object",
+			data:         map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: "{}"},
+			want:         map[string]string{},
+			wantExplicit: true,
+		},
+		{
+			name: "prefixed key",
+			data: map[string]string{
+				"ingress.annotation.nginx.ingress.kubernetes.io_ssl-redirect": "false",
+				"ingress.annotation.example.com_proxy_buffer":                 "on",
+				"ingress.annotation.owner":                                    "team-a",
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:              "nginx",
+			},
+			want: map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-redirect": "false",
+				"example.com/proxy_buffer":                 "on",
+				"owner":                                    "team-a",
+			},
+			wantExplicit: true,
+		},
+		{
+			name: "flat entries win over the JSON object",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations:        `{"nginx.ingress.kubernetes.io/ssl-redirect": "true", "example.com/a": "1"}`,
+				"ingress.annotation.nginx.ingress.kubernetes.io_ssl-redirect": "false",
+			},
+			want: map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-redirect": "false",
+				"example.com/a": "1",
+			},
+			wantExplicit: true,
+		},
+		{
+			name:    "empty value",
+			data:    map[string]string{"ingress.annotation.example.com_a": ""},
+			wantErr: `invalid ingress.annotation.example.com_a in configmap network: the value of annotation "example.com/a" is empty`,
+		},
+		{
+			name:    "empty key",
+			data:    map[string]string{"ingress.annotation.": "1"},
+			wantErr: `annotation key "" is invalid`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations, explicit, err := ParseIngressAnnotations(newNetworkConfigMap(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseIngressAnnotations() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIngressAnnotations() error = %v", err)
+			}
+			if !reflect.DeepEqual(annotations, tt.want) {
+				t.Errorf("ParseIngressAnnotations() = %v, want %v", annotations, tt.want)
+			}
+			if explicit != tt.wantExplicit {
+				t.Errorf("ParseIngressAnnotations() explicit = %v, want %v", explicit, tt.wantExplicit)
+			}
+		})
+	}
+}
+
+func TestParseIngressAnnotationsBase(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "base only",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase: `{"example.com/a": "base", "example.com/b": "base"}`,
+			},
+			want: map[string]string{"example.com/a": "base", "example.com/b": "base"},
+		},
+		{
+			name: "main wins over base",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase: `{"example.com/a": "base", "example.com/b": "base"}`,
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations:     `{"example.com/b": "main", "example.com/c": "main"}`,
+			},
+			want: map[string]string{"example.com/a": "base", "example.com/b": "main", "example.com/c": "main"},
+		},
+		{
+			name: "flat entries win over both",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase: `{"example.com/a": "base"}`,
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations:     `{"example.com/a": "main"}`,
+				"ingress.annotation.example.com_a":                         "flat",
+			},
+			want: map[string]string{"example.com/a": "flat"},
+		},
+		{
+			name: "invalid base",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase: `["example.com/a"]`,
+			},
+			wantErr: "invalid ingress-annotations-base in configmap network",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations, explicit, err := ParseIngressAnnotations(newNetworkConfigMap(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseIngressAnnotations() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIngressAnnotations() error = %v", err)
+			}
+			if !reflect.DeepEqual(annotations, tt.want) {
+				t.Errorf("ParseIngressAnnotations() = %v, want %v", annotations, tt.want)
+			}
+			if !explicit {
+				t.Error("ParseIngressAnnotations() explicit = false, want true")
+			}
+		})
+	}
+}
+func TestGetIngressIPInsufficientTimeout(t *testing.T) {
+	tests := []struct {
+		name      string
+		minBudget string
+		timeout   time.Duration
+	}{
+		{name: "less than the poll interval", timeout: time.Millisecond},
+		{name: "less than the min budget", minBudget: "1h", timeout: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9"})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "1s",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryMinBudget:    tt.minBudget,
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			_, err := GetIngressIP(ctx, staticConfigMapGetter(configMap), cliset)
+			if !errors.Is(err, ErrInsufficientTimeout) {
+				t.Fatalf("GetIngressIP() error = %v, want ErrInsufficientTimeout", err)
+			}
+
+			ingresses, err := cliset.NetworkingV1().Ingresses(configMap.Namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(ingresses.Items) != 0 {
+				t.Errorf("GetIngressIP() created %d ingresses, want none", len(ingresses.Items))
+			}
+		})
+	}
+}
+
+func TestGetIngressIPExternalIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		externalIP string
+		want       string
+		wantErr    bool
+	}{
+		{name: "ipv4", externalIP: "192.0.2.10", want: "192.0.2.10"},
+		{name: "ipv6", externalIP: " 2001:DB8::1 ", want: "2001:db8::1"},
+		{name: "invalid", externalIP: "lb.example.com", wantErr: true},
+		{name: "unset", want: "10.0.0.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9"})
+			var creates int
+			cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				creates++
+				return false, nil, nil
+			})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+				consts.KubeConfigMapKeyNetworkConfigExternalIP:            tt.externalIP,
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIngressIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ip != tt.want {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, tt.want)
+			}
+			wantCreates := 0
+			if tt.externalIP == "" {
+				wantCreates = 1
+			}
+			if creates != wantCreates {
+				t.Errorf("GetIngressIP() created %d probe ingresses, want %d", creates, wantCreates)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPServiceStatusFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  []corev1.LoadBalancerIngress
+		want    string
+		wantErr error
+	}{
+		{name: "service status", status: []corev1.LoadBalancerIngress{{IP: "10.0.0.7"}}, want: "10.0.0.7"},
+		{name: "both statuses empty", wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset()
+			if err := cliset.Tracker().Add(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx"},
+				Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: tt.status}},
+			}); err != nil {
+				t.Fatalf("failed to add the controller service: %v", err)
+			}
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:                   "nginx",
+				consts.KubeConfigMapKeyNetworkConfigIngressControllerService:       "ingress-nginx/ingress-nginx-controller",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:          "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:           "200ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryServiceStatusFallback: "30ms",
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetIngressIP() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if ip != tt.want {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindIngressControllerLoadBalancerService(t *testing.T) {
+	service := func(namespace, name string, serviceType corev1.ServiceType) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"app.kubernetes.io/name": "ingress-nginx"},
+			},
+			Spec: corev1.ServiceSpec{Type: serviceType},
+		}
+	}
+	tests := []struct {
+		name     string
+		services []*corev1.Service
+		want     string
+		wantErr  string
+	}{
+		{
+			name: "single match",
+			services: []*corev1.Service{
+				service("ingress-nginx", "ingress-nginx-controller", corev1.ServiceTypeLoadBalancer),
+				service("ingress-nginx", "ingress-nginx-controller-admission", corev1.ServiceTypeClusterIP),
+			},
+			want: "ingress-nginx/ingress-nginx-controller",
+		},
+		{
+			name:    "no match",
+			wantErr: "no ingress controller service matches app.kubernetes.io/name=ingress-nginx",
+		},
+		{
+			name: "no load balancer",
+			services: []*corev1.Service{
+				service("ingress-nginx", "ingress-nginx-controller-admission", corev1.ServiceTypeClusterIP),
+			},
+			wantErr: "none of the 1 ingress controller services matching app.kubernetes.io/name=ingress-nginx is of type LoadBalancer",
+		},
+		{
+			name: "multiple matches",
+			services: []*corev1.Service{
+				service("ingress-nginx", "ingress-nginx-controller", corev1.ServiceTypeLoadBalancer),
+				service("ingress-internal", "ingress-nginx-controller", corev1.ServiceTypeLoadBalancer),
+			},
+			wantErr: "2 services of type LoadBalancer match the ingress controller selector app.kubernetes.io/name=ingress-nginx: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := fake.NewSimpleClientset()
+			for _, svc := range tt.services {
+				if err := cliset.Tracker().Add(svc); err != nil {
+					t.Fatalf("failed to add the service: %v", err)
+				}
+			}
+			config := &discoveryConfig{ControllerServiceSelector: "app.kubernetes.io/name=ingress-nginx"}
+
+			svc, err := findIngressControllerLoadBalancerService(context.Background(), cliset, config, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("findIngressControllerLoadBalancerService() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findIngressControllerLoadBalancerService() error = %v", err)
+			}
+			if got := svc.Namespace + "/" + svc.Name; got != tt.want {
+				t.Errorf("findIngressControllerLoadBalancerService() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPProbeNamespace(t *testing.T) {
+	for _, allowed := range []bool{true, false} {
+		t.Run(fmt.Sprintf("allowed=%v", allowed), func(t *testing.T) {
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.9"})
+			cliset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+				if review.Spec.ResourceAttributes.Namespace != "shared" || review.Spec.ResourceAttributes.Resource != "ingresses" {
+					t.Errorf("reviewed %+v, want the ingresses of namespace shared", review.Spec.ResourceAttributes)
+				}
+				review.Status.Allowed = allowed
+				return true, review, nil
+			})
+			var namespaces []string
+			cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				namespaces = append(namespaces, action.GetNamespace())
+				return false, nil, nil
+			})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:            "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:   "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace: "shared",
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if !allowed {
+				if err == nil {
+					t.Fatalf("GetIngressIP() = %q, want an access error", ip)
+				}
+				if len(namespaces) != 0 {
+					t.Errorf("GetIngressIP() created ingresses in %v, want none", namespaces)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if !reflect.DeepEqual(namespaces, []string{"shared"}) {
+				t.Errorf("GetIngressIP() created ingresses in %v, want [shared]", namespaces)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPFakeClockTimeout(t *testing.T) {
+	start := time.Now()
+	fakeClock := clocktesting.NewFakeClock(start)
+	SetClock(fakeClock)
+	defer SetClock(nil)
+
+	// The ingress never gets an address, and the default 20 minute wait
+	// timeout applies.
+	cliset := newLoadBalancerClientset()
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+		done <- err
+	}()
+
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case err := <-done:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("GetIngressIP() error = %v, want context.DeadlineExceeded", err)
+			}
+			if elapsed := fakeClock.Since(start); elapsed < defaultDiscoveryTunables.WaitTimeout {
+				t.Fatalf("the fake clock advanced %s, want at least the wait timeout", elapsed)
+			}
+			return
+		case <-deadline:
+			t.Fatal("GetIngressIP() didn't time out on the fake clock")
+		default:
+		}
+		if fakeClock.HasWaiters() {
+			fakeClock.Step(time.Minute)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadinessChecker(t *testing.T) {
+	const annotation = "example.com/load-balancer-address"
+	newIngress := func(address string, status ...networkingv1.IngressLoadBalancerIngress) *networkingv1.Ingress {
+		ing := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: GetNamespace()},
+			Status: networkingv1.IngressStatus{
+				LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: status},
+			},
+		}
+		if address != "" {
+			ing.Annotations = map[string]string{annotation: address}
+		}
+		return ing
+	}
+
+	tests := []struct {
+		name      string
+		checker   ReadinessChecker
+		ing       *networkingv1.Ingress
+		want      []networkingv1.IngressLoadBalancerIngress
+		wantReady bool
+	}{
+		{
+			name:      "load balancer status",
+			checker:   LoadBalancerStatusChecker{},
+			ing:       newIngress("", networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"}),
+			want:      []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}},
+			wantReady: true,
+		},
+		{
+			name:    "load balancer status empty",
+			checker: LoadBalancerStatusChecker{},
+			ing:     newIngress("10.0.0.2"),
+		},
+		{
+			name:      "address annotation",
+			checker:   AddressAnnotationChecker{Annotation: annotation},
+			ing:       newIngress("10.0.0.2, lb.example.com", networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"}),
+			want:      []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.2"}, {Hostname: "lb.example.com"}},
+			wantReady: true,
+		},
+		{
+			name:      "address annotation unset",
+			checker:   AddressAnnotationChecker{Annotation: annotation},
+			ing:       newIngress("", networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"}),
+			want:      []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}},
+			wantReady: true,
+		},
+		{
+			name:    "address annotation not ready",
+			checker: AddressAnnotationChecker{Annotation: annotation},
+			ing:     newIngress(" , "),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, ready := tt.checker.Ready(tt.ing)
+			if ready != tt.wantReady {
+				t.Fatalf("Ready() ready = %v, want %v", ready, tt.wantReady)
+			}
+			if ready && !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("Ready() entries = %v, want %v", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPReadinessCheckerByController(t *testing.T) {
+	const annotation = "example.com/load-balancer-address"
+	newClientset := func() *fake.Clientset {
+		cliset := fake.NewSimpleClientset(&networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "alb"},
+			Spec:       networkingv1.IngressClassSpec{Controller: "ingress.k8s.aws/alb"},
+		})
+		cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress)
+			ing.Name = ing.GenerateName + "test"
+			ing.Annotations = map[string]string{annotation: "10.0.0.7"}
+			return false, nil, nil
+		})
+		return cliset
+	}
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:               "alb",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:      "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:       "200ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressAnnotation: annotation,
+	})
+
+	t.Run("built-in", func(t *testing.T) {
+		ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), newClientset())
+		if err != nil {
+			t.Fatalf("GetIngressIP() error = %v", err)
+		}
+		if ip != "10.0.0.7" {
+			t.Errorf("GetIngressIP() = %q, want 10.0.0.7", ip)
+		}
+	})
+
+	t.Run("replaced", func(t *testing.T) {
+		SetReadinessChecker(IngressControllerALB, LoadBalancerStatusChecker{})
+		defer SetReadinessChecker(IngressControllerALB, nil)
+
+		if _, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), newClientset()); err == nil {
+			t.Fatal("GetIngressIP() error = nil, want the load balancer status to stay empty")
+		}
+	})
+}
+
+func TestGetDomainSuffixes(t *testing.T) {
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	var creates int
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		creates++
+		return false, nil, nil
+	})
+	discovered := map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	}
+	configMaps := map[string]map[string]string{
+		"team-a": {consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "team-a.example.com"},
+		"team-b": discovered,
+		"team-c": discovered,
+	}
+	for namespace, data := range configMaps {
+		configMap := newNetworkConfigMap(data)
+		configMap.Namespace = namespace
+		if err := cliset.Tracker().Add(configMap); err != nil {
+			t.Fatalf("failed to add the network configmap of %s: %v", namespace, err)
+		}
+	}
+	configmapGetter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		if namespace == "team-d" {
+			return nil, k8serrors.NewForbidden(corev1.Resource("configmaps"), name, errors.New("denied"))
+		}
+		return cliset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+
+	domainSuffixes, errs := GetDomainSuffixes(context.Background(), []string{"team-a", "team-b", "team-c", "team-d"}, configmapGetter, cliset)
+	generated := ComposeMagicDNSSuffix("10.0.0.1", GetMagicDNS())
+	want := map[string]string{
+		"team-a": "team-a.example.com",
+		"team-b": generated,
+		"team-c": generated,
+	}
+	if !reflect.DeepEqual(domainSuffixes, want) {
+		t.Errorf("GetDomainSuffixes() = %v, want %v", domainSuffixes, want)
+	}
+	if len(errs) != 1 || errs["team-d"] == nil {
+		t.Errorf("GetDomainSuffixes() errors = %v, want one for team-d", errs)
+	}
+	if creates != 1 {
+		t.Errorf("GetDomainSuffixes() created %d probe ingresses, want 1", creates)
+	}
+}
+
+func TestGetDomainSuffixPatch(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	domainSuffix, patch, err := GetDomainSuffixPatch(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetDomainSuffixPatch() error = %v", err)
+	}
+	want := ComposeMagicDNSSuffix("10.0.0.1", GetMagicDNS())
+	if domainSuffix != want {
+		t.Errorf("GetDomainSuffixPatch() = %q, want %q", domainSuffix, want)
+	}
+	wantPatch := fmt.Sprintf(`{"data":{"%s":"%s"}}`, consts.KubeConfigMapKeyNetworkConfigDomainSuffix, want)
+	if string(patch) != wantPatch {
+		t.Errorf("GetDomainSuffixPatch() patch = %s, want %s", patch, wantPatch)
+	}
+	for _, action := range cliset.Actions() {
+		if action.GetResource().Resource == "configmaps" && action.GetVerb() != "get" {
+			t.Errorf("GetDomainSuffixPatch() made a %s call on configmaps, want none", action.GetVerb())
+		}
+	}
+
+	configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix] = "example.com"
+	domainSuffix, patch, err = GetDomainSuffixPatch(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetDomainSuffixPatch() with a domain suffix error = %v", err)
+	}
+	if domainSuffix != "example.com" || patch != nil {
+		t.Errorf("GetDomainSuffixPatch() with a domain suffix = %q, %s, want example.com and no patch", domainSuffix, patch)
+	}
+}
+
+func TestGetIngressIPProbeAlreadyExists(t *testing.T) {
+	const name = "dynamo-probe-taken"
+	cliset := newLoadBalancerClientset()
+	if err := cliset.Tracker().Add(&networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: GetNamespace()},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.3"}}},
+		},
+	}); err != nil {
+		t.Fatalf("failed to add the existing ingress: %v", err)
+	}
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewAlreadyExists(networkingv1.Resource("ingresses"), name)
+	})
+	var gets []string
+	cliset.PrependReactor("get", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets = append(gets, action.(k8stesting.GetAction).GetName())
+		return false, nil, nil
+	})
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.3" {
+		t.Errorf("GetIngressIP() = %q, want 10.0.0.3", ip)
+	}
+	if len(gets) == 0 {
+		t.Fatal("GetIngressIP() didn't get the existing ingress")
+	}
+	for _, got := range gets {
+		if got != name {
+			t.Errorf("GetIngressIP() got the ingress %q, want %q", got, name)
+		}
+	}
+	if _, err := cliset.NetworkingV1().Ingresses(GetNamespace()).Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+		t.Errorf("the existing ingress was deleted: %v", err)
+	}
+}
+
+func TestGetIngressIPDefaultIngressClass(t *testing.T) {
+	newIngressClass := func(name string, isDefault bool) *networkingv1.IngressClass {
+		class := &networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+		}
+		if isDefault {
+			class.Annotations = map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"}
+		}
+		return class
+	}
+
+	tests := []struct {
+		name      string
+		className string
+		classes   []runtime.Object
+		want      string
+		wantErr   error
+	}{
+		{
+			name:    "default exists",
+			classes: []runtime.Object{newIngressClass("nginx", false), newIngressClass("nginx-default", true)},
+			want:    "nginx-default",
+		},
+		{
+			name:    "no default",
+			classes: []runtime.Object{newIngressClass("nginx", false)},
+			wantErr: ErrNoDefaultIngressClass,
+		},
+		{
+			name:      "explicit override",
+			className: "nginx",
+			classes:   []runtime.Object{newIngressClass("nginx", false), newIngressClass("nginx-default", true)},
+			want:      "nginx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := fake.NewSimpleClientset(tt.classes...)
+			var className *string
+			cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress)
+				ing.Name = ing.GenerateName + "test"
+				ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}
+				className = ing.Spec.IngressClassName
+				return false, nil, nil
+			})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          tt.className,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+			})
+
+			_, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetIngressIP() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if className == nil || *className != tt.want {
+				t.Errorf("probe ingress class = %v, want %q", className, tt.want)
+			}
+		})
+	}
+}
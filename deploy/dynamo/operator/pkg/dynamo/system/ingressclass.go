@@ -0,0 +1,212 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// IngressControllerType identifies the implementation behind an IngressClass.
+type IngressControllerType string
+
+const (
+	IngressControllerUnknown IngressControllerType = ""
+	IngressControllerNginx   IngressControllerType = "nginx"
+	IngressControllerContour IngressControllerType = "contour"
+	IngressControllerTraefik IngressControllerType = "traefik"
+	IngressControllerHAProxy IngressControllerType = "haproxy"
+	IngressControllerALB     IngressControllerType = "alb"
+	IngressControllerIstio   IngressControllerType = "istio"
+)
+
+// ingressControllerPatterns maps substrings of the IngressClass spec.controller
+// to the controller type, checked in order.
+var ingressControllerPatterns = []struct {
+	pattern        string
+	controllerType IngressControllerType
+}{
+	{"nginx", IngressControllerNginx},
+	{"contour", IngressControllerContour},
+	{"traefik", IngressControllerTraefik},
+	{"haproxy", IngressControllerHAProxy},
+	{"ingress.k8s.aws/alb", IngressControllerALB},
+	{"istio", IngressControllerIstio},
+}
+
+// controllerPreferredPathTypes are the path types best supported by the
+// recognized ingress controllers, used when the network config doesn't set one.
+var controllerPreferredPathTypes = map[IngressControllerType]networkingv1.PathType{
+	IngressControllerNginx:   networkingv1.PathTypePrefix,
+	IngressControllerContour: networkingv1.PathTypePrefix,
+	IngressControllerTraefik: networkingv1.PathTypePrefix,
+	IngressControllerHAProxy: networkingv1.PathTypePrefix,
+	IngressControllerALB:     networkingv1.PathTypePrefix,
+	IngressControllerIstio:   networkingv1.PathTypePrefix,
+}
+
+//go:embed ingress_defaults.yaml
+var ingressDefaultsYAML []byte
+
+var controllerDefaultAnnotations = mustLoadControllerDefaultAnnotations(ingressDefaultsYAML)
+
+func mustLoadControllerDefaultAnnotations(data []byte) map[IngressControllerType]map[string]string {
+	defaults := make(map[IngressControllerType]map[string]string)
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		panic(errors.Wrap(err, "failed to parse the embedded ingress controller default annotations"))
+	}
+	return defaults
+}
+
+// IngressClassInfo describes an IngressClass installed in the cluster.
+type IngressClassInfo struct {
+	Name       string `json:"name"`
+	Controller string `json:"controller"`
+	IsDefault  bool   `json:"isDefault"`
+}
+
+// ListIngressClasses returns every IngressClass in the cluster together with its
+// controller and whether it is marked as the cluster default, sorted by name.
+func ListIngressClasses(ctx context.Context, cliset kubernetes.Interface) (classes []IngressClassInfo, err error) {
+	list, err := cliset.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		err = errors.Wrap(err, "failed to list ingress classes")
+		return
+	}
+
+	classes = make([]IngressClassInfo, 0, len(list.Items))
+	for _, ingressClass := range list.Items {
+		classes = append(classes, IngressClassInfo{
+			Name:       ingressClass.Name,
+			Controller: ingressClass.Spec.Controller,
+			IsDefault:  ingressClass.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == "true",
+		})
+	}
+
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].Name < classes[j].Name
+	})
+
+	return
+}
+
+// ErrNoDefaultIngressClass is returned when no ingress class is configured in the
+// network config and none is marked as the cluster default, so no controller
+// would ever admit the probe ingress.
+var ErrNoDefaultIngressClass = errors.Errorf("no ingress class is configured and the cluster has no default ingress class, set %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigIngressClass, consts.KubeConfigMapNameNetworkConfig)
+
+// GetDefaultIngressClass returns the ingress class marked as the cluster default,
+// or nil if there is none.
+func GetDefaultIngressClass(ctx context.Context, cliset kubernetes.Interface) (*IngressClassInfo, error) {
+	classes, err := ListIngressClasses(ctx, cliset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range classes {
+		if classes[i].IsDefault {
+			return &classes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ValidateIngressClass checks that the ingress class with the given name exists,
+// and if it doesn't, returns an error suggesting the classes that do.
+func ValidateIngressClass(ctx context.Context, cliset kubernetes.Interface, className string) error {
+	classes, err := ListIngressClasses(ctx, cliset)
+	if err != nil {
+		return err
+	}
+
+	for _, class := range classes {
+		if class.Name == className {
+			return nil
+		}
+	}
+
+	return errors.Errorf("the ingress class %q does not exist%s", className, suggestIngressClass(classes))
+}
+
+func suggestIngressClass(classes []IngressClassInfo) string {
+	if len(classes) == 0 {
+		return ", and no ingress classes are installed in the cluster"
+	}
+
+	names := make([]string, 0, len(classes))
+	for _, class := range classes {
+		if class.IsDefault {
+			return fmt.Sprintf(", did you mean the default ingress class %q?", class.Name)
+		}
+		names = append(names, class.Name)
+	}
+
+	return fmt.Sprintf(", available ingress classes: %s", strings.Join(names, ", "))
+}
+
+// DetectIngressControllerType returns the controller type for the spec.controller
+// string of an IngressClass, or IngressControllerUnknown if it isn't recognized.
+func DetectIngressControllerType(controller string) IngressControllerType {
+	controller = strings.ToLower(controller)
+	for _, p := range ingressControllerPatterns {
+		if strings.Contains(controller, p.pattern) {
+			return p.controllerType
+		}
+	}
+	return IngressControllerUnknown
+}
+
+// GetIngressControllerType returns the controller type behind the given ingress
+// class, or behind the cluster default class when className is nil. Lookup
+// failures are logged and reported as IngressControllerUnknown, since the
+// controller type only drives optional defaults.
+func GetIngressControllerType(ctx context.Context, cliset kubernetes.Interface, className *string) IngressControllerType {
+	classes, err := ListIngressClasses(ctx, cliset)
+	if err != nil {
+		contextLogger(ctx).Error(err, "Failed to detect the ingress controller type")
+		return IngressControllerUnknown
+	}
+
+	for _, class := range classes {
+		if (className != nil && class.Name == *className) || (className == nil && class.IsDefault) {
+			return DetectIngressControllerType(class.Controller)
+		}
+	}
+	return IngressControllerUnknown
+}
+
+// ControllerDefaultAnnotations returns a copy of the recommended default ingress
+// annotations for the controller type, as shipped in ingress_defaults.yaml.
+func ControllerDefaultAnnotations(controllerType IngressControllerType) map[string]string {
+	defaults := controllerDefaultAnnotations[controllerType]
+	annotations := make(map[string]string, len(defaults))
+	for k, v := range defaults {
+		annotations[k] = v
+	}
+	return annotations
+}
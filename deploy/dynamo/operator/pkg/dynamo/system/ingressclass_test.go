@@ -0,0 +1,189 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newIngressClass(name, controller string, isDefault bool) *networkingv1.IngressClass {
+	class := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       networkingv1.IngressClassSpec{Controller: controller},
+	}
+	if isDefault {
+		class.Annotations = map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"}
+	}
+	return class
+}
+
+func TestValidateIngressClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		classes   []runtime.Object
+		className string
+		wantErr   string
+	}{
+		{
+			name:      "exists",
+			classes:   []runtime.Object{newIngressClass("nginx", "k8s.io/ingress-nginx", false)},
+			className: "nginx",
+		},
+		{
+			name:      "no classes",
+			className: "nginx",
+			wantErr:   "no ingress classes are installed",
+		},
+		{
+			name:      "default class suggested",
+			classes:   []runtime.Object{newIngressClass("traefik", "traefik.io/ingress-controller", false), newIngressClass("nginx-internal", "k8s.io/ingress-nginx", true)},
+			className: "nginx",
+			wantErr:   `did you mean the default ingress class "nginx-internal"?`,
+		},
+		{
+			name:      "available classes listed",
+			classes:   []runtime.Object{newIngressClass("traefik", "traefik.io/ingress-controller", false), newIngressClass("contour", "projectcontour.io/contour", false)},
+			className: "nginx",
+			wantErr:   "available ingress classes: contour, traefik",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIngressClass(context.Background(), fake.NewSimpleClientset(tt.classes...), tt.className)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateIngressClass() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateIngressClass() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetDefaultIngressClass(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []runtime.Object
+		want    string
+	}{
+		{name: "no classes"},
+		{name: "no default", classes: []runtime.Object{newIngressClass("nginx", "k8s.io/ingress-nginx", false)}},
+		{
+			name: "default",
+			classes: []runtime.Object{
+				newIngressClass("nginx", "k8s.io/ingress-nginx", false),
+				newIngressClass("traefik", "traefik.io/ingress-controller", true),
+			},
+			want: "traefik",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, err := GetDefaultIngressClass(context.Background(), fake.NewSimpleClientset(tt.classes...))
+			if err != nil {
+				t.Fatalf("GetDefaultIngressClass() error = %v", err)
+			}
+			if tt.want == "" {
+				if class != nil {
+					t.Errorf("GetDefaultIngressClass() = %s, want none", class.Name)
+				}
+				return
+			}
+			if class == nil || class.Name != tt.want || !class.IsDefault {
+				t.Errorf("GetDefaultIngressClass() = %+v, want the default %s", class, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectIngressControllerType(t *testing.T) {
+	tests := []struct {
+		controller string
+		want       IngressControllerType
+	}{
+		{controller: "k8s.io/ingress-nginx", want: IngressControllerNginx},
+		{controller: "nginx.org/ingress-controller", want: IngressControllerNginx},
+		{controller: "projectcontour.io/contour", want: IngressControllerContour},
+		{controller: "Traefik.io/ingress-controller", want: IngressControllerTraefik},
+		{controller: "haproxy.org/ingress-controller/haproxy", want: IngressControllerHAProxy},
+		{controller: "ingress.k8s.aws/alb", want: IngressControllerALB},
+		{controller: "istio.io/ingress-controller", want: IngressControllerIstio},
+		{controller: "example.com/ingress", want: IngressControllerUnknown},
+		{controller: "", want: IngressControllerUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.controller, func(t *testing.T) {
+			if got := DetectIngressControllerType(tt.controller); got != tt.want {
+				t.Errorf("DetectIngressControllerType(%q) = %q, want %q", tt.controller, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerDefaultAnnotations(t *testing.T) {
+	for _, controllerType := range []IngressControllerType{IngressControllerNginx, IngressControllerContour, IngressControllerHAProxy, IngressControllerALB} {
+		if len(ControllerDefaultAnnotations(controllerType)) == 0 {
+			t.Errorf("ControllerDefaultAnnotations(%q) is empty, want the defaults of ingress_defaults.yaml", controllerType)
+		}
+	}
+	if got := ControllerDefaultAnnotations(IngressControllerNginx)["nginx.ingress.kubernetes.io/proxy-body-size"]; got != "0" {
+		t.Errorf("the nginx proxy-body-size default = %q, want 0", got)
+	}
+	if got := ControllerDefaultAnnotations(IngressControllerUnknown); len(got) != 0 {
+		t.Errorf("ControllerDefaultAnnotations() of an unknown controller = %v, want none", got)
+	}
+
+	// The returned annotations are a copy.
+	ControllerDefaultAnnotations(IngressControllerALB)["alb.ingress.kubernetes.io/target-type"] = "instance"
+	if got := ControllerDefaultAnnotations(IngressControllerALB)["alb.ingress.kubernetes.io/target-type"]; got != "ip" {
+		t.Errorf("changing the returned annotations changed the defaults to %q", got)
+	}
+}
+
+func TestIngressDefaultsYAML(t *testing.T) {
+	defaults := mustLoadControllerDefaultAnnotations(ingressDefaultsYAML)
+	for controllerType := range defaults {
+		known := false
+		for _, p := range ingressControllerPatterns {
+			known = known || p.controllerType == controllerType
+		}
+		if !known {
+			t.Errorf("ingress_defaults.yaml has the defaults of the unknown controller type %q", controllerType)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("mustLoadControllerDefaultAnnotations() of invalid YAML didn't panic")
+		}
+	}()
+	mustLoadControllerDefaultAnnotations([]byte("nginx: [not, a, map"))
+}
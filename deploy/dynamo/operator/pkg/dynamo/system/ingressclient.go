@@ -0,0 +1,76 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sync"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// IngressClient is the ingress operations of a namespace the discovery manages
+// its probe ingresses with. The typed client-go ingress client implements it,
+// and is the default; another one, e.g. an in-memory control plane, is set with
+// SetIngressClient.
+type IngressClient interface {
+	Create(ctx context.Context, ingress *networkingv1.Ingress, opts metav1.CreateOptions) (*networkingv1.Ingress, error)
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*networkingv1.Ingress, error)
+	Update(ctx context.Context, ingress *networkingv1.Ingress, opts metav1.UpdateOptions) (*networkingv1.Ingress, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	List(ctx context.Context, opts metav1.ListOptions) (*networkingv1.IngressList, error)
+}
+
+// ingressWatcher is implemented by the IngressClients that support watches, which
+// are otherwise polled.
+type ingressWatcher interface {
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// IngressClientFactory returns the IngressClient of the namespace.
+type IngressClientFactory func(namespace string) IngressClient
+
+var (
+	ingressClientMu      sync.RWMutex
+	ingressClientFactory IngressClientFactory
+)
+
+// SetIngressClient sets the factory of the IngressClients the discovery manages
+// the probe ingresses with instead of the clientset, or restores the clientset
+// with nil. The other resources, e.g. the ingress classes, are still read with
+// the clientset.
+func SetIngressClient(factory IngressClientFactory) {
+	ingressClientMu.Lock()
+	defer ingressClientMu.Unlock()
+	ingressClientFactory = factory
+}
+
+// getIngressClient returns the IngressClient of the namespace, the one of the
+// clientset unless another one is set.
+func getIngressClient(cliset kubernetes.Interface, namespace string) IngressClient {
+	ingressClientMu.RLock()
+	factory := ingressClientFactory
+	ingressClientMu.RUnlock()
+	if factory != nil {
+		return factory(namespace)
+	}
+	return cliset.NetworkingV1().Ingresses(namespace)
+}
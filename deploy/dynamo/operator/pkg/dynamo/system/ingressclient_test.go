@@ -0,0 +1,146 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// memoryIngressClient is an in-memory IngressClient whose ingresses get the
+// load balancer status on creation.
+type memoryIngressClient struct {
+	mu        sync.Mutex
+	status    []networkingv1.IngressLoadBalancerIngress
+	ingresses map[string]*networkingv1.Ingress
+	created   []string
+}
+
+var _ IngressClient = &memoryIngressClient{}
+
+func newMemoryIngressClient(status ...networkingv1.IngressLoadBalancerIngress) *memoryIngressClient {
+	return &memoryIngressClient{status: status, ingresses: make(map[string]*networkingv1.Ingress)}
+}
+
+func (c *memoryIngressClient) Create(_ context.Context, ingress *networkingv1.Ingress, _ metav1.CreateOptions) (*networkingv1.Ingress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ingress = ingress.DeepCopy()
+	if ingress.Name == "" {
+		ingress.Name = ingress.GenerateName + "memory"
+	}
+	if _, ok := c.ingresses[ingress.Name]; ok {
+		return nil, k8serrors.NewAlreadyExists(networkingv1.Resource("ingresses"), ingress.Name)
+	}
+	ingress.Status.LoadBalancer.Ingress = c.status
+	c.ingresses[ingress.Name] = ingress
+	c.created = append(c.created, ingress.Name)
+	return ingress.DeepCopy(), nil
+}
+
+func (c *memoryIngressClient) Get(_ context.Context, name string, _ metav1.GetOptions) (*networkingv1.Ingress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ingress, ok := c.ingresses[name]
+	if !ok {
+		return nil, k8serrors.NewNotFound(networkingv1.Resource("ingresses"), name)
+	}
+	return ingress.DeepCopy(), nil
+}
+
+func (c *memoryIngressClient) Update(_ context.Context, ingress *networkingv1.Ingress, _ metav1.UpdateOptions) (*networkingv1.Ingress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.ingresses[ingress.Name]; !ok {
+		return nil, k8serrors.NewNotFound(networkingv1.Resource("ingresses"), ingress.Name)
+	}
+	c.ingresses[ingress.Name] = ingress.DeepCopy()
+	return ingress.DeepCopy(), nil
+}
+
+func (c *memoryIngressClient) Delete(_ context.Context, name string, _ metav1.DeleteOptions) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.ingresses[name]; !ok {
+		return k8serrors.NewNotFound(networkingv1.Resource("ingresses"), name)
+	}
+	delete(c.ingresses, name)
+	return nil
+}
+
+func (c *memoryIngressClient) List(_ context.Context, _ metav1.ListOptions) (*networkingv1.IngressList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := &networkingv1.IngressList{}
+	for _, ingress := range c.ingresses {
+		list.Items = append(list.Items, *ingress.DeepCopy())
+	}
+	return list, nil
+}
+
+func TestGetIngressIPWithIngressClient(t *testing.T) {
+	ingressCli := newMemoryIngressClient(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.7"})
+	SetIngressClient(func(string) IngressClient { return ingressCli })
+	defer SetIngressClient(nil)
+
+	cliset := fake.NewSimpleClientset(&networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	})
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.7" {
+		t.Errorf("GetIngressIP() = %q, want %q", ip, "10.0.0.7")
+	}
+	if len(ingressCli.created) != 1 {
+		t.Errorf("created probe ingresses = %v, want one", ingressCli.created)
+	}
+	if len(ingressCli.ingresses) != 0 {
+		t.Errorf("probe ingresses left in the ingress client = %d, want 0", len(ingressCli.ingresses))
+	}
+	ingresses, err := cliset.NetworkingV1().Ingresses("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list the clientset ingresses: %v", err)
+	}
+	if len(ingresses.Items) != 0 {
+		t.Errorf("clientset ingresses = %d, want 0", len(ingresses.Items))
+	}
+}
+
+func TestGetIngressClientDefault(t *testing.T) {
+	cliset := fake.NewSimpleClientset()
+	ingressCli := getIngressClient(cliset, "default")
+	if _, ok := ingressCli.(ingressWatcher); !ok {
+		t.Errorf("getIngressClient() = %T, want the clientset's watching ingress client", ingressCli)
+	}
+}
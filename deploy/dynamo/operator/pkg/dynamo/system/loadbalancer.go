@@ -0,0 +1,86 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// LoadBalancerScheme is whether the load balancer provisioned for the probe is
+// reachable from the internet or only from the VPC.
+type LoadBalancerScheme string
+
+const (
+	LoadBalancerSchemeInternetFacing LoadBalancerScheme = "internet-facing"
+	LoadBalancerSchemeInternal       LoadBalancerScheme = "internal"
+)
+
+// loadBalancerAnnotationKeys are the ingress annotations that select the scheme
+// and subnets of the load balancer, for the controllers that provision one load
+// balancer per ingress. The other controllers are fronted by a single Service
+// whose load balancer is configured on the Service itself.
+var loadBalancerAnnotationKeys = map[IngressControllerType]struct {
+	scheme  string
+	subnets string
+}{
+	IngressControllerALB: {
+		scheme:  "alb.ingress.kubernetes.io/scheme",
+		subnets: "alb.ingress.kubernetes.io/subnets",
+	},
+}
+
+func parseLoadBalancerScheme(value string) (LoadBalancerScheme, error) {
+	scheme := LoadBalancerScheme(strings.TrimSpace(value))
+	switch scheme {
+	case "", LoadBalancerSchemeInternetFacing, LoadBalancerSchemeInternal:
+		return scheme, nil
+	default:
+		return "", errors.Errorf("invalid %s in configmap %s: %s, expected %s or %s", consts.KubeConfigMapKeyNetworkConfigLBScheme, consts.KubeConfigMapNameNetworkConfig, scheme, LoadBalancerSchemeInternetFacing, LoadBalancerSchemeInternal)
+	}
+}
+
+// ApplyLoadBalancerSettings translates the load balancer scheme and subnets to
+// the annotations of the controller, so that the probe provisions the same kind of
+// load balancer as the real services. Annotations set explicitly in the network
+// config win. It returns false, applying nothing, when the load balancer of the
+// controller is configured on its Service rather than per ingress.
+func (c *IngressConfig) ApplyLoadBalancerSettings(controllerType IngressControllerType, scheme LoadBalancerScheme, subnets []string) bool {
+	if scheme == "" && len(subnets) == 0 {
+		return true
+	}
+
+	keys, ok := loadBalancerAnnotationKeys[controllerType]
+	if !ok {
+		return false
+	}
+
+	if c.Annotations == nil {
+		c.Annotations = make(map[string]string)
+	}
+	if _, ok := c.Annotations[keys.scheme]; !ok && scheme != "" {
+		c.Annotations[keys.scheme] = string(scheme)
+	}
+	if _, ok := c.Annotations[keys.subnets]; !ok && len(subnets) > 0 {
+		c.Annotations[keys.subnets] = strings.Join(subnets, ",")
+	}
+	return true
+}
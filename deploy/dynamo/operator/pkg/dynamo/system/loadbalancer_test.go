@@ -0,0 +1,106 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLoadBalancerScheme(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    LoadBalancerScheme
+		wantErr bool
+	}{
+		{value: "", want: ""},
+		{value: " internet-facing ", want: LoadBalancerSchemeInternetFacing},
+		{value: "internal", want: LoadBalancerSchemeInternal},
+		{value: "private", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseLoadBalancerScheme(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLoadBalancerScheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseLoadBalancerScheme() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyLoadBalancerSettings(t *testing.T) {
+	tests := []struct {
+		name            string
+		controllerType  IngressControllerType
+		annotations     map[string]string
+		scheme          LoadBalancerScheme
+		subnets         []string
+		wantApplied     bool
+		wantAnnotations map[string]string
+	}{
+		{
+			name:           "none",
+			controllerType: IngressControllerALB,
+			wantApplied:    true,
+		},
+		{
+			name:           "alb",
+			controllerType: IngressControllerALB,
+			scheme:         LoadBalancerSchemeInternal,
+			subnets:        []string{"subnet-a", "subnet-b"},
+			wantApplied:    true,
+			wantAnnotations: map[string]string{
+				"alb.ingress.kubernetes.io/scheme":  "internal",
+				"alb.ingress.kubernetes.io/subnets": "subnet-a,subnet-b",
+			},
+		},
+		{
+			name:           "explicit annotations win",
+			controllerType: IngressControllerALB,
+			annotations:    map[string]string{"alb.ingress.kubernetes.io/scheme": "internet-facing"},
+			scheme:         LoadBalancerSchemeInternal,
+			subnets:        []string{"subnet-a"},
+			wantApplied:    true,
+			wantAnnotations: map[string]string{
+				"alb.ingress.kubernetes.io/scheme":  "internet-facing",
+				"alb.ingress.kubernetes.io/subnets": "subnet-a",
+			},
+		},
+		{
+			name:           "configured on the service",
+			controllerType: IngressControllerNginx,
+			scheme:         LoadBalancerSchemeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig := &IngressConfig{Annotations: tt.annotations}
+			if applied := ingressConfig.ApplyLoadBalancerSettings(tt.controllerType, tt.scheme, tt.subnets); applied != tt.wantApplied {
+				t.Errorf("ApplyLoadBalancerSettings() = %v, want %v", applied, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(ingressConfig.Annotations, tt.wantAnnotations) {
+				t.Errorf("Annotations = %v, want %v", ingressConfig.Annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
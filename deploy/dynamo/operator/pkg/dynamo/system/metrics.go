@@ -0,0 +1,148 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics are the Prometheus metrics of the domain suffix detection.
+type Metrics struct {
+	DetectionDuration prometheus.Histogram
+	DetectionFailures *prometheus.CounterVec
+	// ConfigInfo and DiscoveryInfo are info gauges, always 1, with one series
+	// per namespace describing its effective discovery config and its current
+	// domain suffix.
+	ConfigInfo    *prometheus.GaugeVec
+	DiscoveryInfo *prometheus.GaugeVec
+}
+
+var metrics = &Metrics{
+	DetectionDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dynamo_domain_suffix_detection_seconds",
+		Help:    "Time taken to detect the domain suffix.",
+		Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	}),
+	DetectionFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dynamo_domain_suffix_detection_failures_total",
+		Help: "Number of failed domain suffix detections, by reason.",
+	}, []string{"reason"}),
+	ConfigInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dynamo_domain_suffix_config_info",
+		Help: "The effective domain suffix discovery config of a namespace.",
+	}, []string{"namespace", "ingress_class", "controller", "mode"}),
+	DiscoveryInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dynamo_domain_suffix_info",
+		Help: "The current domain suffix of a namespace, the IP it resolves to and the magic DNS domain or template it was composed with.",
+	}, []string{"namespace", "domain_suffix", "ip", "magic_dns"}),
+}
+
+// RegisterMetrics registers the package metrics with the registerer and returns
+// their handles. It is safe to call more than once, including with the same
+// registerer from several controllers.
+func RegisterMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	for _, collector := range []prometheus.Collector{metrics.DetectionDuration, metrics.DetectionFailures, metrics.ConfigInfo, metrics.DiscoveryInfo} {
+		if err := reg.Register(collector); err != nil {
+			var alreadyRegistered prometheus.AlreadyRegisteredError
+			if errors.As(err, &alreadyRegistered) && alreadyRegistered.ExistingCollector == collector {
+				continue
+			}
+			return nil, errors.Wrap(err, "failed to register the domain suffix detection metrics")
+		}
+	}
+	return metrics, nil
+}
+
+// MustRegister is RegisterMetrics for the callers that can't recover from a
+// registration failure, e.g. at startup. It panics if the metrics can't be
+// registered.
+func MustRegister(reg prometheus.Registerer) {
+	if _, err := RegisterMetrics(reg); err != nil {
+		panic(err)
+	}
+}
+
+// The reasons of DetectionFailures: a timeout, a ProbeCreateError, or a
+// ResolveError or ErrIngressNoAddress. The other failures are counted as
+// detectionFailureReasonOther.
+const (
+	detectionFailureReasonTimeout = "timeout"
+	detectionFailureReasonCreate  = "create_error"
+	detectionFailureReasonResolve = "resolve_error"
+	detectionFailureReasonOther   = "other"
+)
+
+func detectionFailureReason(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return detectionFailureReasonTimeout
+	}
+	var createErr *ProbeCreateError
+	if errors.As(err, &createErr) {
+		return detectionFailureReasonCreate
+	}
+	var resolveErr *ResolveError
+	if errors.As(err, &resolveErr) || errors.Is(err, ErrIngressNoAddress) {
+		return detectionFailureReasonResolve
+	}
+	return detectionFailureReasonOther
+}
+
+// recordDetection observes the duration of a detection started at start, and
+// counts it as failed if err isn't nil.
+func recordDetection(start time.Time, err error) {
+	metrics.DetectionDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.DetectionFailures.WithLabelValues(detectionFailureReason(err)).Inc()
+	}
+}
+
+// setInfo replaces the series of the namespace, so that an info gauge never has
+// more than one series per namespace.
+func setInfo(gauge *prometheus.GaugeVec, labels prometheus.Labels) {
+	gauge.DeletePartialMatch(prometheus.Labels{"namespace": labels["namespace"]})
+	gauge.With(labels).Set(1)
+}
+
+func recordConfigInfo(namespace string, className *string, controllerType IngressControllerType, mode NetworkMode) {
+	class := ""
+	if className != nil {
+		class = *className
+	}
+	setInfo(metrics.ConfigInfo, prometheus.Labels{
+		"namespace":     namespace,
+		"ingress_class": class,
+		"controller":    string(controllerType),
+		"mode":          string(mode),
+	})
+}
+
+// recordDiscoveryInfo sets the discovery info of the namespace. magicDNS is the
+// magic DNS domain or template the domain suffix was composed with, or empty if
+// it was set rather than discovered.
+func recordDiscoveryInfo(namespace, domainSuffix, ip, magicDNS string) {
+	setInfo(metrics.DiscoveryInfo, prometheus.Labels{
+		"namespace":     namespace,
+		"domain_suffix": domainSuffix,
+		"ip":            ip,
+		"magic_dns":     magicDNS,
+	})
+}
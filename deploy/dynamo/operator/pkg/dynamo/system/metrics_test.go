@@ -0,0 +1,210 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func detectionFailures(t *testing.T, reason string) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := metrics.DetectionFailures.WithLabelValues(reason).Write(&m); err != nil {
+		t.Fatalf("failed to read the %s detection failures: %v", reason, err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestGetIngressIPRecordsDetectionFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantReason string
+		setup      func(cliset *k8stesting.Fake)
+	}{
+		{
+			// The load balancer status is never populated.
+			name:       "timeout",
+			wantReason: detectionFailureReasonTimeout,
+		},
+		{
+			name:       "create error",
+			wantReason: detectionFailureReasonCreate,
+			setup: func(cliset *k8stesting.Fake) {
+				cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, k8serrors.NewForbidden(schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}, "", nil)
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset()
+			if tt.setup != nil {
+				tt.setup(&cliset.Fake)
+			}
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:     "50ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+			})
+
+			before := detectionFailures(t, tt.wantReason)
+			if _, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset); err == nil {
+				t.Fatal("GetIngressIP() succeeded, want an error")
+			}
+			if got := detectionFailures(t, tt.wantReason) - before; got != 1 {
+				t.Errorf("the %s detection failures increased by %v, want 1", tt.wantReason, got)
+			}
+		})
+	}
+}
+
+func TestMustRegister(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	MustRegister(reg)
+	// Registering again with the same registerer is a no-op.
+	MustRegister(reg)
+
+	metrics.DetectionDuration.Observe(1)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "dynamo_domain_suffix_detection_seconds" {
+			return
+		}
+	}
+	t.Error("the dynamo_domain_suffix_detection_seconds histogram isn't registered")
+}
+
+func TestRegisterMetrics(t *testing.T) {
+	got, err := RegisterMetrics(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("RegisterMetrics() error = %v", err)
+	}
+	if got != metrics {
+		t.Error("RegisterMetrics() returned other handles than the package metrics")
+	}
+
+	// Another collector already registered under one of the names is a
+	// conflict, not a repeated registration.
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "dynamo_domain_suffix_detection_seconds",
+		Help: "Time taken to detect the domain suffix.",
+	}))
+	if _, err := RegisterMetrics(reg); err == nil {
+		t.Error("RegisterMetrics() with a conflicting collector succeeded, want an error")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRegister() with a conflicting collector didn't panic")
+		}
+	}()
+	MustRegister(reg)
+}
+
+// infoSeries returns the labels of the series of the info gauge of the namespace.
+func infoSeries(t *testing.T, gauge *prometheus.GaugeVec, namespace string) []map[string]string {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 100)
+	gauge.Collect(ch)
+	close(ch)
+
+	var series []map[string]string
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("failed to read the info gauge: %v", err)
+		}
+		labels := make(map[string]string)
+		for _, pair := range m.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if labels["namespace"] == namespace {
+			series = append(series, labels)
+		}
+	}
+	return series
+}
+
+func TestRecordConfigInfo(t *testing.T) {
+	className := "nginx"
+	recordConfigInfo("info-a", &className, IngressControllerNginx, NetworkModeLoadBalancer)
+	recordConfigInfo("info-b", nil, IngressControllerUnknown, NetworkModeClusterIP)
+	// A new config of the namespace replaces its series.
+	recordConfigInfo("info-a", nil, IngressControllerUnknown, NetworkModeGateway)
+
+	series := infoSeries(t, metrics.ConfigInfo, "info-a")
+	if len(series) != 1 {
+		t.Fatalf("info-a has %d config info series, want 1", len(series))
+	}
+	if series[0]["ingress_class"] != "" || series[0]["controller"] != string(IngressControllerUnknown) || series[0]["mode"] != string(NetworkModeGateway) {
+		t.Errorf("the config info of info-a is %v, want the latest config", series[0])
+	}
+	if series := infoSeries(t, metrics.ConfigInfo, "info-b"); len(series) != 1 || series[0]["mode"] != string(NetworkModeClusterIP) {
+		t.Errorf("the config info of info-b is %v, want it kept", series)
+	}
+}
+
+func TestGetDomainSuffixRecordsDiscoveryInfo(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate:         "ip-%s.dns.corp.internal",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	if _, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset); err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+
+	series := infoSeries(t, metrics.DiscoveryInfo, configMap.Namespace)
+	if len(series) != 1 {
+		t.Fatalf("%s has %d discovery info series, want 1", configMap.Namespace, len(series))
+	}
+	want := map[string]string{
+		"namespace":     configMap.Namespace,
+		"domain_suffix": "ip-10.0.0.1.dns.corp.internal",
+		"ip":            "10.0.0.1",
+		"magic_dns":     "ip-%s.dns.corp.internal",
+	}
+	for name, value := range want {
+		if series[0][name] != value {
+			t.Errorf("the discovery info %s = %q, want %q", name, series[0][name], value)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// NetworkMode is how the address the domain suffix is built from is discovered,
+// as set by the network-mode key of the network config.
+type NetworkMode string
+
+const (
+	// NetworkModeLoadBalancer, the default, creates a probe ingress and waits
+	// for the ingress controller to publish its load balancer address.
+	NetworkModeLoadBalancer NetworkMode = "loadbalancer"
+	// NetworkModeClusterIP uses the ClusterIP of the ingress controller Service,
+	// for internal-only deployments. No probe ingress is created.
+	NetworkModeClusterIP NetworkMode = "clusterip"
+	// NetworkModeGateway creates a probe Gateway and HTTPRoute of the Gateway
+	// API instead of a probe ingress, and reads the Gateway addresses.
+	NetworkModeGateway NetworkMode = "gateway"
+)
+
+func parseNetworkMode(configMap *corev1.ConfigMap) (NetworkMode, error) {
+	mode := NetworkMode(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigNetworkMode]))
+	switch mode {
+	case "":
+		return NetworkModeLoadBalancer, nil
+	case NetworkModeLoadBalancer, NetworkModeClusterIP, NetworkModeGateway:
+		return mode, nil
+	default:
+		return "", errors.Errorf("invalid %s in configmap %s: %s, expected one of %s, %s, %s", consts.KubeConfigMapKeyNetworkConfigNetworkMode, consts.KubeConfigMapNameNetworkConfig, mode, NetworkModeLoadBalancer, NetworkModeClusterIP, NetworkModeGateway)
+	}
+}
+
+// getIngressControllerClusterIP returns the ClusterIP of the ingress controller
+// Service.
+func getIngressControllerClusterIP(ctx context.Context, cliset kubernetes.Interface, config *discoveryConfig, className *string) (string, error) {
+	svc, err := findIngressControllerService(ctx, cliset, config, className)
+	if err != nil {
+		return "", err
+	}
+	clusterIP := svc.Spec.ClusterIP
+	if clusterIP == "" || clusterIP == corev1.ClusterIPNone {
+		return "", errors.Errorf("the ingress controller service %s/%s has no cluster IP", svc.Namespace, svc.Name)
+	}
+	return clusterIP, nil
+}
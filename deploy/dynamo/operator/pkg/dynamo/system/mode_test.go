@@ -0,0 +1,87 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestParseNetworkMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    NetworkMode
+		wantErr bool
+	}{
+		{value: "", want: NetworkModeLoadBalancer},
+		{value: " clusterip ", want: NetworkModeClusterIP},
+		{value: "gateway", want: NetworkModeGateway},
+		{value: "nodeport", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		mode, err := parseNetworkMode(newNetworkConfigMap(map[string]string{consts.KubeConfigMapKeyNetworkConfigNetworkMode: tt.value}))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNetworkMode(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if mode != tt.want {
+			t.Errorf("parseNetworkMode(%q) = %s, want %s", tt.value, mode, tt.want)
+		}
+	}
+}
+
+func TestGetIngressIPClusterIPMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		clusterIP string
+		wantErr   bool
+	}{
+		{name: "cluster IP", clusterIP: "10.96.0.10"},
+		{name: "headless", clusterIP: corev1.ClusterIPNone, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newControllerService("ingress", "edge", nil)
+			svc.Spec.ClusterIP = tt.clusterIP
+			cliset := fake.NewSimpleClientset(svc)
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigNetworkMode:              string(NetworkModeClusterIP),
+				consts.KubeConfigMapKeyNetworkConfigIngressControllerService: "ingress/edge",
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIngressIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && ip != tt.clusterIP {
+				t.Errorf("GetIngressIP() = %q, want the cluster IP %s", ip, tt.clusterIP)
+			}
+			for _, action := range cliset.Actions() {
+				if action.GetVerb() == "create" {
+					t.Errorf("GetIngressIP() created a %s, want no probe in the clusterip mode", action.GetResource().Resource)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// classProbe is the probe ingress of one of the ProbeIngressClasses.
+type classProbe struct {
+	name           string
+	class          string
+	controllerType IngressControllerType
+	checker        ReadinessChecker
+}
+
+// discoverMultiClassIngressAddresses is discoverIngressAddresses creating a probe
+// ingress for each of the ProbeIngressClasses, and using the first one to get an
+// address. All the probes are deleted when it returns.
+func discoverMultiClassIngressAddresses(ctx, parentCtx context.Context, logger logr.Logger, configMap *corev1.ConfigMap, cliset kubernetes.Interface, namespace, correlationID string, ingressConfig *IngressConfig, discoveryConfig *discoveryConfig) (addresses []IngressAddress, probeName string, err error) {
+	probeNamespace, err := getProbeNamespace(ctx, cliset, namespace, discoveryConfig)
+	if err != nil {
+		return
+	}
+	discoveryConfig = lookupProbeBackendPort(ctx, logger, cliset, probeNamespace, discoveryConfig)
+	ingressCli := getIngressClient(cliset, probeNamespace)
+
+	probes := make([]classProbe, 0, len(discoveryConfig.ProbeIngressClasses))
+	names := make([]string, 0, len(discoveryConfig.ProbeIngressClasses))
+	for _, class := range discoveryConfig.ProbeIngressClasses {
+		if discoveryConfig.IngressClassCheck {
+			if err = ValidateIngressClass(ctx, cliset, class); err != nil {
+				err = errors.Wrapf(err, "failed to check the ingress class, %s in configmap %s turns the check off", consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassCheck, consts.KubeConfigMapNameNetworkConfig)
+				return
+			}
+		}
+
+		// Each class gets the defaults of its own controller.
+		controllerType := GetIngressControllerType(ctx, cliset, &class)
+		classConfig := *ingressConfig
+		classConfig.ClassName = &class
+		classConfig.Annotations = maps.Clone(ingressConfig.Annotations)
+		var probe *networkingv1.Ingress
+		probe, err = renderProbeIngress(logger, probeNamespace, correlationID, &classConfig, discoveryConfig, controllerType)
+		if err != nil {
+			return
+		}
+		probe.GenerateName += class + "-"
+		if owner, ok := probeOwnerFromContext(ctx); ok {
+			probe.OwnerReferences = []metav1.OwnerReference{owner}
+		}
+
+		logger.Info("Creating ingress to get a ingress IP automatically", "ingress", probe.GenerateName, "ingressClass", class)
+		recordEventf(configMap, corev1.EventTypeNormal, EventReasonCreatingProbeIngress, "Creating the probe ingress %s to discover the domain suffix", probe.GenerateName)
+		var created *networkingv1.Ingress
+		err = traceSpan(ctx, spanCreateProbeIngress, func(ctx context.Context) error {
+			return retryCreate(discoveryConfig.CreateRetries, func() (err error) {
+				created, err = ingressCli.Create(ctx, probe, metav1.CreateOptions{})
+				return
+			})
+		}, spanAttrProbeIngress.String(probe.GenerateName), spanAttrIngressClass.String(class))
+		if err != nil {
+			err = &ProbeCreateError{Kind: "ingress", Name: probe.GenerateName, Err: err}
+			return
+		}
+		var done func()
+		ctx, done, err = trackProbeIngress(ctx, cliset, probeNamespace, created.Name)
+		if err != nil {
+			return
+		}
+		defer done()
+		probes = append(probes, classProbe{name: created.Name, class: class, controllerType: controllerType, checker: getReadinessChecker(controllerType, discoveryConfig)})
+		names = append(names, created.Name)
+	}
+	probeName = strings.Join(names, ",")
+
+	logger.Info("Waiting for any of the ingresses to be ready", "ingresses", names)
+	recordEventf(configMap, corev1.EventTypeNormal, EventReasonWaitingForLoadBalancer, "Waiting for the load balancer of any of the probe ingresses %s", probeName)
+	var ing *networkingv1.Ingress
+	var winner classProbe
+	err = traceWait(ctx, spanWaitForIngressReady, func(ctx context.Context) (err error) {
+		ing, winner, err = waitForFirstIngressReady(ctx, ingressCli, probes, discoveryConfig)
+		return
+	}, spanAttrProbeIngress.String(probeName))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			recordEventf(configMap, corev1.EventTypeWarning, EventReasonDomainSuffixTimeout, "None of the probe ingresses %s got a load balancer address in time", probeName)
+		}
+		err = errors.Wrapf(err, "failed to wait for any of the ingresses %s to be ready", probeName)
+		return
+	}
+	probeName = ing.Name
+	logger.Info("Ingress is ready", "ingress", ing.Name, "ingressClass", winner.class)
+	recordConfigInfo(namespace, &winner.class, winner.controllerType, discoveryConfig.NetworkMode)
+
+	statusOwner := fmt.Sprintf("the ingress %s", ing.Name)
+	err = traceSpan(ctx, spanResolveIngressAddresses, func(ctx context.Context) (err error) {
+		addresses, err = resolveLoadBalancerAddresses(ctx, ing.Status.LoadBalancer.Ingress, statusOwner, discoveryConfig)
+		return
+	}, spanAttrProbeIngress.String(ing.Name))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the address of %s", statusOwner)
+		return
+	}
+
+	var probeHost string
+	if len(ing.Spec.Rules) > 0 {
+		probeHost = ing.Spec.Rules[0].Host
+	}
+	if err = waitForAddressesReachable(ctx, logger, addressIPs(addresses), probeHost, discoveryConfig); err != nil {
+		addresses = nil
+		return
+	}
+	return
+}
+
+// waitForFirstIngressReady waits for the probe ingresses at once, each with the
+// readiness checker of its controller, and returns the first one to be ready.
+// The waits of the others are canceled then.
+func waitForFirstIngressReady(ctx context.Context, ingressCli IngressClient, probes []classProbe, discoveryConfig *discoveryConfig) (*networkingv1.Ingress, classProbe, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		ing   *networkingv1.Ingress
+		probe classProbe
+		err   error
+	}
+	// Buffered, so that the waits still running when one wins don't block.
+	results := make(chan result, len(probes))
+	for _, probe := range probes {
+		go func(probe classProbe) {
+			ing, err := waitForIngressReady(ctx, ingressCli, probe.name, discoveryConfig.PollInterval, discoveryConfig.WaitTimeout, discoveryConfig.PollImmediately, probe.checker)
+			results <- result{ing: ing, probe: probe, err: err}
+		}(probe)
+	}
+
+	var firstErr error
+	for range probes {
+		r := <-results
+		if r.err == nil {
+			return r.ing, r.probe, nil
+		}
+		if firstErr == nil {
+			firstErr = errors.Wrapf(r.err, "the ingress %s of the ingress class %s", r.probe.name, r.probe.class)
+		}
+	}
+	return nil, classProbe{}, firstErr
+}
@@ -0,0 +1,145 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// newMultiClassClientset returns a clientset with the nginx and traefik ingress
+// classes, where the probe ingresses of a class get the address in status, if
+// any.
+func newMultiClassClientset(status map[string]string) (*fake.Clientset, *[]string) {
+	cliset := fake.NewSimpleClientset(
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}, Spec: networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"}},
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "traefik"}, Spec: networkingv1.IngressClassSpec{Controller: "traefik.io/ingress-controller"}},
+	)
+	var mu sync.Mutex
+	var created []string
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress)
+		if ing.Name == "" {
+			ing.Name = ing.GenerateName + "test"
+		}
+		if ip := status[*ing.Spec.IngressClassName]; ip != "" {
+			ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: ip}}
+		}
+		mu.Lock()
+		created = append(created, ing.Name)
+		mu.Unlock()
+		return false, nil, nil
+	})
+	return cliset, &created
+}
+
+func TestGetIngressIPProbeIngressClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  map[string]string
+		want    string
+		wantErr error
+	}{
+		{name: "second class ready first", status: map[string]string{"traefik": "10.0.0.2"}, want: "10.0.0.2"},
+		{name: "first class ready first", status: map[string]string{"nginx": "10.0.0.1"}, want: "10.0.0.1"},
+		{name: "none ready", wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset, created := newMultiClassClientset(tt.status)
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses: "nginx, traefik",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:        "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:         "100ms",
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetIngressIP() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			} else if ip != tt.want {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, tt.want)
+			}
+
+			if len(*created) != 2 {
+				t.Errorf("created the probe ingresses %v, want one per ingress class", *created)
+			}
+			ingresses, err := cliset.NetworkingV1().Ingresses(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list the ingresses: %v", err)
+			}
+			if len(ingresses.Items) != 0 {
+				t.Errorf("%d probe ingresses are left, want all of them deleted", len(ingresses.Items))
+			}
+		})
+	}
+}
+
+func TestParseDiscoveryConfigProbeIngressClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    []string
+		wantErr string
+	}{
+		{name: "unset"},
+		{name: "list", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses: " nginx ,traefik"}, want: []string{"nginx", "traefik"}},
+		{name: "repeated", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses: "nginx,nginx"}, wantErr: "the ingress class nginx is repeated"},
+		{name: "empty class", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses: "nginx,"}, wantErr: `invalid ingress class ""`},
+		{
+			name: "with probe hosts",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses: "nginx,traefik",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts:          "2",
+			},
+			wantErr: "can't be combined",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseDiscoveryConfig(newNetworkConfigMap(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseDiscoveryConfig() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDiscoveryConfig() error = %v", err)
+			}
+			if strings.Join(config.ProbeIngressClasses, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ProbeIngressClasses = %v, want %v", config.ProbeIngressClasses, tt.want)
+			}
+		})
+	}
+}
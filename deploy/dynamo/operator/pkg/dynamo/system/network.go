@@ -19,13 +19,1098 @@ package system
 
 import (
 	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
 )
 
+// ErrNetworkConfigImmutable is returned when the discovered domain suffix can't be
+// persisted because the network configmap is immutable and no status configmap
+// is configured.
+var ErrNetworkConfigImmutable = errors.New("the network configmap is immutable")
+
+// ErrNetworkConfigNotFound is returned when there is no network configmap, e.g.
+// while the cluster is bootstrapped, so that callers can requeue. The error also
+// matches k8serrors.IsNotFound.
+var ErrNetworkConfigNotFound = errors.New("the network configmap doesn't exist")
+
+// networkConfigNotFoundError is the NotFound error of the network configmap,
+// which is also ErrNetworkConfigNotFound.
+type networkConfigNotFoundError struct {
+	err error
+}
+
+func (e *networkConfigNotFoundError) Error() string {
+	return e.err.Error()
+}
+
+func (e *networkConfigNotFoundError) Unwrap() error {
+	return e.err
+}
+
+func (e *networkConfigNotFoundError) Is(target error) bool {
+	return target == ErrNetworkConfigNotFound
+}
+
+// ErrAmbiguousNetworkConfig is returned when several configmaps match the network
+// config selector and none of them takes precedence.
+var ErrAmbiguousNetworkConfig = errors.New("several network configmaps match with the same precedence")
+
+// GetNetworkConfigConfigMapBySelector returns the network configmap among the
+// configmaps of the namespace matching the label selector, for setups that don't
+// use the fixed consts.KubeConfigMapNameNetworkConfig name. See
+// SelectNetworkConfigConfigMap for the precedence between several matches.
+func GetNetworkConfigConfigMapBySelector(ctx context.Context, cliset kubernetes.Interface, namespace, selector string) (*corev1.ConfigMap, error) {
+	configMaps, err := cliset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the configmaps matching %s in namespace %s", selector, namespace)
+	}
+	if len(configMaps.Items) == 0 {
+		return nil, k8serrors.NewNotFound(corev1.Resource("configmaps"), selector)
+	}
+	return SelectNetworkConfigConfigMap(configMaps.Items)
+}
+
+// SelectNetworkConfigConfigMap picks the network configmap among several matches:
+// the one with the highest consts.KubeAnnotationDynamoConfigPriority annotation
+// wins (unannotated configmaps have priority 0), then the most recently created
+// one. A tie on both returns ErrAmbiguousNetworkConfig.
+func SelectNetworkConfigConfigMap(configMaps []corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	if len(configMaps) == 0 {
+		return nil, errors.New("no network configmap to select from")
+	}
+
+	priorities := make([]int, len(configMaps))
+	for i := range configMaps {
+		priority_ := strings.TrimSpace(configMaps[i].Annotations[consts.KubeAnnotationDynamoConfigPriority])
+		if priority_ == "" {
+			continue
+		}
+		priority, err := strconv.Atoi(priority_)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s annotation on configmap %s", consts.KubeAnnotationDynamoConfigPriority, configMaps[i].Name)
+		}
+		priorities[i] = priority
+	}
+
+	best := 0
+	tied := false
+	for i := 1; i < len(configMaps); i++ {
+		switch {
+		case priorities[i] > priorities[best],
+			priorities[i] == priorities[best] && configMaps[best].CreationTimestamp.Before(&configMaps[i].CreationTimestamp):
+			best, tied = i, false
+		case priorities[i] == priorities[best] && configMaps[i].CreationTimestamp.Equal(&configMaps[best].CreationTimestamp):
+			tied = true
+		}
+	}
+	if tied {
+		return nil, errors.Wrapf(ErrAmbiguousNetworkConfig, "set the %s annotation to pick one", consts.KubeAnnotationDynamoConfigPriority)
+	}
+
+	return &configMaps[best], nil
+}
+
+// GetNetworkConfigConfigMap returns the network configmap of the namespace of the
+// context (see WithNamespace and WithNetworkConfigName). Outside of the system namespace, the network
+// configmap of the namespace overrides the one of the system namespace key by
+// key, and either of them may be absent. The domain suffix of the system
+// namespace isn't inherited, as it was discovered for its own ingress config,
+// and the network configmap of the namespace may only set the
+// TenantNetworkConfigKeys.
+// A missing network configmap is reported as ErrNetworkConfigNotFound.
 func GetNetworkConfigConfigMap(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) (configMap *corev1.ConfigMap, err error) {
-	configMap, err = configmapGetter(ctx, GetNamespace(), consts.KubeConfigMapNameNetworkConfig)
+	configMap, err = getNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil && k8serrors.IsNotFound(err) {
+		err = &networkConfigNotFoundError{err: err}
+	}
+	return
+}
+
+func getNetworkConfigConfigMap(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) (configMap *corev1.ConfigMap, err error) {
+	namespace, name := namespaceFromContext(ctx), networkConfigNameFromContext(ctx)
+	configMap, err = configmapGetter(ctx, namespace, name)
+	if namespace == GetNamespace() {
+		return
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return
+	}
+	local := configMap
+	if local != nil {
+		local = filterTenantNetworkConfig(discoveryLogger(ctx, CorrelationIDFromContext(ctx)), local)
+	}
+
+	global, err := configmapGetter(ctx, GetNamespace(), name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) && local != nil {
+			return local, nil
+		}
+		return nil, err
+	}
+	return mergeNetworkConfigConfigMaps(namespace, name, local, global), nil
+}
+
+// TenantNetworkConfigKeys are the keys the network configmap of a namespace other
+// than the system one may set: the ones shaping the ingresses of the namespace.
+// The other keys, such as the post discovery hook or the probe namespace, are
+// operator settings only the network configmap of the system namespace sets, as
+// the tenants are not trusted with them.
+var TenantNetworkConfigKeys = []string{
+	consts.KubeConfigMapKeyNetworkConfigDomainSuffix,
+	consts.KubeConfigMapKeyNetworkConfigIngressClass,
+	consts.KubeConfigMapKeyNetworkConfigIngressAnnotations,
+	consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase,
+	consts.KubeConfigMapKeyNetworkConfigIngressRemoveAnnotations,
+	consts.KubeConfigMapKeyNetworkConfigIngressPath,
+	consts.KubeConfigMapKeyNetworkConfigIngressPathType,
+	consts.KubeConfigMapKeyNetworkConfigIngressPaths,
+	consts.KubeConfigMapKeyNetworkConfigIngressDefaultBackend,
+	consts.KubeConfigMapKeyNetworkConfigIngressTLS,
+	consts.KubeConfigMapKeyNetworkConfigIngressTLSMode,
+	consts.KubeConfigMapKeyNetworkConfigIngressStaticTLSSecretName,
+	consts.KubeConfigMapKeyNetworkConfigIngressBackendProtocol,
+	consts.KubeConfigMapKeyNetworkConfigCertManagerIssuer,
+	consts.KubeConfigMapKeyNetworkConfigIngressRewriteTarget,
+	consts.KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware,
+}
+
+var tenantNetworkConfigKeys = func() map[string]bool {
+	keys := make(map[string]bool, len(TenantNetworkConfigKeys))
+	for _, key := range TenantNetworkConfigKeys {
+		keys[key] = true
+	}
+	return keys
+}()
+
+// filterTenantNetworkConfig returns a copy of the network configmap of a tenant
+// namespace with only the TenantNetworkConfigKeys, and the per annotation keys,
+// logging the other keys it ignores.
+func filterTenantNetworkConfig(logger logr.Logger, configMap *corev1.ConfigMap) *corev1.ConfigMap {
+	filtered := configMap.DeepCopy()
+	var ignored []string
+	for key := range filtered.Data {
+		if tenantNetworkConfigKeys[key] || strings.HasPrefix(key, consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation) {
+			continue
+		}
+		delete(filtered.Data, key)
+		ignored = append(ignored, key)
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		logger.Info("Ignoring the keys of the network config of the namespace that only the system namespace may set", "configmap", configMap.Namespace+"/"+configMap.Name, "keys", ignored)
+	}
+	return filtered
+}
+
+// mergeNetworkConfigConfigMaps returns a copy of the local network configmap with
+// the keys it doesn't set taken from the global one. If there is no local one, the
+// result is a configmap of the namespace that doesn't exist yet, which the
+// discovered domain suffix is persisted to.
+func mergeNetworkConfigConfigMaps(namespace, name string, local, global *corev1.ConfigMap) *corev1.ConfigMap {
+	merged := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if local != nil {
+		merged = local.DeepCopy()
+	}
+	if merged.Data == nil {
+		merged.Data = make(map[string]string, len(global.Data))
+	}
+	for key, value := range global.Data {
+		if key == consts.KubeConfigMapKeyNetworkConfigDomainSuffix {
+			continue
+		}
+		if _, ok := merged.Data[key]; !ok {
+			merged.Data[key] = value
+		}
+	}
+	return merged
+}
+
+// NetworkConfig is the network config, parsed from a single read of the network
+// configmap.
+type NetworkConfig struct {
+	Ingress *IngressConfig
+	// DomainSuffix is the domain suffix set in the network config, if any.
+	DomainSuffix string
+	// DomainSuffixAutoGenerated reports whether DomainSuffix is a magic DNS one,
+	// as persisted by the discovery, rather than a real domain, for policies
+	// flagging magic DNS in production. A magic DNS suffix set by hand is
+	// flagged the same.
+	DomainSuffixAutoGenerated bool
+	// MagicDNS is the magic DNS domain, and MagicDNSTemplate the template
+	// taking precedence over it when set.
+	MagicDNS         string
+	MagicDNSTemplate string
+	TLS              []IngressTLSConfig
+	// TLSMode is the ingress TLS mode set in the network config, "none",
+	// "auto" or "static", or empty if unset.
+	TLSMode string
+}
+
+// GetNetworkConfig fetches the network configmap once and parses all of it.
+// With WithAllowMissingNetworkConfig, a missing network configmap is parsed as an
+// empty one, i.e. all the defaults.
+func GetNetworkConfig(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) (networkConfig *NetworkConfig, err error) {
+	configMap, err := getNetworkConfigConfigMapOrDefault(ctx, configmapGetter)
+	if err != nil {
+		return
+	}
+	return parseNetworkConfig(configMap)
+}
+
+// getNetworkConfigConfigMapOrDefault is GetNetworkConfigConfigMap, returning an
+// empty network configmap instead of ErrNetworkConfigNotFound if the context
+// allows it.
+func getNetworkConfigConfigMapOrDefault(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) (configMap *corev1.ConfigMap, err error) {
+	configMap, err = GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if errors.Is(err, ErrNetworkConfigNotFound) && allowMissingNetworkConfigFromContext(ctx) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      networkConfigNameFromContext(ctx),
+				Namespace: namespaceFromContext(ctx),
+			},
+		}
+		err = nil
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get configmap %s", networkConfigNameFromContext(ctx))
+	}
 	return
 }
+
+type allowMissingNetworkConfigKey struct{}
+
+// WithAllowMissingNetworkConfig returns a context that makes GetNetworkConfig,
+// GetIngressConfig and GetIngressConfigWithSource return the default config
+// instead of ErrNetworkConfigNotFound when there is no network configmap.
+func WithAllowMissingNetworkConfig(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowMissingNetworkConfigKey{}, true)
+}
+
+func allowMissingNetworkConfigFromContext(ctx context.Context) bool {
+	allow, _ := ctx.Value(allowMissingNetworkConfigKey{}).(bool)
+	return allow
+}
+
+// SensitiveNetworkConfigKeys are the network config keys for which the network
+// secret takes precedence over the network configmap: the TLS sections and the
+// (base) ingress annotations, which may carry inline certificates or credentials.
+var SensitiveNetworkConfigKeys = []string{
+	consts.KubeConfigMapKeyNetworkConfigIngressTLS,
+	consts.KubeConfigMapKeyNetworkConfigIngressAnnotations,
+	consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase,
+}
+
+// GetNetworkConfigWithSecret is GetNetworkConfig overlaid with the secret of
+// the same name as the network configmap. The secret values of the
+// SensitiveNetworkConfigKeys take precedence over the configmap ones, while its
+// other keys only fill in the ones missing from the configmap. Either of the
+// configmap and the secret may be absent, but not both.
+func GetNetworkConfigWithSecret(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), secretGetter func(ctx context.Context, namespace, name string) (*corev1.Secret, error)) (networkConfig *NetworkConfig, err error) {
+	name := networkConfigNameFromContext(ctx)
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			err = errors.Wrapf(err, "failed to get configmap %s", name)
+			return
+		}
+		configMap = nil
+	}
+
+	secret, err := secretGetter(ctx, namespaceFromContext(ctx), name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			err = errors.Wrapf(err, "failed to get secret %s", name)
+			return
+		}
+		if configMap == nil {
+			err = errors.Wrapf(err, "neither configmap nor secret %s found", name)
+			return
+		}
+		secret = nil
+	}
+
+	return parseNetworkConfig(overlayNetworkConfigSecret(configMap, secret))
+}
+
+// overlayNetworkConfigSecret returns a copy of configMap with the data of secret
+// merged in, see GetNetworkConfigWithSecret. Either of them may be nil.
+func overlayNetworkConfigSecret(configMap *corev1.ConfigMap, secret *corev1.Secret) *corev1.ConfigMap {
+	if configMap == nil {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secret.Name,
+				Namespace: secret.Namespace,
+			},
+		}
+	} else {
+		configMap = configMap.DeepCopy()
+	}
+	if secret == nil {
+		return configMap
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string, len(secret.Data))
+	}
+	sensitive := make(map[string]bool, len(SensitiveNetworkConfigKeys))
+	for _, key := range SensitiveNetworkConfigKeys {
+		sensitive[key] = true
+	}
+	for key, value := range secret.Data {
+		if _, ok := configMap.Data[key]; ok && !sensitive[key] {
+			continue
+		}
+		configMap.Data[key] = string(value)
+	}
+	return configMap
+}
+
+func parseNetworkConfig(configMap *corev1.ConfigMap) (networkConfig *NetworkConfig, err error) {
+	ingressConfig, err := ParseIngressConfig(configMap)
+	if err != nil {
+		return
+	}
+
+	// The discovery tunables are left out, so that a typo in one of them
+	// doesn't prevent reading the ingress config.
+	magicDNSTemplate, err := parseMagicDNSTemplate(configMap)
+	if err != nil {
+		return
+	}
+
+	networkConfig = &NetworkConfig{
+		Ingress:          ingressConfig,
+		DomainSuffix:     strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]),
+		MagicDNS:         GetMagicDNS(),
+		MagicDNSTemplate: magicDNSTemplate,
+		TLS:              ingressConfig.TLS,
+		TLSMode:          strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressTLSMode]),
+	}
+	networkConfig.DomainSuffixAutoGenerated = networkConfig.DomainSuffix != "" && IsMagicDNSSuffix(networkConfig.DomainSuffix, networkConfig.MagicDNS, magicDNSTemplate)
+	return
+}
+
+// discoveryConfig holds the network config tunables of the domain suffix
+// discovery, as opposed to the shape of the ingresses themselves.
+type discoveryConfig struct {
+	// NetworkMode selects how the address the domain suffix is built from is
+	// discovered.
+	NetworkMode NetworkMode
+	// ExternalIP, if set, is the known address of the ingress controller, e.g.
+	// assigned by MetalLB, used as is instead of discovering one, so that no
+	// probe is ever created.
+	ExternalIP string
+	// GatewayClass is the class of the probe Gateway in the gateway network
+	// mode.
+	GatewayClass *string
+
+	// Preflight runs PreflightDiscovery before the probe is created, to fail
+	// fast rather than wait out the timeout. It is off by default, since its
+	// heuristics may misjudge unusual clusters.
+	Preflight bool
+	// IngressClassCheck checks that the configured ingress class exists before
+	// creating the probe ingress, which no controller would ever admit
+	// otherwise. It lists the IngressClasses, so clusters where that's
+	// forbidden turn it off.
+	IngressClassCheck bool
+	// IngressClassMode is where the ingress class is set on the probe ingress.
+	IngressClassMode IngressClassMode
+	// ProbeCatchAll creates the probe ingress rule without a host, for
+	// controllers that only assign an address to catch-all rules.
+	ProbeCatchAll bool
+	// ProbeHostSuffix is the domain the probe ingress host is generated under.
+	ProbeHostSuffix string
+	// ProbeHostSeed, when set, derives the probe host from the namespace and
+	// the seed instead of the pod name or a random one, so that repeated
+	// discoveries use the same host.
+	ProbeHostSeed string
+	// ProbeNamespace, if set, is the namespace the probe ingress is created in,
+	// with its backend service, instead of the one of the discovery, e.g. when
+	// the default-domain service lives elsewhere. The probe ingress GC doesn't
+	// cover it, see CleanupProbeIngresses.
+	ProbeNamespace string
+	// ProbeBackendService and ProbeBackendPort, or ProbeBackendPortName, are
+	// the service, in the probe namespace, the probe routes to.
+	ProbeBackendService  string
+	ProbeBackendPort     int32
+	ProbeBackendPortName string
+	// ProbeBackendPortLookup uses the port of the configured probe backend
+	// service, the one named ProbeBackendPortName if set, or else its first
+	// one, falling back to ProbeBackendPort if it can't be looked up.
+	ProbeBackendPortLookup bool
+	// PostHookFatal makes the failure of a PostDiscoveryHook, or of the post
+	// discovery command of the operator flags, fail the discovery.
+	PostHookFatal bool
+	// ReadyCondition is a status condition type (e.g. "Ready") that, when
+	// True, marks the probe ingress as ready even if it has no address yet.
+	ReadyCondition string
+	// ProbeTTL is annotated on the probe ingress so that StartProbeIngressGC
+	// deletes it once it is older, whatever its global threshold.
+	ProbeTTL time.Duration
+	// StatusConfigMap is the name of a configmap the discovered domain suffix
+	// is persisted to when the network configmap is immutable.
+	StatusConfigMap string
+	// ControllerService (`namespace/name`) or ControllerServiceSelector locate
+	// the Service of the ingress controller.
+	ControllerService         string
+	ControllerServiceSelector string
+	// ServiceStatusFallback, if set, is how long the probe ingress may have no
+	// load balancer address before the one of the ingress controller Service is
+	// used instead, for the controllers that never set the ingress status. Of
+	// the Services matching ControllerServiceSelector, the one of type
+	// LoadBalancer is used. It doesn't apply with a ReadyCondition.
+	ServiceStatusFallback time.Duration
+	// NodeAddressFallback, if set, is the type of the node addresses used as a
+	// last resort, for a NodePort access, when the preflight finds no load
+	// balancer provider or the probe ingress gets no load balancer address in
+	// time.
+	NodeAddressFallback corev1.NodeAddressType
+	// PinnedAddress is the IP or hostname to pick from a multi-address load
+	// balancer status; discovery fails if it isn't in the status.
+	PinnedAddress string
+	// PollInterval, WaitTimeout and ProvisioningBudget default to the values
+	// set with RegisterFlags.
+	PollInterval       time.Duration
+	WaitTimeout        time.Duration
+	ProvisioningBudget time.Duration
+	// MinBudget is the least time the context of a discovery must have left
+	// before a deadline for it to start, which defaults to PollInterval: with
+	// less, the probe ingress would be created only to time out right away.
+	MinBudget time.Duration
+	// CreateRetries is how many times the creation of the probe is retried
+	// after a transient API error.
+	CreateRetries int
+	// DialPort, when set, additionally requires the discovered addresses to
+	// accept TCP connections on that port before the probe is declared ready,
+	// for the controllers populating the status before the load balancer is
+	// provisioned.
+	DialPort int
+	// GRPCHealthCheck makes the dial check a gRPC health check of
+	// GRPCHealthService, over plaintext HTTP/2, for the probe backends serving
+	// gRPC. Each check is bounded by GRPCHealthTimeout.
+	GRPCHealthCheck   bool
+	GRPCHealthService string
+	GRPCHealthTimeout time.Duration
+	// AddressChangePolicy is what to do when the ingress controller address
+	// changed between the discovery and the persistence of the domain suffix.
+	AddressChangePolicy AddressChangePolicy
+	// AddressPreference is whether the IP or the hostname of the load balancer
+	// is preferred, and HostnameFallback allows using the IP when the preferred
+	// hostname fails to resolve.
+	AddressPreference AddressPreference
+	HostnameFallback  bool
+	// PreferHostname uses the hostname of the load balancer as is, rather than
+	// an IP it resolves to, for the load balancers whose IPs change, such as the
+	// AWS ELBs. It can't be combined with an AddressPreference other than
+	// AddressPreferenceHostname, nor with HostnameFallback.
+	PreferHostname bool
+	// ResolveTimeout bounds each resolution of a load balancer hostname, so
+	// that a DNS server that doesn't answer can't hang the discovery. Zero
+	// leaves it bounded by the context only.
+	ResolveTimeout time.Duration
+	// AddressAnnotation, if set, is the annotation of the probe ingress the ALB
+	// readiness checker reads the address from before the load balancer status.
+	AddressAnnotation string
+	// PersistentProbe keeps the probe ingress between discoveries, and updates
+	// it in place when its config changes.
+	PersistentProbe bool
+	// ReuseProbe reuses any probe ingress with the domain probe label, and
+	// leaves the one it creates when there is none for the next discoveries.
+	ReuseProbe bool
+	// ProbeIngress, if set, is the name of an existing ingress, e.g. provisioned
+	// through GitOps, that the discovery only reads and waits on, never creating
+	// nor deleting an ingress, for clusters where it may only get and list them.
+	ProbeIngress string
+	// ProbeHosts is the number of probe ingresses created with distinct hosts,
+	// for controllers sharded by host where some shards never assign an
+	// address. The first one to get an address is used.
+	ProbeHosts int
+	// ProbeIngressClasses, if set, are the ingress classes a probe ingress is
+	// created for each, instead of the one of the ingress config, for clusters
+	// running several ingress controllers. The first one to get an address is
+	// used.
+	ProbeIngressClasses []string
+	// MagicDNSTemplate, if set, is the domain suffix with a single %s the IP is
+	// interpolated into, e.g. `%s.nip.io`, instead of the magic DNS domain.
+	MagicDNSTemplate string
+	// MagicDNSIPFormat is how the IP is embedded into the magic DNS domain
+	// suffix, dotted by default.
+	MagicDNSIPFormat MagicDNSIPFormat
+	// ExternalNameService (`namespace/name` or `name`) is an ExternalName
+	// Service pointed at the discovered domain suffix.
+	ExternalNameService string
+	// PollImmediately checks the probe ingress status right away instead of
+	// after the first poll interval, so that an ingress that is already
+	// programmed, e.g. a reused persistent probe, is picked up without delay.
+	// It defaults to true.
+	PollImmediately bool
+	// Watch waits for the probe ingress with a watch rather than polls, falling
+	// back to the polls if the watch fails.
+	Watch bool
+	// ReverseLookup adds the reverse DNS names of the discovered IP to the
+	// discovery state and result.
+	ReverseLookup bool
+	// LBScheme and LBSubnets select the load balancer provisioned for the probe
+	// ingress, by the controllers that support it.
+	LBScheme  LoadBalancerScheme
+	LBSubnets []string
+	// AddressSelector picks among the addresses a load balancer hostname
+	// resolves to.
+	AddressSelector *AddressSelector
+	// ControllerCheck checks that the ingress controller workloads, found by
+	// ControllerSelector or by their upstream labels, have a ready replica.
+	ControllerCheck    bool
+	ControllerSelector string
+
+	// ignoredErrs are the errors of the tunables that fell back to their
+	// default, for the discovery to log.
+	ignoredErrs []error
+}
+
+func parseDiscoveryConfig(configMap *corev1.ConfigMap) (config *discoveryConfig, err error) {
+	config = &discoveryConfig{}
+
+	config.NetworkMode, err = parseNetworkMode(configMap)
+	if err != nil {
+		return
+	}
+
+	if externalIP := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigExternalIP]); externalIP != "" {
+		ip := net.ParseIP(externalIP)
+		if ip == nil {
+			err = errors.Errorf("invalid %s in configmap %s, not an IP: %s", consts.KubeConfigMapKeyNetworkConfigExternalIP, consts.KubeConfigMapNameNetworkConfig, externalIP)
+			return
+		}
+		config.ExternalIP = ip.String()
+	}
+
+	if gatewayClass := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigGatewayClass]); gatewayClass != "" {
+		config.GatewayClass = &gatewayClass
+	}
+	if config.NetworkMode == NetworkModeGateway && config.GatewayClass == nil {
+		err = errors.Errorf("%s is required in configmap %s when %s is %s", consts.KubeConfigMapKeyNetworkConfigGatewayClass, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigNetworkMode, NetworkModeGateway)
+		return
+	}
+
+	config.Preflight, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryPreflight, false)
+	if err != nil {
+		return
+	}
+
+	config.IngressClassCheck, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassCheck, true)
+	if err != nil {
+		return
+	}
+
+	config.IngressClassMode = IngressClassMode(strings.ToLower(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassMode])))
+	switch config.IngressClassMode {
+	case "":
+		config.IngressClassMode = IngressClassModeSpec
+	case IngressClassModeSpec, IngressClassModeAnnotation:
+	default:
+		err = errors.Errorf("invalid %s %q in configmap %s, must be %s or %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassMode, config.IngressClassMode, consts.KubeConfigMapNameNetworkConfig, IngressClassModeSpec, IngressClassModeAnnotation)
+		return
+	}
+
+	config.ProbeCatchAll, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeCatchAll, false)
+	if err != nil {
+		return
+	}
+
+	config.PostHookFatal, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryPostHookFatal, false)
+	if err != nil {
+		return
+	}
+
+	config.ReadyCondition = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryReadyCondition])
+
+	config.ProbeTTL, err = parseDurationKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeTTL, 0)
+	if err != nil {
+		return
+	}
+
+	config.StatusConfigMap = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryStatusConfigMap])
+
+	config.ControllerService = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressControllerService])
+	config.ControllerServiceSelector = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressControllerServiceSelector])
+
+	config.PinnedAddress = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryPinnedAddress])
+	if config.PinnedAddress != "" {
+		if err = validateAddress(config.PinnedAddress); err != nil {
+			err = errors.Wrapf(err, "invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryPinnedAddress, consts.KubeConfigMapNameNetworkConfig)
+			return
+		}
+	}
+
+	// Unparseable poll settings fall back to the defaults instead of blocking the
+	// discovery, an inconsistent pair is rejected below.
+	config.PollInterval = parseDurationKeyOrDefault(config, configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval, defaultDiscoveryTunables.PollInterval)
+	config.WaitTimeout = parseDurationKeyOrDefault(config, configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout, defaultDiscoveryTunables.WaitTimeout)
+	if config.PollInterval <= 0 {
+		err = errors.Errorf("%s in configmap %s must be positive", consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval, consts.KubeConfigMapNameNetworkConfig)
+		return
+	}
+	if config.PollInterval >= config.WaitTimeout {
+		err = errors.Errorf("%s (%s) in configmap %s must be less than %s (%s)", consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval, config.PollInterval, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout, config.WaitTimeout)
+		return
+	}
+
+	config.ProvisioningBudget, err = parseDurationKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryProvisioningBudget, defaultDiscoveryTunables.ProvisioningBudget)
+	if err != nil {
+		return
+	}
+
+	config.ServiceStatusFallback, err = parseDurationKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryServiceStatusFallback, 0)
+	if err != nil {
+		return
+	}
+
+	config.NodeAddressFallback, err = parseNodeAddressFallback(configMap)
+	if err != nil {
+		return
+	}
+
+	config.MinBudget, err = parseDurationKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryMinBudget, 0)
+	if err != nil {
+		return
+	}
+	if config.MinBudget == 0 {
+		config.MinBudget = config.PollInterval
+	}
+
+	config.AddressChangePolicy = AddressChangePolicy(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressChangePolicy]))
+	switch config.AddressChangePolicy {
+	case "":
+		config.AddressChangePolicy = AddressChangePolicyPersist
+	case AddressChangePolicyPersist, AddressChangePolicyReselect, AddressChangePolicyAbort:
+	default:
+		err = errors.Errorf("invalid %s in configmap %s: %s, expected one of %s, %s, %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressChangePolicy, consts.KubeConfigMapNameNetworkConfig, config.AddressChangePolicy, AddressChangePolicyPersist, AddressChangePolicyReselect, AddressChangePolicyAbort)
+		return
+	}
+
+	config.AddressPreference = AddressPreference(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference]))
+	switch config.AddressPreference {
+	case "":
+		config.AddressPreference = AddressPreferenceIP
+	case AddressPreferenceIP, AddressPreferenceHostname, AddressPreferenceAuto:
+	default:
+		err = errors.Errorf("invalid %s in configmap %s: %s, expected %s, %s or %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference, consts.KubeConfigMapNameNetworkConfig, config.AddressPreference, AddressPreferenceIP, AddressPreferenceHostname, AddressPreferenceAuto)
+		return
+	}
+
+	config.HostnameFallback, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryHostnameFallback, false)
+	if err != nil {
+		return
+	}
+
+	config.PreferHostname, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferHostname, false)
+	if err != nil {
+		return
+	}
+	if config.PreferHostname {
+		// The hostname is then never resolved, so there is neither an IP to
+		// prefer to it nor a failed resolution to fall back from.
+		if preference := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference]); preference != "" && AddressPreference(preference) != AddressPreferenceHostname {
+			err = errors.Errorf("%s in configmap %s can't be combined with %s %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferHostname, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressPreference, preference)
+			return
+		}
+		if config.HostnameFallback {
+			err = errors.Errorf("%s in configmap %s can't be combined with %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferHostname, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigDiscoveryHostnameFallback)
+			return
+		}
+	}
+
+	config.ResolveTimeout, err = parseDurationKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryResolveTimeout, defaultResolveTimeout)
+	if err != nil {
+		return
+	}
+
+	config.AddressAnnotation = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressAnnotation])
+	if errs := validation.IsQualifiedName(config.AddressAnnotation); config.AddressAnnotation != "" && len(errs) > 0 {
+		err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressAnnotation, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+		return
+	}
+
+	config.CreateRetries, err = parseIntKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryCreateRetries, defaultCreateRetries)
+	if err != nil {
+		return
+	}
+
+	config.DialPort, err = parseIntKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort, 0)
+	if err != nil {
+		return
+	}
+	if config.DialPort > 65535 {
+		err = errors.Errorf("%s in configmap %s is not a valid port: %d", consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort, consts.KubeConfigMapNameNetworkConfig, config.DialPort)
+		return
+	}
+
+	config.GRPCHealthCheck, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthCheck, false)
+	if err != nil {
+		return
+	}
+	if config.GRPCHealthCheck && config.DialPort == 0 {
+		err = errors.Errorf("%s is required in configmap %s when %s is set", consts.KubeConfigMapKeyNetworkConfigDiscoveryDialPort, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthCheck)
+		return
+	}
+	config.GRPCHealthService = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthService])
+	config.GRPCHealthTimeout, err = parseDurationKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryGRPCHealthTimeout, defaultGRPCHealthTimeout)
+	if err != nil {
+		return
+	}
+
+	config.PersistentProbe, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe, false)
+	if err != nil {
+		return
+	}
+
+	config.ReuseProbe, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe, false)
+	if err != nil {
+		return
+	}
+
+	config.ProbeIngress = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress])
+	if config.ProbeIngress != "" {
+		if errs := validation.IsDNS1123Subdomain(config.ProbeIngress); len(errs) > 0 {
+			err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+			return
+		}
+		if config.PersistentProbe || config.ReuseProbe {
+			err = errors.Errorf("%s in configmap %s can't be combined with %s nor %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe, consts.KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe)
+			return
+		}
+	}
+
+	config.ProbeHosts, err = parseIntKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts, 1)
+	if err != nil {
+		return
+	}
+	if config.ProbeHosts == 0 {
+		config.ProbeHosts = 1
+	}
+	if config.ProbeHosts > maxProbeHosts {
+		err = errors.Errorf("%s in configmap %s must be at most %d: %d", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts, consts.KubeConfigMapNameNetworkConfig, maxProbeHosts, config.ProbeHosts)
+		return
+	}
+	if config.ProbeHosts > 1 && (config.PersistentProbe || config.ReuseProbe || config.ProbeIngress != "" || config.ProbeCatchAll || config.ReadyCondition != "") {
+		err = errors.Errorf("%s in configmap %s can't be combined with %s, %s, %s, %s nor %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe, consts.KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeCatchAll, consts.KubeConfigMapKeyNetworkConfigDiscoveryReadyCondition)
+		return
+	}
+
+	config.ProbeIngressClasses, err = parseProbeIngressClasses(configMap)
+	if err != nil {
+		return
+	}
+	if len(config.ProbeIngressClasses) > 0 && (config.ProbeHosts > 1 || config.PersistentProbe || config.ReuseProbe || config.ProbeIngress != "" || config.ReadyCondition != "") {
+		err = errors.Errorf("%s in configmap %s can't be combined with %s, %s, %s, %s nor %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses, consts.KubeConfigMapNameNetworkConfig, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts, consts.KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe, consts.KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress, consts.KubeConfigMapKeyNetworkConfigDiscoveryReadyCondition)
+		return
+	}
+
+	config.ProbeHostSuffix = strings.TrimSuffix(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSuffix]), ".")
+	if config.ProbeHostSuffix == "" {
+		config.ProbeHostSuffix = DefaultProbeHostSuffix
+	} else if errs := validation.IsDNS1123Subdomain(config.ProbeHostSuffix); len(errs) > 0 {
+		err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSuffix, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+		return
+	}
+
+	config.ProbeHostSeed = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSeed])
+
+	config.ProbeNamespace = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace])
+	if errs := validation.IsDNS1123Label(config.ProbeNamespace); config.ProbeNamespace != "" && len(errs) > 0 {
+		err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+		return
+	}
+
+	config.ProbeBackendService = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendService])
+	if config.ProbeBackendService == "" {
+		config.ProbeBackendService = DefaultProbeBackendService
+	} else if errs := validation.IsDNS1035Label(config.ProbeBackendService); len(errs) > 0 {
+		err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendService, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+		return
+	}
+
+	config.ProbeBackendPortName = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName])
+	if config.ProbeBackendPortName != "" {
+		if strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort]) != "" {
+			err = errors.Errorf("only one of %s and %s can be set in configmap %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName, consts.KubeConfigMapNameNetworkConfig)
+			return
+		}
+		if errs := validation.IsValidPortName(config.ProbeBackendPortName); len(errs) > 0 {
+			err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+			return
+		}
+	}
+
+	probeBackendPort, err := parseIntKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort, consts.BentoServicePort)
+	if err != nil {
+		return
+	}
+	if errs := validation.IsValidPortNum(probeBackendPort); len(errs) > 0 {
+		err = errors.Errorf("invalid %s in configmap %s: %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+		return
+	}
+	config.ProbeBackendPort = int32(probeBackendPort)
+
+	config.ProbeBackendPortLookup, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortLookup, false)
+	if err != nil {
+		return
+	}
+
+	config.MagicDNSTemplate, err = parseMagicDNSTemplate(configMap)
+	if err != nil {
+		return
+	}
+
+	config.MagicDNSIPFormat = MagicDNSIPFormat(strings.ToLower(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigMagicDNSIPFormat])))
+	switch config.MagicDNSIPFormat {
+	case "":
+		config.MagicDNSIPFormat = MagicDNSIPFormatDotted
+	case MagicDNSIPFormatDotted, MagicDNSIPFormatDashed:
+	default:
+		err = errors.Errorf("invalid %s %q in configmap %s, must be %s or %s", consts.KubeConfigMapKeyNetworkConfigMagicDNSIPFormat, config.MagicDNSIPFormat, consts.KubeConfigMapNameNetworkConfig, MagicDNSIPFormatDotted, MagicDNSIPFormatDashed)
+		return
+	}
+
+	config.ExternalNameService = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryExternalNameService])
+
+	config.PollImmediately, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately, true)
+	if err != nil {
+		return
+	}
+
+	config.Watch, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryWatch, false)
+	if err != nil {
+		return
+	}
+
+	config.ReverseLookup, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryReverseLookup, false)
+	if err != nil {
+		return
+	}
+
+	config.LBScheme, err = parseLoadBalancerScheme(configMap.Data[consts.KubeConfigMapKeyNetworkConfigLBScheme])
+	if err != nil {
+		return
+	}
+
+	for _, subnet := range strings.Split(configMap.Data[consts.KubeConfigMapKeyNetworkConfigLBSubnets], ",") {
+		if subnet = strings.TrimSpace(subnet); subnet != "" {
+			config.LBSubnets = append(config.LBSubnets, subnet)
+		}
+	}
+
+	config.AddressSelector, err = parseAddressSelector(configMap)
+	if err != nil {
+		return
+	}
+
+	config.ControllerCheck, err = parseBoolKey(configMap, consts.KubeConfigMapKeyNetworkConfigDiscoveryControllerCheck, false)
+	if err != nil {
+		return
+	}
+	config.ControllerSelector = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryControllerSelector])
+
+	return
+}
+
+func parseBoolKey(configMap *corev1.ConfigMap, key string, defaultValue bool) (bool, error) {
+	value := strings.TrimSpace(configMap.Data[key])
+	if value == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s in configmap %s as a boolean: %s", key, consts.KubeConfigMapNameNetworkConfig, value)
+	}
+	return b, nil
+}
+
+func parseIntKey(configMap *corev1.ConfigMap, key string, defaultValue int) (int, error) {
+	value := strings.TrimSpace(configMap.Data[key])
+	if value == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse %s in configmap %s as an integer: %s", key, consts.KubeConfigMapNameNetworkConfig, value)
+	}
+	if i < 0 {
+		return 0, errors.Errorf("%s in configmap %s must not be negative: %s", key, consts.KubeConfigMapNameNetworkConfig, value)
+	}
+	return i, nil
+}
+
+func parseDurationKey(configMap *corev1.ConfigMap, key string, defaultValue time.Duration) (time.Duration, error) {
+	value := strings.TrimSpace(configMap.Data[key])
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse %s in configmap %s as a duration: %s", key, consts.KubeConfigMapNameNetworkConfig, value)
+	}
+	if d < 0 {
+		return 0, errors.Errorf("%s in configmap %s must not be negative: %s", key, consts.KubeConfigMapNameNetworkConfig, value)
+	}
+	return d, nil
+}
+
+func parseMagicDNSTemplate(configMap *corev1.ConfigMap) (string, error) {
+	template := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate])
+	if template == "" {
+		return "", nil
+	}
+	if err := validateMagicDNSTemplate(template); err != nil {
+		return "", errors.Wrapf(err, "invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate, consts.KubeConfigMapNameNetworkConfig)
+	}
+	return template, nil
+}
+
+// parseProbeIngressClasses parses the comma-separated ingress classes of the
+// discovery-probe-ingress-classes key.
+func parseProbeIngressClasses(configMap *corev1.ConfigMap) ([]string, error) {
+	value := strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses])
+	if value == "" {
+		return nil, nil
+	}
+	var classes []string
+	seen := map[string]bool{}
+	for _, class := range strings.Split(value, ",") {
+		class = strings.TrimSpace(class)
+		if errs := validation.IsDNS1123Subdomain(class); len(errs) > 0 {
+			return nil, errors.Errorf("invalid ingress class %q in %s of configmap %s: %s", class, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses, consts.KubeConfigMapNameNetworkConfig, strings.Join(errs, ", "))
+		}
+		if seen[class] {
+			return nil, errors.Errorf("the ingress class %s is repeated in %s of configmap %s", class, consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngressClasses, consts.KubeConfigMapNameNetworkConfig)
+		}
+		seen[class] = true
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// parseDurationKeyOrDefault is like parseDurationKey, but returns the default
+// value when the key can't be parsed, adding the error to the ignored ones of the
+// config.
+func parseDurationKeyOrDefault(config *discoveryConfig, configMap *corev1.ConfigMap, key string, defaultValue time.Duration) time.Duration {
+	d, err := parseDurationKey(configMap, key, defaultValue)
+	if err != nil {
+		config.ignoredErrs = append(config.ignoredErrs, errors.Wrapf(err, "using the default %s of %s", key, defaultValue))
+		return defaultValue
+	}
+	return d
+}
+
+// PersistDomainSuffix writes the domain suffix to the network configmap, or to the
+// status configmap when the network configmap is immutable. The configmap is only
+// updated if it has no domain suffix yet, unless overwrite is set, and at the
+// resource version it was read at, so that concurrent discoveries don't
+// overwrite each other: the domain suffix persisted first is returned, and
+// whether the configmap was changed. Writing the domain suffix the configmap
+// already has is a no-op.
+func PersistDomainSuffix(ctx context.Context, cliset kubernetes.Interface, configMap *corev1.ConfigMap, statusConfigMapName, domainSuffix string, overwrite bool) (persisted string, changed bool, err error) {
+	configMapCli := cliset.CoreV1().ConfigMaps(configMap.Namespace)
+
+	if configMap.Immutable == nil || !*configMap.Immutable {
+		// A network configmap merged from the one of the system namespace
+		// may not exist yet in its namespace.
+		persisted, changed, err = updateDomainSuffix(ctx, configMapCli, configMap.Namespace, configMap.Name, domainSuffix, configMap.CreationTimestamp.IsZero(), overwrite)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to update configmap %s", consts.KubeConfigMapNameNetworkConfig)
+		}
+		return
+	}
+
+	if statusConfigMapName == "" {
+		err = errors.Wrapf(ErrNetworkConfigImmutable, "failed to persist the domain suffix %s, set %s to persist it to a separate configmap", domainSuffix, consts.KubeConfigMapKeyNetworkConfigDiscoveryStatusConfigMap)
+		return
+	}
+
+	contextLogger(ctx).Info("The network configmap is immutable, so the domain suffix is persisted to the status configmap instead", "configmap", configMap.Name, "statusConfigMap", statusConfigMapName)
+
+	persisted, changed, err = updateDomainSuffix(ctx, configMapCli, configMap.Namespace, statusConfigMapName, domainSuffix, true, overwrite)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to persist the domain suffix to configmap %s", statusConfigMapName)
+	}
+	return
+}
+
+// updateDomainSuffix sets the domain suffix of the configmap name, retrying on the
+// conflicts with concurrent writers, and returns the domain suffix of the
+// configmap and whether it changed. Unless overwrite is set, a domain suffix the
+// configmap already has is kept. A missing configmap is created in the namespace
+// if create is set.
+func updateDomainSuffix(ctx context.Context, configMapCli corev1client.ConfigMapInterface, namespace, name, domainSuffix string, create, overwrite bool) (persisted string, changed bool, err error) {
+	err = retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err)
+	}, func() error {
+		changed = false
+		current, err := configMapCli.Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) && create {
+			_, err = configMapCli.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Data: map[string]string{
+					consts.KubeConfigMapKeyNetworkConfigDomainSuffix: domainSuffix,
+				},
+			}, metav1.CreateOptions{})
+			persisted, changed = domainSuffix, err == nil
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		existing := strings.TrimSpace(current.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix])
+		if existing == domainSuffix || (existing != "" && !overwrite) {
+			persisted = existing
+			return nil
+		}
+
+		current = current.DeepCopy()
+		if current.Data == nil {
+			current.Data = make(map[string]string, 1)
+		}
+		current.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix] = domainSuffix
+		_, err = configMapCli.Update(ctx, current, metav1.UpdateOptions{})
+		persisted, changed = domainSuffix, err == nil
+		return err
+	})
+	return
+}
+
+// getStatusDomainSuffix returns the domain suffix persisted to the status
+// configmap, or an empty string if there is none yet.
+func getStatusDomainSuffix(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), namespace, name string) (string, error) {
+	configMap, err := configmapGetter(ctx, namespace, name)
+	if k8serrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get configmap %s", name)
+	}
+	return strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]), nil
+}
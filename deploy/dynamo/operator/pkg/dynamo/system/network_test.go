@@ -0,0 +1,1004 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestParseDiscoveryConfigPolling(t *testing.T) {
+	tests := []struct {
+		name             string
+		interval         string
+		timeout          string
+		wantPollInterval time.Duration
+		wantWaitTimeout  time.Duration
+		wantErr          bool
+	}{
+		{
+			name:             "defaults",
+			wantPollInterval: defaultDiscoveryTunables.PollInterval,
+			wantWaitTimeout:  defaultDiscoveryTunables.WaitTimeout,
+		},
+		{
+			name:             "configured",
+			interval:         "2s",
+			timeout:          "1m",
+			wantPollInterval: 2 * time.Second,
+			wantWaitTimeout:  time.Minute,
+		},
+		{
+			name:             "unparseable values fall back to the defaults",
+			interval:         "often",
+			timeout:          "-1m",
+			wantPollInterval: defaultDiscoveryTunables.PollInterval,
+			wantWaitTimeout:  defaultDiscoveryTunables.WaitTimeout,
+		},
+		{
+			name:     "zero interval",
+			interval: "0s",
+			wantErr:  true,
+		},
+		{
+			name:     "interval not less than the timeout",
+			interval: "1m",
+			timeout:  "1m",
+			wantErr:  true,
+		},
+		{
+			name:    "timeout below the default interval",
+			timeout: "5s",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: tt.interval,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:  tt.timeout,
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDiscoveryConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if config.PollInterval != tt.wantPollInterval {
+				t.Errorf("PollInterval = %s, want %s", config.PollInterval, tt.wantPollInterval)
+			}
+			if config.WaitTimeout != tt.wantWaitTimeout {
+				t.Errorf("WaitTimeout = %s, want %s", config.WaitTimeout, tt.wantWaitTimeout)
+			}
+		})
+	}
+}
+
+// countingConfigMapGetter returns the configmap and counts the calls.
+func countingConfigMapGetter(configMap *corev1.ConfigMap, calls *int) func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		*calls++
+		return configMap, nil
+	}
+}
+
+func TestSelectNetworkConfigConfigMap(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newConfigMap := func(name string, age time.Duration, priority string) corev1.ConfigMap {
+		configMap := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created.Add(-age)),
+		}}
+		if priority != "" {
+			configMap.Annotations = map[string]string{consts.KubeAnnotationDynamoConfigPriority: priority}
+		}
+		return configMap
+	}
+
+	tests := []struct {
+		name       string
+		configMaps []corev1.ConfigMap
+		want       string
+		wantErr    error
+	}{
+		{
+			name:       "highest priority",
+			configMaps: []corev1.ConfigMap{newConfigMap("low", 0, "1"), newConfigMap("high", time.Hour, "10"), newConfigMap("unannotated", 0, "")},
+			want:       "high",
+		},
+		{
+			name:       "negative priority",
+			configMaps: []corev1.ConfigMap{newConfigMap("negative", 0, "-1"), newConfigMap("unannotated", time.Hour, "")},
+			want:       "unannotated",
+		},
+		{
+			name:       "newest wins",
+			configMaps: []corev1.ConfigMap{newConfigMap("old", time.Hour, ""), newConfigMap("new", 0, ""), newConfigMap("older", 2*time.Hour, "")},
+			want:       "new",
+		},
+		{
+			name:       "exact tie",
+			configMaps: []corev1.ConfigMap{newConfigMap("a", 0, "5"), newConfigMap("b", 0, "5")},
+			wantErr:    ErrAmbiguousNetworkConfig,
+		},
+		{
+			name:       "tie broken by a higher priority",
+			configMaps: []corev1.ConfigMap{newConfigMap("a", 0, ""), newConfigMap("b", 0, ""), newConfigMap("c", time.Hour, "1")},
+			want:       "c",
+		},
+		{
+			name:       "invalid annotation",
+			configMaps: []corev1.ConfigMap{newConfigMap("a", 0, ""), newConfigMap("b", 0, "high")},
+			wantErr:    errors.New("invalid"),
+		},
+		{
+			name:    "none",
+			wantErr: errors.New("no network configmap"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap, err := SelectNetworkConfigConfigMap(tt.configMaps)
+			if tt.wantErr != nil {
+				if err == nil || !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Errorf("SelectNetworkConfigConfigMap() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectNetworkConfigConfigMap() error = %v", err)
+			}
+			if configMap.Name != tt.want {
+				t.Errorf("SelectNetworkConfigConfigMap() = %s, want %s", configMap.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNetworkConfig(t *testing.T) {
+	var calls int
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:     "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix:     "apps.example.com",
+		consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate: "%s.nip.io",
+		consts.KubeConfigMapKeyNetworkConfigIngressTLS:       `[{"secretName": "wildcard-tls"}]`,
+	})
+
+	networkConfig, err := GetNetworkConfig(context.Background(), countingConfigMapGetter(configMap, &calls))
+	if err != nil {
+		t.Fatalf("GetNetworkConfig() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("the configmap was fetched %d times, want 1", calls)
+	}
+	if networkConfig.Ingress.ClassName == nil || *networkConfig.Ingress.ClassName != "nginx" {
+		t.Errorf("Ingress.ClassName = %v, want nginx", networkConfig.Ingress.ClassName)
+	}
+	if networkConfig.DomainSuffix != "apps.example.com" {
+		t.Errorf("DomainSuffix = %q, want %q", networkConfig.DomainSuffix, "apps.example.com")
+	}
+	if networkConfig.MagicDNS != GetMagicDNS() || networkConfig.MagicDNSTemplate != "%s.nip.io" {
+		t.Errorf("MagicDNS = %q, MagicDNSTemplate = %q", networkConfig.MagicDNS, networkConfig.MagicDNSTemplate)
+	}
+	if len(networkConfig.TLS) != 1 || networkConfig.TLS[0].SecretName != "wildcard-tls" {
+		t.Errorf("TLS = %v, want the wildcard-tls secret", networkConfig.TLS)
+	}
+}
+
+func newNetworkSecret(data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      consts.KubeConfigMapNameNetworkConfig,
+			Namespace: GetNamespace(),
+		},
+		Data: make(map[string][]byte, len(data)),
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+// staticSecretGetter returns a secret getter returning secret, or a not found error
+// when it's nil.
+func staticSecretGetter(secret *corev1.Secret) func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	return func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+		if secret == nil {
+			return nil, k8serrors.NewNotFound(corev1.Resource("secrets"), name)
+		}
+		return secret, nil
+	}
+}
+
+func TestGetNetworkConfigWithSecret(t *testing.T) {
+	notFoundConfigMapGetter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return nil, k8serrors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+
+	tests := []struct {
+		name             string
+		configmapGetter  func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+		secret           *corev1.Secret
+		wantClassName    string
+		wantDomainSuffix string
+		wantTLSSecret    string
+		wantErr          bool
+	}{
+		{
+			name: "secret overlays the sensitive keys only",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "apps.example.com",
+				consts.KubeConfigMapKeyNetworkConfigIngressTLS:   `[{"secretName": "configmap-tls"}]`,
+			})),
+			secret: newNetworkSecret(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "secret.example.com",
+				consts.KubeConfigMapKeyNetworkConfigIngressTLS:   `[{"secretName": "secret-tls"}]`,
+			}),
+			wantClassName:    "nginx",
+			wantDomainSuffix: "apps.example.com",
+			wantTLSSecret:    "secret-tls",
+		},
+		{
+			name: "secret fills in the missing keys",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+			})),
+			secret: newNetworkSecret(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "secret.example.com",
+			}),
+			wantClassName:    "nginx",
+			wantDomainSuffix: "secret.example.com",
+		},
+		{
+			name: "secret absent",
+			configmapGetter: staticConfigMapGetter(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+				consts.KubeConfigMapKeyNetworkConfigIngressTLS:   `[{"secretName": "configmap-tls"}]`,
+			})),
+			wantClassName: "nginx",
+			wantTLSSecret: "configmap-tls",
+		},
+		{
+			name:            "configmap absent",
+			configmapGetter: notFoundConfigMapGetter,
+			secret: newNetworkSecret(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+			}),
+			wantClassName: "nginx",
+		},
+		{
+			name:            "both absent",
+			configmapGetter: notFoundConfigMapGetter,
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networkConfig, err := GetNetworkConfigWithSecret(context.Background(), tt.configmapGetter, staticSecretGetter(tt.secret))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNetworkConfigWithSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if networkConfig.Ingress.ClassName == nil || *networkConfig.Ingress.ClassName != tt.wantClassName {
+				t.Errorf("Ingress.ClassName = %v, want %s", networkConfig.Ingress.ClassName, tt.wantClassName)
+			}
+			if networkConfig.DomainSuffix != tt.wantDomainSuffix {
+				t.Errorf("DomainSuffix = %q, want %q", networkConfig.DomainSuffix, tt.wantDomainSuffix)
+			}
+			var tlsSecret string
+			if len(networkConfig.TLS) > 0 {
+				tlsSecret = networkConfig.TLS[0].SecretName
+			}
+			if tlsSecret != tt.wantTLSSecret {
+				t.Errorf("TLS secret = %q, want %q", tlsSecret, tt.wantTLSSecret)
+			}
+		})
+	}
+}
+
+func TestGetNetworkConfigWithSecretName(t *testing.T) {
+	var configMapName, secretName string
+	configmapGetter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		configMapName = name
+		return nil, k8serrors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+	secretGetter := func(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+		secretName = name
+		return nil, k8serrors.NewNotFound(corev1.Resource("secrets"), name)
+	}
+
+	ctx := WithNetworkConfigName(context.Background(), "network-b")
+	_, err := GetNetworkConfigWithSecret(ctx, configmapGetter, secretGetter)
+	if err == nil {
+		t.Fatal("GetNetworkConfigWithSecret() succeeded without a configmap nor a secret, want an error")
+	}
+	if configMapName != "network-b" || secretName != "network-b" {
+		t.Errorf("GetNetworkConfigWithSecret() got the configmap %q and the secret %q, want both of network-b", configMapName, secretName)
+	}
+	if !strings.Contains(err.Error(), "network-b") {
+		t.Errorf("GetNetworkConfigWithSecret() error = %v, want it to name network-b", err)
+	}
+}
+
+func TestGetDomainSuffixFetchesConfigMapOnce(t *testing.T) {
+	var calls int
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	if _, err := GetDomainSuffix(context.Background(), countingConfigMapGetter(configMap, &calls), cliset); err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("the configmap was fetched %d times, want 1", calls)
+	}
+}
+
+// enforceResourceVersion makes the fake clientset reject the configmap updates
+// that aren't at the current resource version, like the API server does. The
+// first update is preceded by a concurrent write of the concurrent domain suffix.
+func enforceResourceVersion(t *testing.T, cliset *fake.Clientset, concurrent string) {
+	concurrentWritten := false
+	cliset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update := action.(k8stesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+		gvr := corev1.SchemeGroupVersion.WithResource("configmaps")
+		obj, err := cliset.Tracker().Get(gvr, update.Namespace, update.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		current := obj.(*corev1.ConfigMap).DeepCopy()
+
+		if !concurrentWritten {
+			concurrentWritten = true
+			current.Data = map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: concurrent}
+			current.ResourceVersion = "2"
+			if err := cliset.Tracker().Update(gvr, current, current.Namespace); err != nil {
+				t.Fatalf("failed to write the concurrent domain suffix: %v", err)
+			}
+		}
+
+		if update.ResourceVersion != current.ResourceVersion {
+			return true, nil, k8serrors.NewConflict(gvr.GroupResource(), update.Name, errors.New("the object has been modified"))
+		}
+		return false, nil, nil
+	})
+}
+
+func TestGetDomainSuffixConcurrentWrite(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+	configMap.ResourceVersion = "1"
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+	concurrent := ComposeMagicDNSSuffix("10.0.0.2", GetMagicDNS())
+	enforceResourceVersion(t, cliset, concurrent)
+
+	domainSuffix, err := GetDomainSuffix(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+	if domainSuffix != concurrent {
+		t.Errorf("GetDomainSuffix() = %q, want the concurrently persisted %q", domainSuffix, concurrent)
+	}
+
+	persisted, err := cliset.CoreV1().ConfigMaps(configMap.Namespace).Get(context.Background(), configMap.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the network configmap: %v", err)
+	}
+	if got := persisted.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; got != concurrent {
+		t.Errorf("persisted %s = %q, want %q", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, got, concurrent)
+	}
+}
+
+// namespacedConfigMapGetter returns a configmap getter returning the configmap of
+// the namespace, or a not found error.
+func namespacedConfigMapGetter(configMaps ...*corev1.ConfigMap) func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		for _, configMap := range configMaps {
+			if configMap.Namespace == namespace && configMap.Name == name {
+				return configMap, nil
+			}
+		}
+		return nil, k8serrors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+}
+
+func TestGetNetworkConfigConfigMapNamespaceOverride(t *testing.T) {
+	global := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:       "nginx",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"example.com/global": "true"}`,
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix:       "10.0.0.1.sslip.io",
+	})
+	local := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass: "tenant-nginx",
+	})
+	local.Namespace = "tenant"
+
+	tests := []struct {
+		name      string
+		namespace string
+		getter    func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "system namespace",
+			namespace: GetNamespace(),
+			getter:    namespacedConfigMapGetter(global, local),
+			want:      global.Data,
+		},
+		{
+			name:      "local values win",
+			namespace: "tenant",
+			getter:    namespacedConfigMapGetter(global, local),
+			want: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:       "tenant-nginx",
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"example.com/global": "true"}`,
+			},
+		},
+		{
+			name:      "local absent",
+			namespace: "tenant",
+			getter:    namespacedConfigMapGetter(global),
+			want: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:       "nginx",
+				consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"example.com/global": "true"}`,
+			},
+		},
+		{
+			name:      "global absent",
+			namespace: "tenant",
+			getter:    namespacedConfigMapGetter(local),
+			want:      local.Data,
+		},
+		{
+			name:      "both absent",
+			namespace: "tenant",
+			getter:    namespacedConfigMapGetter(),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap, err := GetNetworkConfigConfigMap(WithNamespace(context.Background(), tt.namespace), tt.getter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNetworkConfigConfigMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if configMap.Namespace != tt.namespace {
+				t.Errorf("GetNetworkConfigConfigMap() namespace = %q, want %q", configMap.Namespace, tt.namespace)
+			}
+			if !reflect.DeepEqual(configMap.Data, tt.want) {
+				t.Errorf("GetNetworkConfigConfigMap() data = %v, want %v", configMap.Data, tt.want)
+			}
+		})
+	}
+
+	if local.Data[consts.KubeConfigMapKeyNetworkConfigIngressAnnotations] != "" {
+		t.Error("the local configmap was modified by the merge")
+	}
+}
+
+func TestGetNetworkConfigConfigMapTenantKeys(t *testing.T) {
+	global := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+	})
+	local := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:                  "tenant-nginx",
+		consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation + "a": "b",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace:       "kube-system",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryExternalNameService:  "kube-system/victim",
+	})
+	local.Namespace = "tenant"
+
+	for name, getter := range map[string]func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error){
+		"merged":        namespacedConfigMapGetter(global, local),
+		"global absent": namespacedConfigMapGetter(local),
+	} {
+		t.Run(name, func(t *testing.T) {
+			configMap, err := GetNetworkConfigConfigMap(WithNamespace(context.Background(), "tenant"), getter)
+			if err != nil {
+				t.Fatalf("GetNetworkConfigConfigMap() error = %v", err)
+			}
+			want := map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:                  "tenant-nginx",
+				consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation + "a": "b",
+			}
+			if !reflect.DeepEqual(configMap.Data, want) {
+				t.Errorf("GetNetworkConfigConfigMap() data = %v, want %v", configMap.Data, want)
+			}
+		})
+	}
+	if local.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeNamespace] == "" {
+		t.Error("the local configmap was modified by the filter")
+	}
+}
+
+func TestGetIngressConfigMissingNetworkConfig(t *testing.T) {
+	getter := namespacedConfigMapGetter()
+
+	_, err := GetIngressConfig(context.Background(), getter)
+	if !errors.Is(err, ErrNetworkConfigNotFound) {
+		t.Errorf("GetIngressConfig() error = %v, want %v", err, ErrNetworkConfigNotFound)
+	}
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("GetIngressConfig() error = %v, want a not found error", err)
+	}
+
+	otherErr := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return nil, k8serrors.NewForbidden(corev1.Resource("configmaps"), name, errors.New("denied"))
+	}
+	if _, err := GetIngressConfig(context.Background(), otherErr); errors.Is(err, ErrNetworkConfigNotFound) {
+		t.Errorf("GetIngressConfig() error = %v, want it not to be %v", err, ErrNetworkConfigNotFound)
+	}
+
+	ingressConfig, err := GetIngressConfig(WithAllowMissingNetworkConfig(context.Background()), getter)
+	if err != nil {
+		t.Fatalf("GetIngressConfig() with a missing network config allowed error = %v", err)
+	}
+	if ingressConfig.ClassName != nil || ingressConfig.Path != "/" || ingressConfig.PathType != networkingv1.PathTypeImplementationSpecific || ingressConfig.PathTypeExplicit {
+		t.Errorf("GetIngressConfig() with a missing network config allowed = %+v, want the defaults", ingressConfig)
+	}
+}
+
+func TestPersistDomainSuffix(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    string
+		suffix      string
+		wantChanged bool
+	}{
+		{name: "equal", existing: "10.0.0.1.sslip.io", suffix: "10.0.0.1.sslip.io"},
+		{name: "different", existing: "10.0.0.1.sslip.io", suffix: "10.0.0.2.sslip.io", wantChanged: true},
+		{name: "unset", suffix: "10.0.0.2.sslip.io", wantChanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]string{}
+			if tt.existing != "" {
+				data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix] = tt.existing
+			}
+			configMap := newNetworkConfigMap(data)
+			cliset := fake.NewSimpleClientset(configMap)
+
+			_, changed, err := PersistDomainSuffix(context.Background(), cliset, configMap, "", tt.suffix, true)
+			if err != nil {
+				t.Fatalf("PersistDomainSuffix() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("PersistDomainSuffix() changed = %t, want %t", changed, tt.wantChanged)
+			}
+
+			updates := 0
+			for _, action := range cliset.Actions() {
+				if action.GetVerb() == "update" || action.GetVerb() == "patch" {
+					updates++
+				}
+			}
+			if !tt.wantChanged && updates > 0 {
+				t.Errorf("PersistDomainSuffix() wrote the configmap %d times, want none", updates)
+			}
+
+			persisted, err := cliset.CoreV1().ConfigMaps(configMap.Namespace).Get(context.Background(), configMap.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get the network configmap: %v", err)
+			}
+			if got := persisted.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; got != tt.suffix {
+				t.Errorf("persisted %s = %q, want %q", consts.KubeConfigMapKeyNetworkConfigDomainSuffix, got, tt.suffix)
+			}
+		})
+	}
+}
+
+func TestGetDomainSuffixImmutableNetworkConfig(t *testing.T) {
+	discovery := map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	}
+	want := ComposeMagicDNSSuffix("10.0.0.1", GetMagicDNS())
+
+	t.Run("without a status configmap", func(t *testing.T) {
+		cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+		addNetworkConfigMap(t, cliset, GetNamespace(), discovery, true)
+
+		_, err := GetDomainSuffix(context.Background(), clientsetConfigMapGetter(cliset), cliset)
+		if !errors.Is(err, ErrNetworkConfigImmutable) {
+			t.Errorf("GetDomainSuffix() error = %v, want ErrNetworkConfigImmutable", err)
+		}
+	})
+
+	t.Run("with a status configmap", func(t *testing.T) {
+		data := map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryStatusConfigMap: "discovery-status"}
+		for key, value := range discovery {
+			data[key] = value
+		}
+		cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+		addNetworkConfigMap(t, cliset, GetNamespace(), data, true)
+		configmapGetter := clientsetConfigMapGetter(cliset)
+
+		domainSuffix, err := GetDomainSuffix(context.Background(), configmapGetter, cliset)
+		if err != nil {
+			t.Fatalf("GetDomainSuffix() error = %v", err)
+		}
+		if domainSuffix != want {
+			t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, want)
+		}
+		persisted, err := getStatusDomainSuffix(context.Background(), configmapGetter, GetNamespace(), "discovery-status")
+		if err != nil {
+			t.Fatalf("getStatusDomainSuffix() error = %v", err)
+		}
+		if persisted != want {
+			t.Errorf("getStatusDomainSuffix() = %q, want the discovered %q", persisted, want)
+		}
+		networkConfig, err := configmapGetter(context.Background(), GetNamespace(), consts.KubeConfigMapNameNetworkConfig)
+		if err != nil {
+			t.Fatalf("failed to get the network configmap: %v", err)
+		}
+		if got, ok := networkConfig.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; ok {
+			t.Errorf("the immutable network configmap got the domain suffix %q", got)
+		}
+
+		// The next discovery reads the domain suffix of the status configmap
+		// rather than probing again.
+		cliset.ClearActions()
+		if domainSuffix, err = GetDomainSuffix(context.Background(), configmapGetter, cliset); err != nil || domainSuffix != want {
+			t.Errorf("GetDomainSuffix() again = %q, %v, want %q", domainSuffix, err, want)
+		}
+		for _, action := range cliset.Actions() {
+			if action.GetVerb() == "create" {
+				t.Errorf("GetDomainSuffix() again created a %s, want the status configmap read", action.GetResource().Resource)
+			}
+		}
+	})
+}
+
+func TestGetStatusDomainSuffixMissing(t *testing.T) {
+	domainSuffix, err := getStatusDomainSuffix(context.Background(), clientsetConfigMapGetter(fake.NewSimpleClientset()), GetNamespace(), "discovery-status")
+	if err != nil || domainSuffix != "" {
+		t.Errorf("getStatusDomainSuffix() of a missing configmap = %q, %v, want no domain suffix", domainSuffix, err)
+	}
+}
+
+func TestDumpNetworkConfig(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:       "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix:       "example.com",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"nginx.ingress.kubernetes.io/auth-secret": "basic-auth", "nginx.ingress.kubernetes.io/ssl-redirect": "false"}`,
+	})
+
+	out, err := DumpNetworkConfig(context.Background(), staticConfigMapGetter(configMap))
+	if err != nil {
+		t.Fatalf("DumpNetworkConfig() error = %v", err)
+	}
+
+	var dump struct {
+		Namespace    string
+		DomainSuffix string
+		MagicDNS     string
+		Ingress      struct {
+			ClassName   *string
+			Annotations map[string]string
+		}
+	}
+	if err := json.Unmarshal(out, &dump); err != nil {
+		t.Fatalf("DumpNetworkConfig() = %s, not JSON: %v", out, err)
+	}
+	if dump.Namespace != configMap.Namespace || dump.DomainSuffix != "example.com" || dump.MagicDNS == "" {
+		t.Errorf("DumpNetworkConfig() = %s, want the namespace, domain suffix and magic DNS", out)
+	}
+	if dump.Ingress.ClassName == nil || *dump.Ingress.ClassName != "nginx" {
+		t.Errorf("DumpNetworkConfig() ingress class = %v, want nginx", dump.Ingress.ClassName)
+	}
+	want := map[string]string{
+		"nginx.ingress.kubernetes.io/auth-secret":  "REDACTED",
+		"nginx.ingress.kubernetes.io/ssl-redirect": "false",
+	}
+	if !reflect.DeepEqual(dump.Ingress.Annotations, want) {
+		t.Errorf("DumpNetworkConfig() annotations = %v, want %v", dump.Ingress.Annotations, want)
+	}
+	if strings.Contains(string(out), "basic-auth") {
+		t.Errorf("DumpNetworkConfig() = %s, leaks the redacted value", out)
+	}
+}
+
+func TestParseNetworkConfigDomainSuffixAutoGenerated(t *testing.T) {
+	tests := []struct {
+		name         string
+		domainSuffix string
+		template     string
+		want         bool
+	}{
+		{name: "unset", want: false},
+		{name: "explicit", domainSuffix: "apps.example.com", want: false},
+		{name: "magic DNS", domainSuffix: "10.0.0.1." + DefaultMagicDNS, want: true},
+		{name: "magic DNS IPv6", domainSuffix: ComposeMagicDNSSuffix("2001:db8::1", DefaultMagicDNS), want: true},
+		{name: "template", domainSuffix: "10.0.0.1.nip.io", template: "%s.nip.io", want: true},
+		{name: "template without an IP", domainSuffix: "apps.nip.io", template: "%s.nip.io", want: false},
+		{name: "other than the template", domainSuffix: "10.0.0.1." + DefaultMagicDNS, template: "%s.nip.io", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networkConfig, err := parseNetworkConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix:     tt.domainSuffix,
+				consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate: tt.template,
+			}))
+			if err != nil {
+				t.Fatalf("parseNetworkConfig() error = %v", err)
+			}
+			if networkConfig.DomainSuffixAutoGenerated != tt.want {
+				t.Errorf("DomainSuffixAutoGenerated = %v, want %v", networkConfig.DomainSuffixAutoGenerated, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposeAndPersistDomainSuffix(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]string
+		existing string
+		ip       string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "dotted",
+			ip:   "10.0.0.1",
+			want: "10.0.0.1." + GetMagicDNS(),
+		},
+		{
+			name: "dashed",
+			data: map[string]string{consts.KubeConfigMapKeyNetworkConfigMagicDNSIPFormat: string(MagicDNSIPFormatDashed)},
+			ip:   "10.0.0.1",
+			want: "10-0-0-1." + GetMagicDNS(),
+		},
+		{
+			name: "template",
+			data: map[string]string{consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate: "%s.nip.io"},
+			ip:   "10.0.0.1",
+			want: "10.0.0.1.nip.io",
+		},
+		{
+			name:     "already persisted",
+			existing: "10.0.0.2." + GetMagicDNS(),
+			ip:       "10.0.0.1",
+			want:     "10.0.0.2." + GetMagicDNS(),
+		},
+		{
+			name:    "invalid domain suffix",
+			data:    map[string]string{consts.KubeConfigMapKeyNetworkConfigMagicDNSTemplate: "%s.-invalid"},
+			ip:      "10.0.0.1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(tt.data)
+			stored := configMap.DeepCopy()
+			if tt.existing != "" {
+				stored.Data = map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: tt.existing}
+			}
+			cliset := fake.NewSimpleClientset(stored)
+
+			domainSuffix, err := ComposeAndPersistDomainSuffix(context.Background(), cliset, configMap, tt.ip)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ComposeAndPersistDomainSuffix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if domainSuffix != tt.want {
+				t.Errorf("ComposeAndPersistDomainSuffix() = %q, want %q", domainSuffix, tt.want)
+			}
+
+			persisted, err := cliset.CoreV1().ConfigMaps(configMap.Namespace).Get(context.Background(), configMap.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get the network configmap: %v", err)
+			}
+			if got := persisted.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; got != tt.want {
+				t.Errorf("the persisted domain suffix = %q, want %q", got, tt.want)
+			}
+			if len(cliset.Actions()) == 0 {
+				t.Errorf("ComposeAndPersistDomainSuffix() made no API calls, want the domain suffix persisted")
+			}
+			for _, action := range cliset.Actions() {
+				if action.GetResource().Resource == "ingresses" {
+					t.Errorf("ComposeAndPersistDomainSuffix() called %s on ingresses, want no probe", action.GetVerb())
+				}
+			}
+		})
+	}
+}
+
+func TestComposeAndPersistDomainSuffixDryRun(t *testing.T) {
+	configMap := newNetworkConfigMap(nil)
+	cliset := fake.NewSimpleClientset(configMap.DeepCopy())
+
+	domainSuffix, err := ComposeAndPersistDomainSuffix(WithDryRun(context.Background()), cliset, configMap, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("ComposeAndPersistDomainSuffix() error = %v", err)
+	}
+	if want := "10.0.0.1." + GetMagicDNS(); domainSuffix != want {
+		t.Errorf("ComposeAndPersistDomainSuffix() = %q, want %q", domainSuffix, want)
+	}
+	if len(cliset.Actions()) != 0 {
+		t.Errorf("ComposeAndPersistDomainSuffix() in dry run made the API calls %v, want none", cliset.Actions())
+	}
+}
+
+func TestGetDomainSuffixForceRediscovery(t *testing.T) {
+	magicDNS := GetMagicDNS()
+	tests := []struct {
+		name        string
+		existing    string
+		force       bool
+		want        string
+		wantUpdated bool
+	}{
+		{
+			name:     "without force",
+			existing: "10.0.0.2." + magicDNS,
+			want:     "10.0.0.2." + magicDNS,
+		},
+		{
+			name:        "force with change",
+			existing:    "10.0.0.2." + magicDNS,
+			force:       true,
+			want:        "10.0.0.1." + magicDNS,
+			wantUpdated: true,
+		},
+		{
+			name:     "force without change",
+			existing: "10.0.0.1." + magicDNS,
+			force:    true,
+			want:     "10.0.0.1." + magicDNS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix:          tt.existing,
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+			if err := cliset.Tracker().Add(configMap); err != nil {
+				t.Fatalf("failed to add the network configmap: %v", err)
+			}
+			var updates int
+			cliset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				updates++
+				return false, nil, nil
+			})
+
+			ctx := context.Background()
+			if tt.force {
+				ctx = WithForceRediscovery(ctx)
+			}
+			domainSuffix, err := GetDomainSuffix(ctx, staticConfigMapGetter(configMap), cliset)
+			if err != nil {
+				t.Fatalf("GetDomainSuffix() error = %v", err)
+			}
+			if domainSuffix != tt.want {
+				t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, tt.want)
+			}
+			if (updates > 0) != tt.wantUpdated {
+				t.Errorf("the network configmap was updated %d times, want updated %v", updates, tt.wantUpdated)
+			}
+
+			persisted, err := cliset.CoreV1().ConfigMaps(configMap.Namespace).Get(ctx, configMap.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get the network configmap: %v", err)
+			}
+			if got := persisted.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; got != tt.want {
+				t.Errorf("the persisted domain suffix = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDomainSuffixResult(t *testing.T) {
+	magicDNS := GetMagicDNS()
+	tests := []struct {
+		name     string
+		existing string
+		env      string
+		want     DomainSuffixResult
+	}{
+		{
+			name:     "configured",
+			existing: "example.com",
+			want:     DomainSuffixResult{DomainSuffix: "example.com", Outcome: DiscoveryOutcomeConfigured},
+		},
+		{
+			name:     "configured magic DNS",
+			existing: "10.0.0.2." + magicDNS,
+			want:     DomainSuffixResult{DomainSuffix: "10.0.0.2." + magicDNS, Outcome: DiscoveryOutcomeConfigured, IP: "10.0.0.2"},
+		},
+		{
+			name: "overridden",
+			env:  "override.example.com",
+			want: DomainSuffixResult{DomainSuffix: "override.example.com", Outcome: DiscoveryOutcomeOverridden},
+		},
+		{
+			name: "discovered",
+			want: DomainSuffixResult{DomainSuffix: "10.0.0.1." + magicDNS, Outcome: DiscoveryOutcomeDiscovered, IP: "10.0.0.1", Changed: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DomainSuffixEnvKey, tt.env)
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDomainSuffix:          tt.existing,
+			})
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+			if err := cliset.Tracker().Add(configMap); err != nil {
+				t.Fatalf("failed to add the network configmap: %v", err)
+			}
+
+			result, err := GetDomainSuffixResult(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if err != nil {
+				t.Fatalf("GetDomainSuffixResult() error = %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("GetDomainSuffixResult() = %+v, want %+v", result, tt.want)
+			}
+		})
+	}
+}
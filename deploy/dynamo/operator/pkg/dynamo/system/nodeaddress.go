@@ -0,0 +1,92 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// ErrNoNodeAddress is returned by the node address fallback when no node has an
+// address of the configured type.
+var ErrNoNodeAddress = errors.New("no node address to fall back to")
+
+// parseNodeAddressFallback parses the discovery-node-address-fallback key,
+// `external` or `internal`, into the type of the node addresses to fall back to.
+// It is empty, i.e. off, by default.
+func parseNodeAddressFallback(configMap *corev1.ConfigMap) (corev1.NodeAddressType, error) {
+	value := strings.ToLower(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryNodeAddressFallback]))
+	switch value {
+	case "":
+		return "", nil
+	case "external":
+		return corev1.NodeExternalIP, nil
+	case "internal":
+		return corev1.NodeInternalIP, nil
+	default:
+		return "", errors.Errorf("invalid %s %q in configmap %s, must be external or internal", consts.KubeConfigMapKeyNetworkConfigDiscoveryNodeAddressFallback, value, consts.KubeConfigMapNameNetworkConfig)
+	}
+}
+
+// getNodeAddresses returns the addresses of the type of the nodes, the ready
+// ones first and each by name, for the ingress controller to be reached through
+// a NodePort when no load balancer is ever provisioned.
+func getNodeAddresses(ctx context.Context, cliset kubernetes.Interface, addressType corev1.NodeAddressType) ([]IngressAddress, error) {
+	nodes, err := cliset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the nodes")
+	}
+	items := nodes.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		if ready := isNodeReady(&items[i]); ready != isNodeReady(&items[j]) {
+			return ready
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	var addresses []IngressAddress
+	for _, node := range items {
+		for _, address := range node.Status.Addresses {
+			if address.Type == addressType && address.Address != "" {
+				addresses = append(addresses, IngressAddress{IP: address.Address})
+				break
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, errors.Wrapf(ErrNoNodeAddress, "none of the %d nodes has an %s", len(items), addressType)
+	}
+	return addresses, nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
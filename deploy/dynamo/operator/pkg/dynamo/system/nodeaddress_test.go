@@ -0,0 +1,141 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func newNode(name string, ready bool, addresses ...corev1.NodeAddress) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			Addresses:  addresses,
+		},
+	}
+}
+
+func TestGetIngressIPNodeAddressFallback(t *testing.T) {
+	nodes := []*corev1.Node{
+		newNode("node-a", false, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "192.168.0.1"}, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.1"}),
+		newNode("node-b", true, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "192.168.0.2"}, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.2"}),
+		newNode("node-c", true, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "192.168.0.3"}),
+	}
+	tests := []struct {
+		name     string
+		fallback string
+		nodes    []*corev1.Node
+		want     string
+		wantErr  error
+	}{
+		{name: "external", fallback: "external", nodes: nodes, want: "203.0.113.2"},
+		{name: "internal", fallback: "Internal", nodes: nodes, want: "192.168.0.2"},
+		{name: "none available", fallback: "external", nodes: nodes[2:], wantErr: context.DeadlineExceeded},
+		{name: "off", nodes: nodes, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset()
+			for _, node := range tt.nodes {
+				if err := cliset.Tracker().Add(node); err != nil {
+					t.Fatalf("failed to add the node: %v", err)
+				}
+			}
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:                 "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:        "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:         "50ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryNodeAddressFallback: tt.fallback,
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetIngressIP() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if ip != tt.want {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPNodeAddressFallbackWithoutLoadBalancerProvider(t *testing.T) {
+	cliset := newLoadBalancerClientset()
+	var creates int
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		creates++
+		return false, nil, nil
+	})
+	for _, object := range []runtime.Object{
+		newNode("node-a", true, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.1"}),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx", Labels: map[string]string{"app.kubernetes.io/name": "ingress-nginx"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	} {
+		if err := cliset.Tracker().Add(object); err != nil {
+			t.Fatalf("failed to add the object: %v", err)
+		}
+	}
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:                 "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryNodeAddressFallback: "external",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPreflight:           "true",
+	})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "203.0.113.1" {
+		t.Errorf("GetIngressIP() = %q, want 203.0.113.1", ip)
+	}
+	if creates != 0 {
+		t.Errorf("GetIngressIP() created %d probe ingresses, want none", creates)
+	}
+}
+
+func TestParseDiscoveryConfigNodeAddressFallbackInvalid(t *testing.T) {
+	_, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryNodeAddressFallback: "hostname",
+	}))
+	if err == nil {
+		t.Fatal("parseDiscoveryConfig() error = nil, want an invalid discovery-node-address-fallback")
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"sync"
+)
+
+// subscriberBufferSize is the number of results buffered for a subscriber before
+// the oldest ones are dropped.
+const subscriberBufferSize = 16
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   = make(map[chan DiscoveryResult]struct{})
+)
+
+// Subscribe returns a channel that receives a DiscoveryResult whenever
+// GetDomainSuffix returns a domain suffix that differs from the previous one of
+// the namespace, and a function that unsubscribes and closes the channel.
+//
+// Discovery never blocks on a slow subscriber: when its buffer is full, the oldest
+// result is dropped.
+func Subscribe() (results <-chan DiscoveryResult, unsubscribe func()) {
+	ch := make(chan DiscoveryResult, subscriberBufferSize)
+
+	subscribersMu.Lock()
+	subscribers[ch] = struct{}{}
+	subscribersMu.Unlock()
+
+	var once sync.Once
+	unsubscribe = func() {
+		once.Do(func() {
+			subscribersMu.Lock()
+			defer subscribersMu.Unlock()
+			delete(subscribers, ch)
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+func notifySubscribers(result DiscoveryResult) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	for ch := range subscribers {
+		select {
+		case ch <- result:
+			continue
+		default:
+		}
+		// The buffer is full, drop the oldest result to make room.
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- result:
+		default:
+		}
+	}
+}
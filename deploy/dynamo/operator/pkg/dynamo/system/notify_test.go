@@ -0,0 +1,111 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestNotifySubscribersDropsOldest(t *testing.T) {
+	results, unsubscribe := Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBufferSize+2; i++ {
+		notifySubscribers(DiscoveryResult{Namespace: fmt.Sprintf("team-%d", i)})
+	}
+
+	if len(results) != subscriberBufferSize {
+		t.Fatalf("the subscriber has %d results buffered, want %d", len(results), subscriberBufferSize)
+	}
+	if first := <-results; first.Namespace != "team-2" {
+		t.Errorf("the oldest buffered result is of %s, want team-2 once the 2 oldest are dropped", first.Namespace)
+	}
+}
+
+func TestSubscribeUnsubscribe(t *testing.T) {
+	results, unsubscribe := Subscribe()
+	unsubscribe()
+	// Unsubscribing twice is a no-op.
+	unsubscribe()
+
+	notifySubscribers(DiscoveryResult{Namespace: "team-a"})
+	if _, ok := <-results; ok {
+		t.Error("the channel received a result after unsubscribing, want it closed")
+	}
+}
+
+func TestGetDomainSuffixNotifiesChanges(t *testing.T) {
+	results, unsubscribe := Subscribe()
+	defer unsubscribe()
+
+	configMap := newNetworkConfigMap(map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "a.example.com"})
+	configMap.Namespace = "notify"
+	ctx := WithNamespace(context.Background(), "notify")
+	for _, domainSuffix := range []string{"a.example.com", "a.example.com", "b.example.com"} {
+		configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix] = domainSuffix
+		if _, err := GetDomainSuffix(ctx, staticConfigMapGetter(configMap), newLoadBalancerClientset()); err != nil {
+			t.Fatalf("GetDomainSuffix() error = %v", err)
+		}
+	}
+
+	var notified []string
+	for len(results) > 0 {
+		result := <-results
+		if result.Namespace == "notify" {
+			notified = append(notified, result.DomainSuffix)
+		}
+	}
+	if len(notified) != 2 || notified[0] != "a.example.com" || notified[1] != "b.example.com" {
+		t.Errorf("notified %v, want a.example.com then b.example.com, without the unchanged one", notified)
+	}
+}
+
+func TestGetDomainSuffixNotifiesConcurrentChangeOnce(t *testing.T) {
+	results, unsubscribe := Subscribe()
+	defer unsubscribe()
+
+	configMap := newNetworkConfigMap(map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "a.example.com"})
+	configMap.Namespace = "notify-concurrent"
+	ctx := WithNamespace(context.Background(), "notify-concurrent")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := GetDomainSuffix(ctx, staticConfigMapGetter(configMap), newLoadBalancerClientset()); err != nil {
+				t.Errorf("GetDomainSuffix() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var notified int
+	for len(results) > 0 {
+		if result := <-results; result.Namespace == "notify-concurrent" {
+			notified++
+		}
+	}
+	if notified != 1 {
+		t.Errorf("the concurrent discoveries notified %d times, want once", notified)
+	}
+}
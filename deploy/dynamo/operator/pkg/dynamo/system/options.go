@@ -0,0 +1,151 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// IngressIPOptions are the options of GetIngressIPWithOptions. The zero value of
+// each field keeps the behavior of GetIngressIP.
+type IngressIPOptions struct {
+	// Clientset is the client the probe ingress is created with.
+	Clientset kubernetes.Interface
+	// ConfigMapGetter gets the network configmap, e.g. from an informer cache.
+	// If nil, it is fetched with Clientset.
+	ConfigMapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+	// Namespace, if set, is the namespace whose network config is used, see
+	// WithNamespace.
+	Namespace string
+	// CorrelationID, if set, is the correlation ID of the discovery, see
+	// WithCorrelationID.
+	CorrelationID string
+	// ProbeOwner, if set, is the owner of the probe ingress, see WithProbeOwner.
+	ProbeOwner *metav1.OwnerReference
+	// NetworkConfigName, if set, is the name of the network configmap, see
+	// WithNetworkConfigName.
+	NetworkConfigName string
+	// OnProgress, if set, is called before each poll of the discovery, see
+	// WithProgress.
+	OnProgress ProgressFunc
+}
+
+// GetIngressIPWithOptions is GetIngressIP with its options gathered in a struct,
+// so that new ones can be added without breaking the callers.
+func GetIngressIPWithOptions(ctx context.Context, opts IngressIPOptions) (string, error) {
+	if opts.Clientset == nil && opts.ConfigMapGetter == nil {
+		return "", errors.New("the IngressIPOptions have neither a Clientset nor a ConfigMapGetter")
+	}
+	ctx = opts.apply(ctx)
+	address, err := GetIngressAddress(ctx, opts.configMapGetter(), opts.Clientset)
+	if err != nil {
+		return "", err
+	}
+	return address.Host(), nil
+}
+
+// apply returns the context carrying the options set.
+func (o IngressIPOptions) apply(ctx context.Context) context.Context {
+	if o.Namespace != "" {
+		ctx = WithNamespace(ctx, o.Namespace)
+	}
+	if o.CorrelationID != "" {
+		ctx = WithCorrelationID(ctx, o.CorrelationID)
+	}
+	if o.ProbeOwner != nil {
+		ctx = WithProbeOwner(ctx, *o.ProbeOwner)
+	}
+	if o.NetworkConfigName != "" {
+		ctx = WithNetworkConfigName(ctx, o.NetworkConfigName)
+	}
+	if o.OnProgress != nil {
+		ctx = WithProgress(ctx, o.OnProgress)
+	}
+	return ctx
+}
+
+func (o IngressIPOptions) configMapGetter() func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	if o.ConfigMapGetter != nil {
+		return o.ConfigMapGetter
+	}
+	return func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return o.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+}
+
+// NetworkConfigOptions are the options of GetNetworkConfigConfigMapWithOptions
+// and GetIngressConfigWithOptions, for an operator whose network config isn't
+// the default one, e.g. one of several operators in a namespace.
+type NetworkConfigOptions struct {
+	// ConfigMapName, if set, is the name of the network configmap instead of
+	// consts.KubeConfigMapNameNetworkConfig.
+	ConfigMapName string
+	// KeyPrefix, if set, is the prefix of the keys of the network configmap that
+	// are read, e.g. "team-a." for "team-a.ingress-class". The keys without it
+	// are ignored.
+	KeyPrefix string
+}
+
+// GetNetworkConfigConfigMapWithOptions is GetNetworkConfigConfigMap reading the
+// network configmap of the options. With a KeyPrefix, the configmap returned is
+// a copy keeping only the keys with the prefix, with the prefix trimmed.
+func GetNetworkConfigConfigMapWithOptions(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), opts NetworkConfigOptions) (*corev1.ConfigMap, error) {
+	configMap, err := GetNetworkConfigConfigMap(opts.apply(ctx), configmapGetter)
+	if err != nil {
+		return nil, err
+	}
+	return opts.trimKeyPrefix(configMap), nil
+}
+
+// GetIngressConfigWithOptions is GetIngressConfig reading the network configmap
+// of the options.
+func GetIngressConfigWithOptions(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), opts NetworkConfigOptions) (ingressConfig *IngressConfig, err error) {
+	configMap, err := getNetworkConfigConfigMapOrDefault(opts.apply(ctx), configmapGetter)
+	if err != nil {
+		return
+	}
+	return ParseIngressConfig(opts.trimKeyPrefix(configMap))
+}
+
+// apply returns the context carrying the options set.
+func (o NetworkConfigOptions) apply(ctx context.Context) context.Context {
+	if o.ConfigMapName != "" {
+		ctx = WithNetworkConfigName(ctx, o.ConfigMapName)
+	}
+	return ctx
+}
+
+func (o NetworkConfigOptions) trimKeyPrefix(configMap *corev1.ConfigMap) *corev1.ConfigMap {
+	if o.KeyPrefix == "" {
+		return configMap
+	}
+	trimmed := configMap.DeepCopy()
+	trimmed.Data = make(map[string]string, len(configMap.Data))
+	for key, value := range configMap.Data {
+		if name, ok := strings.CutPrefix(key, o.KeyPrefix); ok {
+			trimmed.Data[name] = value
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,217 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestGetIngressIPWithOptions(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+	newClientset := func() (*fake.Clientset, *[]*networkingv1.Ingress) {
+		cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+		if err := cliset.Tracker().Add(configMap); err != nil {
+			t.Fatalf("failed to add the network configmap: %v", err)
+		}
+		var probes []*networkingv1.Ingress
+		cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			probes = append(probes, action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress).DeepCopy())
+			return false, nil, nil
+		})
+		return cliset, &probes
+	}
+
+	cliset, _ := newClientset()
+	want, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+
+	t.Run("zero value", func(t *testing.T) {
+		cliset, probes := newClientset()
+		ip, err := GetIngressIPWithOptions(context.Background(), IngressIPOptions{Clientset: cliset})
+		if err != nil {
+			t.Fatalf("GetIngressIPWithOptions() error = %v", err)
+		}
+		if ip != want {
+			t.Errorf("GetIngressIPWithOptions() = %q, want the GetIngressIP() one %q", ip, want)
+		}
+		if len(*probes) != 1 || (*probes)[0].Namespace != GetNamespace() || len((*probes)[0].OwnerReferences) != 0 {
+			t.Errorf("GetIngressIPWithOptions() probe ingresses = %v, want one in %s without owner", *probes, GetNamespace())
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		cliset, probes := newClientset()
+		owner := metav1.OwnerReference{APIVersion: "v1", Kind: "Namespace", Name: GetNamespace(), UID: "uid"}
+		ip, err := GetIngressIPWithOptions(context.Background(), IngressIPOptions{
+			Clientset:       cliset,
+			ConfigMapGetter: staticConfigMapGetter(configMap),
+			CorrelationID:   "abc",
+			ProbeOwner:      &owner,
+		})
+		if err != nil {
+			t.Fatalf("GetIngressIPWithOptions() error = %v", err)
+		}
+		if ip != want {
+			t.Errorf("GetIngressIPWithOptions() = %q, want %q", ip, want)
+		}
+		if len(*probes) != 1 {
+			t.Fatalf("GetIngressIPWithOptions() created %d probe ingresses, want 1", len(*probes))
+		}
+		probe := (*probes)[0]
+		if got := probe.Annotations[consts.KubeAnnotationDynamoDiscoveryCorrelationID]; got != "abc" {
+			t.Errorf("probe correlation ID = %q, want abc", got)
+		}
+		if len(probe.OwnerReferences) != 1 || probe.OwnerReferences[0].UID != owner.UID {
+			t.Errorf("probe owner references = %v, want %v", probe.OwnerReferences, owner)
+		}
+	})
+
+	t.Run("no client", func(t *testing.T) {
+		if _, err := GetIngressIPWithOptions(context.Background(), IngressIPOptions{}); err == nil {
+			t.Error("GetIngressIPWithOptions() error = nil, want one without a client")
+		}
+	})
+}
+
+func TestGetIngressConfigWithOptions(t *testing.T) {
+	defaultConfigMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+	})
+	otherConfigMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "ignored",
+		"team-b." + consts.KubeConfigMapKeyNetworkConfigIngressClass: "traefik",
+		"team-b." + consts.KubeConfigMapKeyNetworkConfigIngressPath:  "/team-b",
+	})
+	otherConfigMap.Name = "network-b"
+	configmapGetter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return fake.NewSimpleClientset(defaultConfigMap, otherConfigMap).CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+
+	tests := []struct {
+		name      string
+		opts      NetworkConfigOptions
+		wantClass string
+		wantPath  string
+	}{
+		{name: "zero value", opts: NetworkConfigOptions{}, wantClass: "nginx", wantPath: "/"},
+		{name: "configmap name", opts: NetworkConfigOptions{ConfigMapName: "network-b"}, wantClass: "ignored", wantPath: "/"},
+		{name: "configmap name and key prefix", opts: NetworkConfigOptions{ConfigMapName: "network-b", KeyPrefix: "team-b."}, wantClass: "traefik", wantPath: "/team-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingressConfig, err := GetIngressConfigWithOptions(context.Background(), configmapGetter, tt.opts)
+			if err != nil {
+				t.Fatalf("GetIngressConfigWithOptions() error = %v", err)
+			}
+			if ingressConfig.ClassName == nil || *ingressConfig.ClassName != tt.wantClass {
+				t.Errorf("ClassName = %v, want %q", ingressConfig.ClassName, tt.wantClass)
+			}
+			if ingressConfig.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", ingressConfig.Path, tt.wantPath)
+			}
+		})
+	}
+
+	t.Run("missing configmap", func(t *testing.T) {
+		_, err := GetNetworkConfigConfigMapWithOptions(context.Background(), configmapGetter, NetworkConfigOptions{ConfigMapName: "network-c"})
+		if !errors.Is(err, ErrNetworkConfigNotFound) {
+			t.Errorf("GetNetworkConfigConfigMapWithOptions() error = %v, want ErrNetworkConfigNotFound", err)
+		}
+	})
+
+	t.Run("key prefix copies the configmap", func(t *testing.T) {
+		configMap, err := GetNetworkConfigConfigMapWithOptions(context.Background(), staticConfigMapGetter(otherConfigMap), NetworkConfigOptions{KeyPrefix: "team-b."})
+		if err != nil {
+			t.Fatalf("GetNetworkConfigConfigMapWithOptions() error = %v", err)
+		}
+		if len(configMap.Data) != 2 || configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressClass] != "traefik" {
+			t.Errorf("GetNetworkConfigConfigMapWithOptions() data = %v, want the team-b. keys trimmed", configMap.Data)
+		}
+		if otherConfigMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressClass] != "ignored" {
+			t.Errorf("GetNetworkConfigConfigMapWithOptions() modified the configmap it read: %v", otherConfigMap.Data)
+		}
+	})
+}
+
+func TestGetIngressIPWithOptionsOnProgress(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+	cliset := newLoadBalancerClientset()
+
+	var attempts []int
+	var elapsed []time.Duration
+	onProgress := func(e time.Duration, attempt int) {
+		attempts = append(attempts, attempt)
+		elapsed = append(elapsed, e)
+		if attempt < 3 {
+			return
+		}
+		// The load balancer gets its address before the third poll.
+		ingresses, err := cliset.NetworkingV1().Ingresses(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+		if err != nil || len(ingresses.Items) != 1 {
+			t.Errorf("failed to list the probe ingress: %v, %v", ingresses, err)
+			return
+		}
+		ing := ingresses.Items[0]
+		ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}
+		if _, err := cliset.NetworkingV1().Ingresses(GetNamespace()).UpdateStatus(context.Background(), &ing, metav1.UpdateOptions{}); err != nil {
+			t.Errorf("failed to update the probe ingress status: %v", err)
+		}
+	}
+
+	ip, err := GetIngressIPWithOptions(context.Background(), IngressIPOptions{
+		Clientset:       cliset,
+		ConfigMapGetter: staticConfigMapGetter(configMap),
+		OnProgress:      onProgress,
+	})
+	if err != nil {
+		t.Fatalf("GetIngressIPWithOptions() error = %v", err)
+	}
+	if ip != "10.0.0.1" {
+		t.Errorf("GetIngressIPWithOptions() = %q, want %q", ip, "10.0.0.1")
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("OnProgress attempts = %v, want %v", attempts, want)
+	}
+	for i := 1; i < len(elapsed); i++ {
+		if elapsed[i] < elapsed[i-1] {
+			t.Errorf("OnProgress elapsed = %v, want it increasing", elapsed)
+		}
+	}
+}
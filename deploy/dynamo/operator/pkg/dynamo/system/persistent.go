@@ -0,0 +1,254 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// persistentProbeIngressName is the name of the probe ingress that is kept
+// between discoveries in persistent mode, and persistentProbeHostLabel the
+// stable first label of its host.
+const (
+	persistentProbeIngressName = probeIngressGenerateName + "persistent"
+	persistentProbeHostLabel   = "persistent"
+)
+
+// certManagerAnnotationPrefixes are the prefixes of the cert-manager annotations,
+// which are removed from the persistent probe ingress when they are removed from
+// the network config, so that a changed issuer gets a new certificate issued.
+var certManagerAnnotationPrefixes = []string{
+	"cert-manager.io/",
+	"acme.cert-manager.io/",
+}
+
+// EnsureProbeIngress creates the persistent probe ingress of the network config,
+// owned by owner, or updates it when the network config changed, and returns it,
+// for the controllers that keep the probe across their reconciles rather than
+// having every discovery create and delete one. It doesn't wait for the ingress
+// to be ready: ProbeIngressAddress reads its address once it is.
+func EnsureProbeIngress(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface, owner metav1.OwnerReference) (*networkingv1.Ingress, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	namespace := namespaceFromContext(ctx)
+	logger := discoveryLogger(ctx, correlationID).WithValues("namespace", namespace)
+
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
+	}
+	ingressConfig, err := ParseIngressConfig(configMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get ingress config")
+	}
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get discovery config")
+	}
+	// The probe outlives the call, so it has the stable name and host of the
+	// persistent one.
+	discoveryConfig.PersistentProbe = true
+
+	probeNamespace, err := getProbeNamespace(ctx, cliset, namespace, discoveryConfig)
+	if err != nil {
+		return nil, err
+	}
+	discoveryConfig = lookupProbeBackendPort(ctx, logger, cliset, probeNamespace, discoveryConfig)
+	controllerType := GetIngressControllerType(ctx, cliset, ingressConfig.ClassName)
+	probe, err := renderProbeIngress(logger, probeNamespace, correlationID, ingressConfig, discoveryConfig, controllerType)
+	if err != nil {
+		return nil, err
+	}
+	probe.OwnerReferences = []metav1.OwnerReference{owner}
+
+	var ing *networkingv1.Ingress
+	err = retryCreate(discoveryConfig.CreateRetries, func() (err error) {
+		ing, err = applyPersistentProbeIngress(ctx, getIngressClient(cliset, probeNamespace), probe)
+		return
+	})
+	if err != nil {
+		return nil, &ProbeCreateError{Kind: "ingress", Name: probe.Name, Err: err}
+	}
+	return ing, nil
+}
+
+// ProbeIngressAddress returns the first address the load balancer status of the
+// probe ingress reports, as is: a hostname isn't resolved. It returns
+// ErrIngressNoAddress if the ingress isn't ready yet.
+func ProbeIngressAddress(ing *networkingv1.Ingress) (IngressAddress, error) {
+	if ing == nil {
+		return IngressAddress{}, errors.Wrap(ErrIngressNoAddress, "no probe ingress")
+	}
+	for _, entry := range ing.Status.LoadBalancer.Ingress {
+		if entry.IP == "" && entry.Hostname == "" {
+			continue
+		}
+		address := IngressAddress{IP: entry.IP, Hostname: entry.Hostname}
+		for _, port := range entry.Ports {
+			address.Ports = append(address.Ports, port.Port)
+		}
+		return address, nil
+	}
+	return IngressAddress{}, errors.Wrapf(ErrIngressNoAddress, "the ingress %s/%s", ing.Namespace, ing.Name)
+}
+
+// applyPersistentProbeIngress creates the persistent probe ingress, or updates it
+// when the desired one differs in a way that matters to the controller.
+func applyPersistentProbeIngress(ctx context.Context, ingressCli IngressClient, desired *networkingv1.Ingress) (*networkingv1.Ingress, error) {
+	existing, err := ingressCli.Get(ctx, desired.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		ing, err := ingressCli.Create(ctx, desired, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create ingress %s", desired.Name)
+		}
+		return ing, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get ingress %s", desired.Name)
+	}
+
+	if !probeIngressChanged(existing, desired) {
+		return existing, nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Annotations = mergeProbeAnnotations(existing.Annotations, desired.Annotations)
+	if updated.Labels == nil {
+		updated.Labels = make(map[string]string, len(desired.Labels))
+	}
+	for k, v := range desired.Labels {
+		updated.Labels[k] = v
+	}
+	updated.OwnerReferences = mergeOwnerReferences(existing.OwnerReferences, desired.OwnerReferences)
+	updated.Spec = desired.Spec
+	ing, err := ingressCli.Update(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update ingress %s", desired.Name)
+	}
+	return ing, nil
+}
+
+// findReusableProbeIngress returns a probe ingress with the domain probe label,
+// preferring one that already has a load balancer address, or nil if there is
+// none.
+func findReusableProbeIngress(ctx context.Context, ingressCli IngressClient) (*networkingv1.Ingress, error) {
+	selector := labels.SelectorFromSet(labels.Set{consts.KubeLabelDynamoPurpose: consts.KubeLabelValueDomainProbe}).String()
+	list, err := ingressCli.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the ingresses by %s", selector)
+	}
+
+	var found *networkingv1.Ingress
+	for i := range list.Items {
+		ing := &list.Items[i]
+		if ing.DeletionTimestamp != nil {
+			continue
+		}
+		if hasLoadBalancerAddress(ing.Status.LoadBalancer.Ingress) {
+			return ing, nil
+		}
+		if found == nil {
+			found = ing
+		}
+	}
+	return found, nil
+}
+
+// probeIngressChanged reports whether the live probe ingress differs from the
+// desired one in its spec or in the annotations set from the network config, or
+// lacks one of its owner references. The correlation ID annotation changes on
+// every discovery and is ignored.
+func probeIngressChanged(existing, desired *networkingv1.Ingress) bool {
+	if !equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return true
+	}
+	for k, v := range desired.Annotations {
+		if k == consts.KubeAnnotationDynamoDiscoveryCorrelationID {
+			continue
+		}
+		if existing.Annotations[k] != v {
+			return true
+		}
+	}
+	for k := range existing.Annotations {
+		if _, ok := desired.Annotations[k]; !ok && isCertManagerAnnotation(k) {
+			return true
+		}
+	}
+	for k, v := range desired.Labels {
+		if existing.Labels[k] != v {
+			return true
+		}
+	}
+	return len(mergeOwnerReferences(existing.OwnerReferences, desired.OwnerReferences)) != len(existing.OwnerReferences)
+}
+
+// mergeOwnerReferences returns the live owner references with the desired ones
+// that are missing, by UID, appended.
+func mergeOwnerReferences(existing, desired []metav1.OwnerReference) []metav1.OwnerReference {
+	merged := append([]metav1.OwnerReference(nil), existing...)
+	for _, owner := range desired {
+		found := false
+		for _, ref := range existing {
+			if ref.UID == owner.UID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, owner)
+		}
+	}
+	return merged
+}
+
+// mergeProbeAnnotations returns the desired annotations on top of the live ones,
+// without the cert-manager annotations that are no longer desired.
+func mergeProbeAnnotations(existing, desired map[string]string) map[string]string {
+	merged := make(map[string]string, len(existing)+len(desired))
+	for k, v := range existing {
+		if _, ok := desired[k]; !ok && isCertManagerAnnotation(k) {
+			continue
+		}
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
+
+func isCertManagerAnnotation(key string) bool {
+	for _, prefix := range certManagerAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,409 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func newReuseProbeConfigMap() map[string]string {
+	return map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe:      "true",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	}
+}
+
+func TestGetIngressIPReusesLabeledProbe(t *testing.T) {
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	existing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      probeIngressGenerateName + "shared",
+			Namespace: GetNamespace(),
+			Labels:    map[string]string{consts.KubeLabelDynamoPurpose: consts.KubeLabelValueDomainProbe},
+		},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+				Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.42"}},
+			},
+		},
+	}
+	if err := cliset.Tracker().Add(existing); err != nil {
+		t.Fatalf("failed to add the probe ingress: %v", err)
+	}
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(newNetworkConfigMap(newReuseProbeConfigMap())), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.42" {
+		t.Errorf("GetIngressIP() = %q, want the address of the reused probe %q", ip, "10.0.0.42")
+	}
+
+	for _, action := range cliset.Actions() {
+		if action.GetResource().Resource != "ingresses" {
+			continue
+		}
+		switch action.GetVerb() {
+		case "list":
+			if selector := action.(k8stesting.ListAction).GetListRestrictions().Labels.String(); selector != consts.KubeLabelDynamoPurpose+"="+consts.KubeLabelValueDomainProbe {
+				t.Errorf("probe ingresses listed by %q, want the domain probe label", selector)
+			}
+		case "create", "delete":
+			t.Errorf("unexpected %s of an ingress when a probe can be reused", action.GetVerb())
+		}
+	}
+}
+
+func TestGetIngressIPCreatesReusableProbe(t *testing.T) {
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(newNetworkConfigMap(newReuseProbeConfigMap())), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.1" {
+		t.Errorf("GetIngressIP() = %q, want %q", ip, "10.0.0.1")
+	}
+
+	probes, err := cliset.NetworkingV1().Ingresses(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list ingresses: %v", err)
+	}
+	if len(probes.Items) != 1 {
+		t.Fatalf("%d probe ingresses left, want the reusable one", len(probes.Items))
+	}
+	if purpose := probes.Items[0].Labels[consts.KubeLabelDynamoPurpose]; purpose != consts.KubeLabelValueDomainProbe {
+		t.Errorf("probe ingress %s label = %q, want %q", consts.KubeLabelDynamoPurpose, purpose, consts.KubeLabelValueDomainProbe)
+	}
+}
+
+// newReadOnlyClientset is newLoadBalancerClientset where ingresses may only be
+// read, as with an RBAC restricted to get and list.
+func newReadOnlyClientset(existing ...*networkingv1.Ingress) *fake.Clientset {
+	cliset := newLoadBalancerClientset()
+	for _, ing := range existing {
+		_ = cliset.Tracker().Add(ing)
+	}
+	for _, verb := range []string{"create", "update", "patch", "delete"} {
+		cliset.PrependReactor(verb, "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewForbidden(networkingv1.Resource("ingresses"), "", errors.New("read-only"))
+		})
+	}
+	return cliset
+}
+
+func TestGetIngressIPReadOnlyProbeIngress(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress: "gitops-probe",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:  "50ms",
+	})
+	existing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "gitops-probe", Namespace: GetNamespace()},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+				Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.42"}},
+			},
+		},
+	}
+	cliset := newReadOnlyClientset(existing)
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.42" {
+		t.Errorf("GetIngressIP() = %q, want the address of the existing ingress %q", ip, "10.0.0.42")
+	}
+	if _, err := cliset.NetworkingV1().Ingresses(GetNamespace()).Get(context.Background(), "gitops-probe", metav1.GetOptions{}); err != nil {
+		t.Errorf("the existing ingress was deleted: %v", err)
+	}
+
+	cliset = newReadOnlyClientset()
+	_, err = GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if !k8serrors.IsNotFound(err) || !strings.Contains(err.Error(), "does not exist, and it is never created") {
+		t.Errorf("GetIngressIP() error = %v, want a not found error for the probe ingress", err)
+	}
+
+	for _, action := range cliset.Actions() {
+		if action.GetResource().Resource != "ingresses" {
+			continue
+		}
+		if verb := action.GetVerb(); verb != "get" && verb != "list" {
+			t.Errorf("unexpected %s of an ingress in read-only mode", verb)
+		}
+	}
+}
+
+func TestParseDiscoveryConfigProbeIngress(t *testing.T) {
+	for _, key := range []string{consts.KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe, consts.KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe} {
+		_, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+			consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress: "gitops-probe",
+			key: "true",
+		}))
+		if err == nil {
+			t.Errorf("parseDiscoveryConfig() error = nil with %s, want an error", key)
+		}
+	}
+
+	_, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeIngress: "GitOps_Probe",
+	}))
+	if err == nil {
+		t.Error("parseDiscoveryConfig() error = nil with an invalid ingress name, want an error")
+	}
+}
+
+func TestEnsureProbeIngress(t *testing.T) {
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "owner-uid"}
+	data := map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx",
+	}
+	cliset := newLoadBalancerClientset()
+	getter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return newNetworkConfigMap(data), nil
+	}
+	countActions := func(verb string) int {
+		n := 0
+		for _, action := range cliset.Actions() {
+			if action.GetVerb() == verb && action.GetResource().Resource == "ingresses" {
+				n++
+			}
+		}
+		return n
+	}
+
+	created, err := EnsureProbeIngress(context.Background(), getter, cliset, owner)
+	if err != nil {
+		t.Fatalf("EnsureProbeIngress() error = %v", err)
+	}
+	if created.Name != persistentProbeIngressName {
+		t.Errorf("EnsureProbeIngress() name = %q, want %q", created.Name, persistentProbeIngressName)
+	}
+	if len(created.OwnerReferences) != 1 || created.OwnerReferences[0].UID != owner.UID {
+		t.Errorf("EnsureProbeIngress() owner references = %v, want %v", created.OwnerReferences, owner)
+	}
+	if n := countActions("create"); n != 1 {
+		t.Errorf("EnsureProbeIngress() created %d ingresses, want 1", n)
+	}
+
+	// A reconcile with the same network config must not write.
+	again, err := EnsureProbeIngress(context.Background(), getter, cliset, owner)
+	if err != nil {
+		t.Fatalf("EnsureProbeIngress() again error = %v", err)
+	}
+	if again.Name != created.Name {
+		t.Errorf("EnsureProbeIngress() again name = %q, want %q", again.Name, created.Name)
+	}
+	if n, m := countActions("create"), countActions("update"); n != 1 || m != 0 {
+		t.Errorf("EnsureProbeIngress() again created %d and updated %d ingresses, want 1 and 0", n, m)
+	}
+
+	data[consts.KubeConfigMapKeyNetworkConfigIngressAnnotations] = `{"example.com/probe": "changed"}`
+	updated, err := EnsureProbeIngress(context.Background(), getter, cliset, owner)
+	if err != nil {
+		t.Fatalf("EnsureProbeIngress() after a change error = %v", err)
+	}
+	if updated.Annotations["example.com/probe"] != "changed" {
+		t.Errorf("EnsureProbeIngress() after a change annotations = %v, want the changed one", updated.Annotations)
+	}
+	if n := countActions("update"); n != 1 {
+		t.Errorf("EnsureProbeIngress() after a change updated %d ingresses, want 1", n)
+	}
+}
+
+func TestProbeIngressChanged(t *testing.T) {
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "owner-uid"}
+	newProbe := func() *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: persistentProbeIngressName,
+				Annotations: map[string]string{
+					"example.com/probe": "a",
+					consts.KubeAnnotationDynamoDiscoveryCorrelationID: "first",
+				},
+				Labels:          map[string]string{consts.KubeLabelDynamoPurpose: consts.KubeLabelValueDomainProbe},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Spec: networkingv1.IngressSpec{IngressClassName: ptr.To("nginx")},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(existing, desired *networkingv1.Ingress)
+		want   bool
+	}{
+		{name: "unchanged", mutate: func(existing, desired *networkingv1.Ingress) {}},
+		{
+			name: "correlation ID",
+			mutate: func(existing, desired *networkingv1.Ingress) {
+				desired.Annotations[consts.KubeAnnotationDynamoDiscoveryCorrelationID] = "second"
+			},
+		},
+		{
+			name: "foreign annotation",
+			mutate: func(existing, desired *networkingv1.Ingress) {
+				existing.Annotations["example.com/other"] = "set by someone else"
+			},
+		},
+		{
+			name:   "spec",
+			mutate: func(existing, desired *networkingv1.Ingress) { desired.Spec.IngressClassName = ptr.To("traefik") },
+			want:   true,
+		},
+		{
+			name:   "annotation",
+			mutate: func(existing, desired *networkingv1.Ingress) { desired.Annotations["example.com/probe"] = "b" },
+			want:   true,
+		},
+		{
+			name: "removed cert-manager annotation",
+			mutate: func(existing, desired *networkingv1.Ingress) {
+				existing.Annotations["cert-manager.io/cluster-issuer"] = "letsencrypt"
+			},
+			want: true,
+		},
+		{
+			name:   "label",
+			mutate: func(existing, desired *networkingv1.Ingress) { desired.Labels["example.com/team"] = "a" },
+			want:   true,
+		},
+		{
+			name: "missing owner",
+			mutate: func(existing, desired *networkingv1.Ingress) {
+				desired.OwnerReferences = append(desired.OwnerReferences, metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"})
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, desired := newProbe(), newProbe()
+			tt.mutate(existing, desired)
+			if got := probeIngressChanged(existing, desired); got != tt.want {
+				t.Errorf("probeIngressChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPersistentProbeIngress(t *testing.T) {
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "owner-uid"}
+	other := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"}
+	existing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      persistentProbeIngressName,
+			Namespace: GetNamespace(),
+			Annotations: map[string]string{
+				"example.com/other":              "set by someone else",
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+			Labels:          map[string]string{"example.com/team": "a"},
+			OwnerReferences: []metav1.OwnerReference{other},
+		},
+		Spec: networkingv1.IngressSpec{IngressClassName: ptr.To("nginx")},
+	}
+	cliset := fake.NewSimpleClientset(existing)
+	desired := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            persistentProbeIngressName,
+			Annotations:     map[string]string{"example.com/probe": "a"},
+			Labels:          map[string]string{consts.KubeLabelDynamoPurpose: consts.KubeLabelValueDomainProbe},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Spec: networkingv1.IngressSpec{IngressClassName: ptr.To("traefik")},
+	}
+
+	updated, err := applyPersistentProbeIngress(context.Background(), cliset.NetworkingV1().Ingresses(GetNamespace()), desired)
+	if err != nil {
+		t.Fatalf("applyPersistentProbeIngress() error = %v", err)
+	}
+	wantAnnotations := map[string]string{"example.com/other": "set by someone else", "example.com/probe": "a"}
+	if !reflect.DeepEqual(updated.Annotations, wantAnnotations) {
+		t.Errorf("annotations = %v, want %v without the removed cert-manager one", updated.Annotations, wantAnnotations)
+	}
+	if updated.Labels["example.com/team"] != "a" || updated.Labels[consts.KubeLabelDynamoPurpose] != consts.KubeLabelValueDomainProbe {
+		t.Errorf("labels = %v, want the live and desired ones", updated.Labels)
+	}
+	if len(updated.OwnerReferences) != 2 {
+		t.Errorf("owner references = %v, want the live and desired ones", updated.OwnerReferences)
+	}
+	if *updated.Spec.IngressClassName != "traefik" {
+		t.Errorf("ingress class = %s, want the desired traefik", *updated.Spec.IngressClassName)
+	}
+}
+
+func TestProbeIngressAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		ing     *networkingv1.Ingress
+		want    IngressAddress
+		wantErr bool
+	}{
+		{name: "nil ingress", wantErr: true},
+		{name: "no address", ing: &networkingv1.Ingress{}, wantErr: true},
+		{
+			name: "ip",
+			ing: &networkingv1.Ingress{Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+				Ingress: []networkingv1.IngressLoadBalancerIngress{{}, {IP: "10.0.0.1", Ports: []networkingv1.IngressPortStatus{{Port: 443}}}},
+			}}},
+			want: IngressAddress{IP: "10.0.0.1", Ports: []int32{443}},
+		},
+		{
+			name: "hostname",
+			ing: &networkingv1.Ingress{Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+				Ingress: []networkingv1.IngressLoadBalancerIngress{{Hostname: "lb.example.com"}},
+			}}},
+			want: IngressAddress{Hostname: "lb.example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ProbeIngressAddress(tt.ing)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProbeIngressAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrIngressNoAddress) {
+				t.Errorf("ProbeIngressAddress() error = %v, want ErrIngressNoAddress", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProbeIngressAddress() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PreflightReason is the reason why the domain suffix discovery is expected to
+// time out.
+type PreflightReason string
+
+const (
+	// PreflightReasonNoIngressClass means no IngressClass is installed, so no
+	// controller will ever admit the probe ingress.
+	PreflightReasonNoIngressClass PreflightReason = "NoIngressClass"
+	// PreflightReasonIngressControllerNotRunning means the pods of a recognized
+	// ingress controller were found, but none of them is running.
+	PreflightReasonIngressControllerNotRunning PreflightReason = "IngressControllerNotRunning"
+	// PreflightReasonIngressControllerNotReady means the Deployments and
+	// DaemonSets of the ingress controller were found, but none of their
+	// replicas is ready.
+	PreflightReasonIngressControllerNotReady PreflightReason = "IngressControllerNotReady"
+	// PreflightReasonNoLoadBalancerProvider means nothing in the cluster can
+	// provision a load balancer address for the ingress controller.
+	PreflightReasonNoLoadBalancerProvider PreflightReason = "NoLoadBalancerProvider"
+)
+
+// PreflightError is returned by PreflightDiscovery when the discovery is almost
+// certainly going to time out.
+type PreflightError struct {
+	Reason  PreflightReason
+	Message string
+}
+
+func (e *PreflightError) Error() string {
+	return fmt.Sprintf("domain suffix discovery preflight failed (%s): %s", e.Reason, e.Message)
+}
+
+// ingressControllerPodSelectors are the label selectors of the pods of the
+// recognized ingress controllers, as set by their upstream manifests.
+var ingressControllerPodSelectors = map[IngressControllerType]string{
+	IngressControllerNginx:   "app.kubernetes.io/name=ingress-nginx",
+	IngressControllerContour: "app.kubernetes.io/name=contour",
+	IngressControllerTraefik: "app.kubernetes.io/name=traefik",
+	IngressControllerHAProxy: "app.kubernetes.io/name=kubernetes-ingress",
+	IngressControllerALB:     "app.kubernetes.io/name=aws-load-balancer-controller",
+	IngressControllerIstio:   "app=istio-ingressgateway",
+}
+
+// loadBalancerProviderPodSelectors match the pods of the in-cluster load balancer
+// implementations used on clusters without a cloud provider.
+var loadBalancerProviderPodSelectors = []string{
+	"app.kubernetes.io/name=metallb",
+	"app=metallb",
+	"app.kubernetes.io/name=kube-vip",
+	"component=cloud-controller-manager",
+	"k8s-app=cloud-controller-manager",
+}
+
+// PreflightDiscovery checks the cluster for strong signals that the domain suffix
+// discovery for the given ingress class (or the default class, when nil) is going
+// to wait out its whole timeout, and returns a *PreflightError if so.
+//
+// It is deliberately conservative: a signal that can't be read (e.g. because of
+// RBAC) is skipped rather than treated as a failure.
+func PreflightDiscovery(ctx context.Context, cliset kubernetes.Interface, className *string) error {
+	classes, err := ListIngressClasses(ctx, cliset)
+	if err != nil {
+		contextLogger(ctx).Error(err, "Skipping the ingress class preflight check")
+		return nil
+	}
+	if len(classes) == 0 {
+		return &PreflightError{
+			Reason:  PreflightReasonNoIngressClass,
+			Message: "no ingress classes are installed in the cluster, install an ingress controller first",
+		}
+	}
+
+	controllerType := IngressControllerUnknown
+	for _, class := range classes {
+		if (className != nil && class.Name == *className) || (className == nil && class.IsDefault) {
+			controllerType = DetectIngressControllerType(class.Controller)
+			break
+		}
+	}
+
+	selector, ok := ingressControllerPodSelectors[controllerType]
+	if !ok {
+		return nil
+	}
+
+	pods, err := cliset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		contextLogger(ctx).Error(err, "Skipping the ingress controller preflight check")
+		return nil
+	}
+	if len(pods.Items) == 0 {
+		// The controller may be deployed with non-standard labels.
+		return nil
+	}
+
+	running := false
+	hostNetwork := false
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			running = true
+		}
+		if pod.Spec.HostNetwork {
+			hostNetwork = true
+		}
+	}
+	if !running {
+		return &PreflightError{
+			Reason:  PreflightReasonIngressControllerNotRunning,
+			Message: fmt.Sprintf("none of the %d %s ingress controller pods (%s) is running", len(pods.Items), controllerType, selector),
+		}
+	}
+
+	if controllerType == IngressControllerALB || hostNetwork {
+		return nil
+	}
+
+	if hasLoadBalancerProvider(ctx, cliset) {
+		return nil
+	}
+
+	return &PreflightError{
+		Reason:  PreflightReasonNoLoadBalancerProvider,
+		Message: "no node has a cloud provider ID and no cloud-controller-manager or in-cluster load balancer (MetalLB, kube-vip) is running",
+	}
+}
+
+// hasLoadBalancerProvider reports whether anything in the cluster may provision
+// load balancer addresses, erring on the side of true.
+func hasLoadBalancerProvider(ctx context.Context, cliset kubernetes.Interface) bool {
+	nodes, err := cliset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		contextLogger(ctx).Error(err, "Skipping the load balancer provider preflight check")
+		return true
+	}
+	for _, node := range nodes.Items {
+		if node.Spec.ProviderID != "" {
+			return true
+		}
+	}
+
+	for _, selector := range loadBalancerProviderPodSelectors {
+		pods, err := cliset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector, Limit: 1})
+		if err != nil {
+			contextLogger(ctx).Error(err, "Skipping the load balancer provider preflight check")
+			return true
+		}
+		if len(pods.Items) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkCanCreateIngresses checks with a SelfSubjectAccessReview that the operator
+// may create and delete ingresses in the namespace, before a probe is created
+// there that it might not be able to clean up.
+func checkCanCreateIngresses(ctx context.Context, cliset kubernetes.Interface, namespace string) error {
+	for _, verb := range []string{"create", "delete"} {
+		review, err := cliset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Group:     networkingv1.GroupName,
+					Resource:  "ingresses",
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to review the access to the ingresses of namespace %s", namespace)
+		}
+		if !review.Status.Allowed {
+			return errors.Errorf("not allowed to %s the ingresses of namespace %s: %s", verb, namespace, review.Status.Reason)
+		}
+	}
+	return nil
+}
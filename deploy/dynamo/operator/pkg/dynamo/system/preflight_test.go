@@ -0,0 +1,174 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func newPreflightPod(name string, labels map[string]string, phase corev1.PodPhase, hostNetwork bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ingress", Labels: labels},
+		Spec:       corev1.PodSpec{HostNetwork: hostNetwork},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+// forbidList makes the list of the resource fail as if RBAC denied it.
+func forbidList(cliset *fake.Clientset, resource string) {
+	cliset.PrependReactor("list", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8serrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("RBAC"))
+	})
+}
+
+func TestPreflightDiscovery(t *testing.T) {
+	nginxClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+	defaultNginxClass := nginxClass.DeepCopy()
+	defaultNginxClass.Annotations = map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"}
+	unknownClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "example.com/ingress"},
+	}
+	nginxLabels := map[string]string{"app.kubernetes.io/name": "ingress-nginx"}
+	runningPod := newPreflightPod("ingress-nginx", nginxLabels, corev1.PodRunning, false)
+	pendingPod := newPreflightPod("ingress-nginx", nginxLabels, corev1.PodPending, false)
+	providerNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+		Spec:       corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-0123"},
+	}
+	metalLBPod := newPreflightPod("metallb-speaker", map[string]string{"app.kubernetes.io/name": "metallb"}, corev1.PodRunning, false)
+
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		forbidden  []string
+		className  *string
+		wantReason PreflightReason
+	}{
+		{
+			name:       "no ingress class",
+			className:  ptr.To("nginx"),
+			wantReason: PreflightReasonNoIngressClass,
+		},
+		{
+			name:      "ingress classes forbidden",
+			forbidden: []string{"ingressclasses"},
+			className: ptr.To("nginx"),
+		},
+		{
+			name:      "unrecognized controller",
+			objects:   []runtime.Object{unknownClass, pendingPod},
+			className: ptr.To("nginx"),
+		},
+		{
+			name:      "no controller pod",
+			objects:   []runtime.Object{nginxClass},
+			className: ptr.To("nginx"),
+		},
+		{
+			name:      "pods forbidden",
+			objects:   []runtime.Object{nginxClass, pendingPod},
+			forbidden: []string{"pods"},
+			className: ptr.To("nginx"),
+		},
+		{
+			name:       "controller not running",
+			objects:    []runtime.Object{nginxClass, pendingPod},
+			className:  ptr.To("nginx"),
+			wantReason: PreflightReasonIngressControllerNotRunning,
+		},
+		{
+			name:       "default class controller not running",
+			objects:    []runtime.Object{defaultNginxClass, pendingPod},
+			wantReason: PreflightReasonIngressControllerNotRunning,
+		},
+		{
+			name:       "no load balancer provider",
+			objects:    []runtime.Object{nginxClass, runningPod},
+			className:  ptr.To("nginx"),
+			wantReason: PreflightReasonNoLoadBalancerProvider,
+		},
+		{
+			name:      "host network",
+			objects:   []runtime.Object{nginxClass, newPreflightPod("ingress-nginx", nginxLabels, corev1.PodRunning, true)},
+			className: ptr.To("nginx"),
+		},
+		{
+			name:      "cloud provider node",
+			objects:   []runtime.Object{nginxClass, runningPod, providerNode},
+			className: ptr.To("nginx"),
+		},
+		{
+			name:      "in-cluster load balancer",
+			objects:   []runtime.Object{nginxClass, runningPod, metalLBPod},
+			className: ptr.To("nginx"),
+		},
+		{
+			name:      "nodes forbidden",
+			objects:   []runtime.Object{nginxClass, runningPod},
+			forbidden: []string{"nodes"},
+			className: ptr.To("nginx"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := fake.NewSimpleClientset(tt.objects...)
+			for _, resource := range tt.forbidden {
+				forbidList(cliset, resource)
+			}
+
+			err := PreflightDiscovery(context.Background(), cliset, tt.className)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("PreflightDiscovery() error = %v, want nil", err)
+				}
+				return
+			}
+			var preflightErr *PreflightError
+			if !errors.As(err, &preflightErr) || preflightErr.Reason != tt.wantReason {
+				t.Errorf("PreflightDiscovery() error = %v, want a %s PreflightError", err, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestParseDiscoveryConfigPreflightDefault(t *testing.T) {
+	config, err := parseDiscoveryConfig(newNetworkConfigMap(nil))
+	if err != nil {
+		t.Fatalf("parseDiscoveryConfig() error = %v", err)
+	}
+	if config.Preflight {
+		t.Error("Preflight = true, want it off by default")
+	}
+}
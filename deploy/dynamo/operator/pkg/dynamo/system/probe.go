@@ -0,0 +1,166 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultProbeHostSuffix is the domain under which the probe ingress host is
+// generated, unless another one is set in the network config. It is in the
+// `.internal` TLD reserved for private use, so it never resolves publicly.
+const DefaultProbeHostSuffix = "domain-suffix-probe.dynamo.internal"
+
+// DefaultProbeBackendService is the service the probe routes to, unless another
+// one is set in the network config. It doesn't need to exist for most ingress
+// controllers, the others need the probe pointed at one that does, e.g. the
+// `kubernetes` service when the operator runs in the default namespace: the
+// backend of an ingress must be in its namespace.
+const DefaultProbeBackendService = "default-domain-service"
+
+// IngressClassMode is where the ingress class is set on the probe ingress, as
+// set by the discovery-ingress-class-mode key of the network config.
+type IngressClassMode string
+
+const (
+	// IngressClassModeSpec, the default, sets spec.ingressClassName.
+	IngressClassModeSpec IngressClassMode = "spec"
+	// IngressClassModeAnnotation sets the legacy `kubernetes.io/ingress.class`
+	// annotation instead, for the controllers predating IngressClasses.
+	IngressClassModeAnnotation IngressClassMode = "annotation"
+)
+
+// probeBackendPort returns the port of the probe backend, by name if one is set.
+func (c *discoveryConfig) probeBackendPort() networkingv1.ServiceBackendPort {
+	if c.ProbeBackendPortName != "" {
+		return networkingv1.ServiceBackendPort{Name: c.ProbeBackendPortName}
+	}
+	return networkingv1.ServiceBackendPort{Number: c.ProbeBackendPort}
+}
+
+// lookupProbeBackendPort returns the discovery config with the probe backend
+// port set to the one of the probe backend service in the namespace, if
+// ProbeBackendPortLookup is set, so that the probe references a real port. The
+// config is returned as is if no service is configured, as the default one
+// usually doesn't exist, or if it can't be looked up.
+func lookupProbeBackendPort(ctx context.Context, logger logr.Logger, cliset kubernetes.Interface, namespace string, config *discoveryConfig) *discoveryConfig {
+	if !config.ProbeBackendPortLookup || config.ProbeBackendService == DefaultProbeBackendService {
+		return config
+	}
+	logger = logger.WithValues("service", config.ProbeBackendService)
+	service, err := cliset.CoreV1().Services(namespace).Get(ctx, config.ProbeBackendService, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "Failed to look up the probe backend service, falling back to the configured port", "port", config.probeBackendPort())
+		return config
+	}
+	for _, port := range service.Spec.Ports {
+		if config.ProbeBackendPortName != "" && port.Name != config.ProbeBackendPortName {
+			continue
+		}
+		logger.V(1).Info("Using the port of the probe backend service", "port", port.Port)
+		lookedUp := *config
+		lookedUp.ProbeBackendPort = port.Port
+		lookedUp.ProbeBackendPortName = ""
+		return &lookedUp
+	}
+	logger.Info("The probe backend service has no matching port, falling back to the configured one", "port", config.probeBackendPort())
+	return config
+}
+
+// ProbeCreateError is returned when the probe of the discovery, of the Kind
+// `ingress`, `gateway` or `httproute`, can't be created.
+type ProbeCreateError struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+func (e *ProbeCreateError) Error() string {
+	return "failed to create " + e.Kind + " " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *ProbeCreateError) Unwrap() error {
+	return e.Err
+}
+
+// probeHostHashLength is the number of hex characters of the hash that replaces
+// a probe host label that is too long.
+const probeHostHashLength = 16
+
+// deterministicProbeHostLabel returns the probe host label derived from the
+// namespace and the seed.
+func deterministicProbeHostLabel(namespace, seed string) string {
+	sum := sha256.Sum256([]byte(namespace + "/" + seed))
+	return "p" + hex.EncodeToString(sum[:])[:probeHostHashLength]
+}
+
+// buildProbeHost returns `<name>.<suffix>`, replacing the name with a short stable
+// hash when it isn't a valid DNS label or makes the host exceed the DNS length
+// limit.
+func buildProbeHost(logger logr.Logger, name, suffix string) (string, error) {
+	name = strings.ToLower(name)
+	host := joinDomain(name, suffix)
+	if len(validation.IsDNS1123Label(name)) == 0 && len(host) <= validation.DNS1123SubdomainMaxLength {
+		return host, nil
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hashed := "p" + hex.EncodeToString(sum[:])[:probeHostHashLength]
+	host = joinDomain(hashed, suffix)
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+		return "", errors.Errorf("the probe host %s is invalid even with a hashed name: %s", host, strings.Join(errs, ", "))
+	}
+
+	logger.Info("The probe host is too long or invalid, so it was shortened", "name", name, "host", host)
+	return host, nil
+}
+
+// maxProbeHosts bounds the number of probe ingresses of a discovery.
+const maxProbeHosts = 16
+
+// probeIngressShards returns n copies of the probe ingress with distinct hosts
+// and name prefixes, the first one being the probe itself, so that they land on
+// different shards of a controller sharded by host.
+func probeIngressShards(logger logr.Logger, probe *networkingv1.Ingress, n int) ([]*networkingv1.Ingress, error) {
+	shards := []*networkingv1.Ingress{probe}
+	for i := 1; i < n; i++ {
+		shard := probe.DeepCopy()
+		shard.GenerateName = probe.GenerateName + strconv.Itoa(i) + "-"
+		for j := range shard.Spec.Rules {
+			label, suffix, _ := strings.Cut(shard.Spec.Rules[j].Host, ".")
+			host, err := buildProbeHost(logger, label+"-"+strconv.Itoa(i), suffix)
+			if err != nil {
+				return nil, err
+			}
+			shard.Spec.Rules[j].Host = host
+		}
+		shards = append(shards, shard)
+	}
+	return shards, nil
+}
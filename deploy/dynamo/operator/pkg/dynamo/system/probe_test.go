@@ -0,0 +1,740 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestRenderProbeHostSuffix(t *testing.T) {
+	t.Setenv("POD_NAME", "dynamo-operator-0")
+
+	tests := []struct {
+		name       string
+		suffix     string
+		wantSuffix string
+		wantErr    bool
+	}{
+		{name: "default", wantSuffix: "." + DefaultProbeHostSuffix},
+		{name: "configured", suffix: "probe.example.com", wantSuffix: ".probe.example.com"},
+		{name: "trailing dot", suffix: "probe.example.com.", wantSuffix: ".probe.example.com"},
+		{name: "invalid", suffix: "probe_example.com", wantErr: true},
+		{name: "uppercase", suffix: "Probe.Example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSuffix: tt.suffix,
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDiscoveryConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			host, err := renderProbeHost(logr.Discard(), GetNamespace(), config)
+			if err != nil {
+				t.Fatalf("renderProbeHost() error = %v", err)
+			}
+			if host != "dynamo-operator-0"+tt.wantSuffix {
+				t.Errorf("renderProbeHost() = %q, want %q", host, "dynamo-operator-0"+tt.wantSuffix)
+			}
+			if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+				t.Errorf("renderProbeHost() = %q is not a valid host: %s", host, strings.Join(errs, ", "))
+			}
+		})
+	}
+}
+
+func TestGetIngressIPProbeBackend(t *testing.T) {
+	tests := []struct {
+		name         string
+		service      string
+		port         string
+		portName     string
+		wantService  string
+		wantPort     int32
+		wantPortName string
+		wantErr      bool
+	}{
+		{
+			name:        "default",
+			wantService: DefaultProbeBackendService,
+			wantPort:    consts.BentoServicePort,
+		},
+		{
+			name:        "custom",
+			service:     "kubernetes",
+			port:        "443",
+			wantService: "kubernetes",
+			wantPort:    443,
+		},
+		{
+			name:         "named port",
+			portName:     "http",
+			wantService:  DefaultProbeBackendService,
+			wantPortName: "http",
+		},
+		{
+			name:    "invalid service",
+			service: "Not_A_Service",
+			wantErr: true,
+		},
+		{
+			name:     "invalid port name",
+			portName: "HTTP_PORT",
+			wantErr:  true,
+		},
+		{
+			name:     "both port number and name",
+			port:     "8080",
+			portName: "http",
+			wantErr:  true,
+		},
+		{
+			name:    "invalid port",
+			port:    "70000",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+			var created *networkingv1.Ingress
+			cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				created = action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress).DeepCopy()
+				return false, nil, nil
+			})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:                  "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendService:  tt.service,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort:     tt.port,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName: tt.portName,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:         "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately:      "true",
+			})
+
+			_, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIngressIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if created == nil {
+				t.Fatal("no probe ingress was created")
+			}
+			backend := created.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+			wantPort := networkingv1.ServiceBackendPort{Name: tt.wantPortName, Number: tt.wantPort}
+			if backend.Name != tt.wantService || backend.Port != wantPort {
+				t.Errorf("probe backend = %s %+v, want %s %+v", backend.Name, backend.Port, tt.wantService, wantPort)
+			}
+		})
+	}
+}
+
+func TestRenderProbeHostSeed(t *testing.T) {
+	t.Setenv("POD_NAME", "")
+
+	render := func(namespace, seed string) string {
+		t.Helper()
+		config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+			consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSeed: seed,
+		}))
+		if err != nil {
+			t.Fatalf("parseDiscoveryConfig() error = %v", err)
+		}
+		host, err := renderProbeHost(logr.Discard(), namespace, config)
+		if err != nil {
+			t.Fatalf("renderProbeHost() error = %v", err)
+		}
+		return host
+	}
+
+	first, second := render("team-a", "probe"), render("team-a", "probe")
+	if first != second {
+		t.Errorf("renderProbeHost() with a seed = %q then %q, want the same host", first, second)
+	}
+	if errs := validation.IsDNS1123Subdomain(first); len(errs) > 0 {
+		t.Errorf("renderProbeHost() = %q is not a valid host: %s", first, strings.Join(errs, ", "))
+	}
+	if other := render("team-b", "probe"); other == first {
+		t.Errorf("renderProbeHost() = %q for two namespaces, want distinct hosts", other)
+	}
+	if random, again := render("team-a", ""), render("team-a", ""); random == again {
+		t.Errorf("renderProbeHost() without a seed = %q twice, want random hosts", random)
+	}
+}
+
+func TestRenderProbeIngressCatchAll(t *testing.T) {
+	for _, catchAll := range []bool{false, true} {
+		t.Run(strconv.FormatBool(catchAll), func(t *testing.T) {
+			config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeCatchAll: strconv.FormatBool(catchAll),
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSeed: "probe",
+			}))
+			if err != nil {
+				t.Fatalf("parseDiscoveryConfig() error = %v", err)
+			}
+			if config.ProbeCatchAll != catchAll {
+				t.Fatalf("ProbeCatchAll = %v, want %v", config.ProbeCatchAll, catchAll)
+			}
+
+			probe, err := renderProbeIngress(logr.Discard(), GetNamespace(), "test", &IngressConfig{Annotations: map[string]string{}}, config, IngressControllerUnknown)
+			if err != nil {
+				t.Fatalf("renderProbeIngress() error = %v", err)
+			}
+			if host := probe.Spec.Rules[0].Host; (host == "") != catchAll {
+				t.Errorf("probe host = %q, want a host only without a catch-all rule", host)
+			}
+		})
+	}
+}
+
+func TestGetIngressIPProbePath(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		pathType     string
+		wantPath     string
+		wantPathType networkingv1.PathType
+	}{
+		{
+			name:         "controller default",
+			wantPath:     "/",
+			wantPathType: controllerPreferredPathTypes[IngressControllerNginx],
+		},
+		{
+			name:         "configured",
+			path:         "/api",
+			pathType:     string(networkingv1.PathTypeExact),
+			wantPath:     "/api",
+			wantPathType: networkingv1.PathTypeExact,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+			var created *networkingv1.Ingress
+			cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				created = action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress).DeepCopy()
+				return false, nil, nil
+			})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+				consts.KubeConfigMapKeyNetworkConfigIngressPath:              tt.path,
+				consts.KubeConfigMapKeyNetworkConfigIngressPathType:          tt.pathType,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+			})
+
+			if _, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset); err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if created == nil {
+				t.Fatal("no probe ingress was created")
+			}
+			path := created.Spec.Rules[0].HTTP.Paths[0]
+			if path.Path != tt.wantPath || path.PathType == nil || *path.PathType != tt.wantPathType {
+				t.Errorf("probe path = %q %v, want %q %q", path.Path, path.PathType, tt.wantPath, tt.wantPathType)
+			}
+		})
+	}
+}
+
+func TestRenderProbeIngressPathFallback(t *testing.T) {
+	config, err := parseDiscoveryConfig(newNetworkConfigMap(nil))
+	if err != nil {
+		t.Fatalf("parseDiscoveryConfig() error = %v", err)
+	}
+	probe, err := renderProbeIngress(logr.Discard(), GetNamespace(), "test", &IngressConfig{Annotations: map[string]string{}}, config, IngressControllerUnknown)
+	if err != nil {
+		t.Fatalf("renderProbeIngress() error = %v", err)
+	}
+	path := probe.Spec.Rules[0].HTTP.Paths[0]
+	if path.Path != "/" || path.PathType == nil || *path.PathType != networkingv1.PathTypeImplementationSpecific {
+		t.Errorf("probe path = %q %v, want / ImplementationSpecific", path.Path, path.PathType)
+	}
+}
+
+func TestRenderProbeIngressAnnotationTemplates(t *testing.T) {
+	config, err := parseDiscoveryConfig(newNetworkConfigMap(nil))
+	if err != nil {
+		t.Fatalf("parseDiscoveryConfig() error = %v", err)
+	}
+	ingressConfig := &IngressConfig{Annotations: map[string]string{"example.com/owner": "{{ .Namespace }}/{{ .IngressName }}"}}
+	probe, err := renderProbeIngress(logr.Discard(), "team-a", "test", ingressConfig, config, IngressControllerUnknown)
+	if err != nil {
+		t.Fatalf("renderProbeIngress() error = %v", err)
+	}
+	if got, want := probe.Annotations["example.com/owner"], "team-a/"+probeIngressGenerateName; got != want {
+		t.Errorf("probe annotation = %q, want %q", got, want)
+	}
+	if got := ingressConfig.Annotations["example.com/owner"]; got != "{{ .Namespace }}/{{ .IngressName }}" {
+		t.Errorf("the ingress config annotations were modified: %q", got)
+	}
+}
+
+func TestRenderProbeIngressReservedAnnotations(t *testing.T) {
+	var lines []map[string]interface{}
+	base := funcr.New(func(prefix, args string) {}, funcr.Options{})
+	logger := logr.New(&recordingSink{LogSink: base.GetSink(), lines: &lines})
+
+	config, err := parseDiscoveryConfig(newNetworkConfigMap(nil))
+	if err != nil {
+		t.Fatalf("parseDiscoveryConfig() error = %v", err)
+	}
+	ingressConfig := &IngressConfig{Annotations: map[string]string{
+		consts.KubeAnnotationDynamoDiscoveryCorrelationID: "admin",
+		"example.com/owner": "admin",
+	}}
+	probe, err := renderProbeIngress(logger, GetNamespace(), "test-correlation", ingressConfig, config, IngressControllerUnknown)
+	if err != nil {
+		t.Fatalf("renderProbeIngress() error = %v", err)
+	}
+	if got := probe.Annotations[consts.KubeAnnotationDynamoDiscoveryCorrelationID]; got != "test-correlation" {
+		t.Errorf("the correlation ID annotation = %q, want the operator value test-correlation", got)
+	}
+	if got := probe.Annotations["example.com/owner"]; got != "admin" {
+		t.Errorf("the unreserved annotation = %q, want it kept", got)
+	}
+
+	warned := false
+	for _, line := range lines {
+		if conflicts, ok := line["annotations"].([]string); ok && reflect.DeepEqual(conflicts, []string{consts.KubeAnnotationDynamoDiscoveryCorrelationID}) {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("renderProbeIngress() logged %v, want a warning about the reserved annotation", lines)
+	}
+}
+
+func TestRenderProbeIngressLabels(t *testing.T) {
+	for _, reuse := range []string{"false", "true"} {
+		config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+			consts.KubeConfigMapKeyNetworkConfigDiscoveryReuseProbe: reuse,
+		}))
+		if err != nil {
+			t.Fatalf("parseDiscoveryConfig() error = %v", err)
+		}
+		probe, err := renderProbeIngress(logr.Discard(), GetNamespace(), "test", &IngressConfig{Annotations: map[string]string{}}, config, IngressControllerUnknown)
+		if err != nil {
+			t.Fatalf("renderProbeIngress() error = %v", err)
+		}
+		if got := probe.Labels[consts.KubeLabelManagedBy]; got != "dynamo-operator" {
+			t.Errorf("probe label %s with reuse %s = %q, want dynamo-operator", consts.KubeLabelManagedBy, reuse, got)
+		}
+		if got := probe.Labels[consts.KubeLabelDynamoComponent]; got != "domain-probe" {
+			t.Errorf("probe label %s with reuse %s = %q, want domain-probe", consts.KubeLabelDynamoComponent, reuse, got)
+		}
+		if _, ok := probe.Labels[consts.KubeLabelDynamoPurpose]; ok != (reuse == "true") {
+			t.Errorf("probe labels with reuse %s = %v, want the %s label only when reused", reuse, probe.Labels, consts.KubeLabelDynamoPurpose)
+		}
+	}
+}
+
+func TestGetIngressIPProbeOwner(t *testing.T) {
+	owner := metav1.OwnerReference{
+		APIVersion:         "nvidia.com/v1alpha1",
+		Kind:               "DynamoNimDeployment",
+		Name:               "my-deployment",
+		UID:                types.UID("1d6b6a4e-0b1e-4a4b-9d8e-0d5f3c1a2b3c"),
+		Controller:         ptr.To(true),
+		BlockOwnerDeletion: ptr.To(true),
+	}
+
+	for _, withOwner := range []bool{true, false} {
+		cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+		var created *networkingv1.Ingress
+		cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			created = action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress).DeepCopy()
+			return false, nil, nil
+		})
+		configMap := newNetworkConfigMap(map[string]string{
+			consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+			consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+		})
+
+		ctx := context.Background()
+		if withOwner {
+			ctx = WithProbeOwner(ctx, owner)
+		}
+		if _, err := GetIngressIP(ctx, staticConfigMapGetter(configMap), cliset); err != nil {
+			t.Fatalf("GetIngressIP() error = %v", err)
+		}
+		if created == nil {
+			t.Fatal("no probe ingress was created")
+		}
+
+		if !withOwner {
+			if len(created.OwnerReferences) != 0 {
+				t.Errorf("probe owner references = %v, want none", created.OwnerReferences)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(created.OwnerReferences, []metav1.OwnerReference{owner}) {
+			t.Fatalf("probe owner references = %v, want %v", created.OwnerReferences, owner)
+		}
+		// The garbage collector deletes the dependents of a deleted owner by
+		// its UID, and the foreground deletion of the owner waits for them.
+		ref := created.OwnerReferences[0]
+		if ref.UID != owner.UID || ref.BlockOwnerDeletion == nil || !*ref.BlockOwnerDeletion {
+			t.Errorf("probe owner reference = %v, want one cascading the deletion of %s", ref, owner.UID)
+		}
+	}
+}
+
+func TestGetIngressIPProbeHosts(t *testing.T) {
+	t.Setenv("POD_NAME", "dynamo-operator-0")
+
+	// Only the shard of the second host assigns an address.
+	cliset := newLoadBalancerClientset()
+	var hosts []string
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress).DeepCopy()
+		ing.Name = ing.GenerateName + "test"
+		hosts = append(hosts, ing.Spec.Rules[0].Host)
+		if len(hosts) == 2 {
+			ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.2"}}
+		}
+		return true, ing, cliset.Tracker().Add(ing)
+	})
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts:   "3",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.2" {
+		t.Errorf("GetIngressIP() = %q, want the address of the second probe 10.0.0.2", ip)
+	}
+
+	want := []string{
+		"dynamo-operator-0." + DefaultProbeHostSuffix,
+		"dynamo-operator-0-1." + DefaultProbeHostSuffix,
+		"dynamo-operator-0-2." + DefaultProbeHostSuffix,
+	}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("probe hosts = %v, want %v", hosts, want)
+	}
+
+	left, err := cliset.NetworkingV1().Ingresses(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list ingresses: %v", err)
+	}
+	if len(left.Items) != 0 {
+		t.Errorf("%d probe ingresses left, want all of them deleted", len(left.Items))
+	}
+}
+
+func TestParseDiscoveryConfigProbeHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    int
+		wantErr bool
+	}{
+		{name: "default", want: 1},
+		{name: "zero", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts: "0"}, want: 1},
+		{name: "configured", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts: "4"}, want: 4},
+		{name: "too many", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts: "17"}, wantErr: true},
+		{name: "negative", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts: "-1"}, wantErr: true},
+		{
+			name: "catch-all",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts:    "2",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeCatchAll: "true",
+			},
+			wantErr: true,
+		},
+		{
+			name: "persistent probe",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHosts:      "2",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPersistentProbe: "true",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseDiscoveryConfig(newNetworkConfigMap(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDiscoveryConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && config.ProbeHosts != tt.want {
+				t.Errorf("ProbeHosts = %d, want %d", config.ProbeHosts, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderProbeIngressClassMode(t *testing.T) {
+	className := "nginx"
+	tests := []struct {
+		mode           string
+		wantSpec       *string
+		wantAnnotation string
+	}{
+		{mode: "", wantSpec: &className},
+		{mode: "spec", wantSpec: &className},
+		{mode: "annotation", wantAnnotation: className},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassMode: tt.mode,
+			}))
+			if err != nil {
+				t.Fatalf("parseDiscoveryConfig() error = %v", err)
+			}
+			ingressConfig := &IngressConfig{ClassName: &className, Annotations: map[string]string{}}
+			probe, err := renderProbeIngress(logr.Discard(), GetNamespace(), "test", ingressConfig, config, IngressControllerUnknown)
+			if err != nil {
+				t.Fatalf("renderProbeIngress() error = %v", err)
+			}
+			if !reflect.DeepEqual(probe.Spec.IngressClassName, tt.wantSpec) {
+				t.Errorf("probe spec.ingressClassName = %v, want %v", probe.Spec.IngressClassName, tt.wantSpec)
+			}
+			if got := probe.Annotations[consts.KubeAnnotationIngressClass]; got != tt.wantAnnotation {
+				t.Errorf("probe annotation %s = %q, want %q", consts.KubeAnnotationIngressClass, got, tt.wantAnnotation)
+			}
+		})
+	}
+}
+
+func TestParseDiscoveryConfigIngressClassModeInvalid(t *testing.T) {
+	_, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryIngressClassMode: "label",
+	}))
+	if err == nil || !strings.Contains(err.Error(), `invalid discovery-ingress-class-mode "label"`) {
+		t.Errorf("parseDiscoveryConfig() error = %v, want an invalid discovery-ingress-class-mode", err)
+	}
+}
+
+func TestGetIngressIPProbeBackendPortLookup(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: GetNamespace()},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "metrics", Port: 9090},
+			{Name: "http", Port: 8080},
+		}},
+	}
+	tests := []struct {
+		name     string
+		service  string
+		portName string
+		lookup   string
+		want     networkingv1.ServiceBackendPort
+	}{
+		{
+			name:    "first port",
+			service: "backend",
+			lookup:  "true",
+			want:    networkingv1.ServiceBackendPort{Number: 9090},
+		},
+		{
+			name:     "named port",
+			service:  "backend",
+			portName: "http",
+			lookup:   "true",
+			want:     networkingv1.ServiceBackendPort{Number: 8080},
+		},
+		{
+			name:    "without lookup",
+			service: "backend",
+			want:    networkingv1.ServiceBackendPort{Number: consts.BentoServicePort},
+		},
+		{
+			name:    "missing service",
+			service: "missing",
+			lookup:  "true",
+			want:    networkingv1.ServiceBackendPort{Number: consts.BentoServicePort},
+		},
+		{
+			name:     "missing port",
+			service:  "backend",
+			portName: "grpc",
+			lookup:   "true",
+			want:     networkingv1.ServiceBackendPort{Name: "grpc"},
+		},
+		{
+			name:   "no service configured",
+			lookup: "true",
+			want:   networkingv1.ServiceBackendPort{Number: consts.BentoServicePort},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+			if err := cliset.Tracker().Add(service.DeepCopy()); err != nil {
+				t.Fatalf("failed to add the backend service: %v", err)
+			}
+			var created *networkingv1.Ingress
+			cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				created = action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress).DeepCopy()
+				return false, nil, nil
+			})
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:                    "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendService:    tt.service,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortName:   tt.portName,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPortLookup: tt.lookup,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:           "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately:        "true",
+			})
+
+			if _, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset); err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if created == nil {
+				t.Fatal("no probe ingress was created")
+			}
+			if got := created.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port; got != tt.want {
+				t.Errorf("probe backend port = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderProbeIngressYAML(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:              "nginx",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations:        `{"example.com/owner": "team-a"}`,
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeHostSuffix:  "probe.example.com",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryProbeBackendPort: "8080",
+	})
+	out, err := RenderProbeIngressYAML(context.Background(), staticConfigMapGetter(configMap))
+	if err != nil {
+		t.Fatalf("RenderProbeIngressYAML() error = %v", err)
+	}
+	for _, want := range []string{
+		"kind: Ingress",
+		"ingressClassName: nginx",
+		"example.com/owner: team-a",
+		".probe.example.com",
+		"number: 8080",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("RenderProbeIngressYAML() = %s, want it to contain %q", out, want)
+		}
+	}
+}
+
+func TestBuildProbeHost(t *testing.T) {
+	longSuffix := strings.Repeat(strings.Repeat("s", 60)+".", 5) + "io"
+	tests := []struct {
+		name       string
+		label      string
+		suffix     string
+		wantHost   string
+		wantHashed bool
+		wantErr    bool
+	}{
+		{
+			name:     "valid",
+			label:    "Probe-ABC",
+			suffix:   "example.com",
+			wantHost: "probe-abc.example.com",
+		},
+		{
+			name:       "over-long label",
+			label:      strings.Repeat("a", 64),
+			suffix:     "example.com",
+			wantHashed: true,
+		},
+		{
+			name:       "invalid label",
+			label:      "probe_abc",
+			suffix:     "example.com",
+			wantHashed: true,
+		},
+		{
+			name:       "over-long host",
+			label:      strings.Repeat("a", 63),
+			suffix:     strings.Repeat(strings.Repeat("s", 60)+".", 3) + "example.io",
+			wantHashed: true,
+		},
+		{
+			name:    "suffix already too long",
+			label:   "probe",
+			suffix:  longSuffix,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, err := buildProbeHost(logr.Discard(), tt.label, tt.suffix)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("buildProbeHost() = %q, want an error", host)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildProbeHost() error = %v", err)
+			}
+			if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+				t.Errorf("buildProbeHost() = %q, which is invalid: %v", host, errs)
+			}
+			if !strings.HasSuffix(host, "."+tt.suffix) {
+				t.Errorf("buildProbeHost() = %q, want it under %s", host, tt.suffix)
+			}
+			if tt.wantHost != "" && host != tt.wantHost {
+				t.Errorf("buildProbeHost() = %q, want %q", host, tt.wantHost)
+			}
+			if label, _, _ := strings.Cut(host, "."); tt.wantHashed && len(label) != probeHostHashLength+1 {
+				t.Errorf("buildProbeHost() = %q, want the label replaced by a hash", host)
+			}
+			// The hash is stable.
+			if again, _ := buildProbeHost(logr.Discard(), tt.label, tt.suffix); again != host {
+				t.Errorf("buildProbeHost() again = %q, want %q", again, host)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ingressStatusConditions holds the status conditions some controllers set on
+// ingresses, which the typed networking/v1 IngressStatus doesn't model.
+type ingressStatusConditions struct {
+	Status struct {
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status"`
+}
+
+// getIngressWithConditions gets the ingress through the raw REST client so that
+// the status conditions aren't dropped when decoding into the typed object.
+func getIngressWithConditions(ctx context.Context, cliset kubernetes.Interface, namespace, name string) (ing *networkingv1.Ingress, conditions []metav1.Condition, err error) {
+	raw, err := cliset.NetworkingV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("ingresses").
+		Name(name).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return
+	}
+
+	ing = &networkingv1.Ingress{}
+	if err = json.Unmarshal(raw, ing); err != nil {
+		err = errors.Wrapf(err, "failed to decode ingress %s", name)
+		return
+	}
+
+	var status ingressStatusConditions
+	if err = json.Unmarshal(raw, &status); err != nil {
+		err = errors.Wrapf(err, "failed to decode the status conditions of ingress %s", name)
+		return
+	}
+	conditions = status.Status.Conditions
+
+	return
+}
+
+// ReadinessChecker tells whether a probe ingress is programmed, for the ingress
+// controllers that don't all signal it the same way.
+type ReadinessChecker interface {
+	// Ready reports whether the ingress is programmed, and returns the load
+	// balancer entries its address is read from.
+	Ready(ing *networkingv1.Ingress) (entries []networkingv1.IngressLoadBalancerIngress, ready bool)
+}
+
+// LoadBalancerStatusChecker is the ReadinessChecker of the controllers that set
+// the load balancer status of the ingress, such as nginx, and the default one.
+type LoadBalancerStatusChecker struct{}
+
+func (LoadBalancerStatusChecker) Ready(ing *networkingv1.Ingress) ([]networkingv1.IngressLoadBalancerIngress, bool) {
+	return ing.Status.LoadBalancer.Ingress, hasLoadBalancerAddress(ing.Status.LoadBalancer.Ingress)
+}
+
+// AddressAnnotationChecker is the ReadinessChecker of the AWS ALB controller: the
+// comma-separated IPs or hostnames of the Annotation, if set on the ingress, and
+// otherwise its load balancer status.
+type AddressAnnotationChecker struct {
+	Annotation string
+}
+
+func (c AddressAnnotationChecker) Ready(ing *networkingv1.Ingress) ([]networkingv1.IngressLoadBalancerIngress, bool) {
+	var entries []networkingv1.IngressLoadBalancerIngress
+	if c.Annotation != "" {
+		for _, address := range strings.Split(ing.Annotations[c.Annotation], ",") {
+			if address = strings.TrimSpace(address); address == "" {
+				continue
+			}
+			if net.ParseIP(address) != nil {
+				entries = append(entries, networkingv1.IngressLoadBalancerIngress{IP: address})
+			} else {
+				entries = append(entries, networkingv1.IngressLoadBalancerIngress{Hostname: address})
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return LoadBalancerStatusChecker{}.Ready(ing)
+	}
+	return entries, true
+}
+
+var (
+	readinessCheckersMu sync.RWMutex
+	readinessCheckers   = map[IngressControllerType]ReadinessChecker{}
+)
+
+// SetReadinessChecker replaces the ReadinessChecker of the ingress controller
+// type. A nil checker restores the built-in one.
+func SetReadinessChecker(controllerType IngressControllerType, checker ReadinessChecker) {
+	readinessCheckersMu.Lock()
+	defer readinessCheckersMu.Unlock()
+	if checker == nil {
+		delete(readinessCheckers, controllerType)
+		return
+	}
+	readinessCheckers[controllerType] = checker
+}
+
+// getReadinessChecker returns the ReadinessChecker of the ingress controller
+// type: the one set with SetReadinessChecker, or the built-in one.
+func getReadinessChecker(controllerType IngressControllerType, config *discoveryConfig) ReadinessChecker {
+	readinessCheckersMu.RLock()
+	checker, ok := readinessCheckers[controllerType]
+	readinessCheckersMu.RUnlock()
+	if ok {
+		return checker
+	}
+	if controllerType == IngressControllerALB {
+		return AddressAnnotationChecker{Annotation: config.AddressAnnotation}
+	}
+	return LoadBalancerStatusChecker{}
+}
+
+// readyIngress returns a copy of the ingress with the load balancer entries of
+// the checker as its status, if it is ready.
+func readyIngress(checker ReadinessChecker, ing *networkingv1.Ingress) (*networkingv1.Ingress, bool) {
+	entries, ready := checker.Ready(ing)
+	if !ready {
+		return nil, false
+	}
+	ing = ing.DeepCopy()
+	ing.Status.LoadBalancer.Ingress = entries
+	return ing, true
+}
+
+func isConditionTrue(conditions []metav1.Condition, conditionType string) bool {
+	for _, condition := range conditions {
+		if condition.Type == conditionType {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
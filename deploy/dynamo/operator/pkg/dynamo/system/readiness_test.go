@@ -0,0 +1,90 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestIsConditionTrue(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionTrue},
+		{Type: "Programmed", Status: metav1.ConditionFalse},
+	}
+
+	tests := []struct {
+		conditionType string
+		want          bool
+	}{
+		{conditionType: "Ready", want: true},
+		{conditionType: "Programmed", want: false},
+		{conditionType: "Accepted", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isConditionTrue(conditions, tt.conditionType); got != tt.want {
+			t.Errorf("isConditionTrue(%s) = %v, want %v", tt.conditionType, got, tt.want)
+		}
+	}
+}
+
+func TestGetIngressWithConditions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/networking.k8s.io/v1/namespaces/team-a/ingresses/probe" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind": "Ingress",
+			"metadata": {"name": "probe", "namespace": "team-a"},
+			"status": {
+				"loadBalancer": {"ingress": [{"ip": "192.0.2.10"}]},
+				"conditions": [{"type": "Ready", "status": "True", "reason": "Programmed", "message": "", "lastTransitionTime": "2025-01-01T00:00:00Z"}]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	cliset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewForConfig() error = %v", err)
+	}
+
+	ing, conditions, err := getIngressWithConditions(context.Background(), cliset, "team-a", "probe")
+	if err != nil {
+		t.Fatalf("getIngressWithConditions() error = %v", err)
+	}
+	if ing.Name != "probe" || len(ing.Status.LoadBalancer.Ingress) != 1 || ing.Status.LoadBalancer.Ingress[0].IP != "192.0.2.10" {
+		t.Errorf("getIngressWithConditions() ingress = %+v, want probe with the address 192.0.2.10", ing)
+	}
+	if !isConditionTrue(conditions, "Ready") {
+		t.Errorf("getIngressWithConditions() conditions = %+v, want Ready True", conditions)
+	}
+
+	if _, _, err := getIngressWithConditions(context.Background(), cliset, "team-a", "missing"); err == nil {
+		t.Error("getIngressWithConditions() of a missing ingress succeeded, want an error")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// ResetDomainSuffix removes the domain suffix from the network config and from the
+// status configmap, if any, so that the next GetDomainSuffix discovers it again.
+func ResetDomainSuffix(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) error {
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get configmap %s", networkConfigNameFromContext(ctx))
+	}
+
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get discovery config")
+	}
+
+	forgetBaseDomain(configMap.Namespace)
+	forgetCachedDomainSuffix(configMap.Namespace)
+
+	patch := []byte(fmt.Sprintf(`{"data":{"%s":null}}`, consts.KubeConfigMapKeyNetworkConfigDomainSuffix))
+	configMapCli := cliset.CoreV1().ConfigMaps(configMap.Namespace)
+
+	if strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]) != "" {
+		if configMap.Immutable != nil && *configMap.Immutable {
+			return errors.Wrapf(ErrNetworkConfigImmutable, "failed to reset the domain suffix")
+		}
+		if _, err = configMapCli.Patch(ctx, configMap.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to patch configmap %s", configMap.Name)
+		}
+	}
+
+	if discoveryConfig.StatusConfigMap != "" {
+		_, err = configMapCli.Patch(ctx, discoveryConfig.StatusConfigMap, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to patch configmap %s", discoveryConfig.StatusConfigMap)
+		}
+	}
+
+	return nil
+}
+
+// DomainSuffixAction is what ReconcileDomainSuffixes did in a namespace.
+type DomainSuffixAction string
+
+const (
+	// DomainSuffixActionVerified means the domain suffix matches the ingress, or
+	// isn't a magic DNS suffix that could be checked.
+	DomainSuffixActionVerified DomainSuffixAction = "verified"
+	// DomainSuffixActionDriftDetected means the domain suffix doesn't match the
+	// ingress and was left alone, since repairing wasn't requested.
+	DomainSuffixActionDriftDetected DomainSuffixAction = "drift-detected"
+	// DomainSuffixActionRediscovered means the stale domain suffix was reset and
+	// discovered again.
+	DomainSuffixActionRediscovered DomainSuffixAction = "rediscovered"
+	// DomainSuffixActionFailed means the verification or the repair failed.
+	DomainSuffixActionFailed DomainSuffixAction = "failed"
+)
+
+// DomainSuffixReconcileResult reports what ReconcileDomainSuffixes did in a
+// namespace.
+type DomainSuffixReconcileResult struct {
+	Namespace    string             `json:"namespace"`
+	Action       DomainSuffixAction `json:"action"`
+	DomainSuffix string             `json:"domainSuffix,omitempty"`
+	Error        string             `json:"error,omitempty"`
+}
+
+// ReconcileDomainSuffixes verifies the domain suffix of every namespace against
+// its current ingress IP. When repair is true, a stale domain suffix is reset and
+// discovered again; otherwise the drift is only reported.
+func ReconcileDomainSuffixes(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface, namespaces []string, repair bool) []DomainSuffixReconcileResult {
+	results := make([]DomainSuffixReconcileResult, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		results = append(results, reconcileDomainSuffix(WithNamespace(ctx, namespace), configmapGetter, cliset, namespace, repair))
+	}
+	return results
+}
+
+func reconcileDomainSuffix(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface, namespace string, repair bool) DomainSuffixReconcileResult {
+	result := DomainSuffixReconcileResult{Namespace: namespace}
+	fail := func(err error) DomainSuffixReconcileResult {
+		result.Action = DomainSuffixActionFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	err := VerifyDomainSuffix(ctx, configmapGetter, cliset)
+	var mismatch *DomainSuffixMismatchError
+	switch {
+	case err == nil:
+		result.Action = DomainSuffixActionVerified
+		return result
+	case !errors.As(err, &mismatch):
+		return fail(err)
+	}
+
+	result.DomainSuffix = mismatch.DomainSuffix
+	if !repair {
+		result.Action = DomainSuffixActionDriftDetected
+		result.Error = err.Error()
+		return result
+	}
+
+	if err = ResetDomainSuffix(ctx, configmapGetter, cliset); err != nil {
+		return fail(err)
+	}
+	domainSuffix, err := GetDomainSuffix(ctx, configmapGetter, cliset)
+	if err != nil {
+		return fail(err)
+	}
+	result.Action = DomainSuffixActionRediscovered
+	result.DomainSuffix = domainSuffix
+	return result
+}
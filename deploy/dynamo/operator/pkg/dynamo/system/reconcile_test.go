@@ -0,0 +1,172 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// clientsetConfigMapGetter gets the configmaps from the clientset, so that the
+// patches of the discovery are seen by the next get.
+func clientsetConfigMapGetter(cliset *fake.Clientset) func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		return cliset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+}
+
+func addNetworkConfigMap(t *testing.T, cliset *fake.Clientset, namespace string, data map[string]string, immutable bool) {
+	t.Helper()
+	configMap := newNetworkConfigMap(data)
+	configMap.Namespace = namespace
+	if immutable {
+		configMap.Immutable = ptr.To(true)
+	}
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap of %s: %v", namespace, err)
+	}
+}
+
+func TestResetDomainSuffix(t *testing.T) {
+	// Only the network config of the system namespace may set the status
+	// configmap.
+	cliset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "discovery-status", Namespace: GetNamespace()},
+		Data:       map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "status.example.com"},
+	})
+	addNetworkConfigMap(t, cliset, GetNamespace(), map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix:             "example.com",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryStatusConfigMap: "discovery-status",
+	}, false)
+	addNetworkConfigMap(t, cliset, "reset-tenant", map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "example.com",
+	}, false)
+	addNetworkConfigMap(t, cliset, "reset-immutable", map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "example.com",
+	}, true)
+	configmapGetter := clientsetConfigMapGetter(cliset)
+
+	for _, namespace := range []string{GetNamespace(), "reset-tenant"} {
+		if err := ResetDomainSuffix(WithNamespace(context.Background(), namespace), configmapGetter, cliset); err != nil {
+			t.Fatalf("ResetDomainSuffix() in %s error = %v", namespace, err)
+		}
+		configMap, err := configmapGetter(context.Background(), namespace, consts.KubeConfigMapNameNetworkConfig)
+		if err != nil {
+			t.Fatalf("failed to get the network configmap of %s: %v", namespace, err)
+		}
+		if _, ok := configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; ok {
+			t.Errorf("ResetDomainSuffix() in %s kept the domain suffix %q", namespace, configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix])
+		}
+	}
+	status, err := configmapGetter(context.Background(), GetNamespace(), "discovery-status")
+	if err != nil {
+		t.Fatalf("failed to get the status configmap: %v", err)
+	}
+	if _, ok := status.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; ok {
+		t.Errorf("ResetDomainSuffix() kept the domain suffix %q of the status configmap", status.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix])
+	}
+
+	err = ResetDomainSuffix(WithNamespace(context.Background(), "reset-immutable"), configmapGetter, cliset)
+	if !errors.Is(err, ErrNetworkConfigImmutable) {
+		t.Errorf("ResetDomainSuffix() of an immutable configmap error = %v, want ErrNetworkConfigImmutable", err)
+	}
+}
+
+func TestReconcileDomainSuffixes(t *testing.T) {
+	cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+	current := ComposeMagicDNSSuffix("10.0.0.1", GetMagicDNS())
+	stale := ComposeMagicDNSSuffix("10.0.0.2", GetMagicDNS())
+	discovery := map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	}
+	withDomainSuffix := func(domainSuffix string) map[string]string {
+		data := map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: domainSuffix}
+		for key, value := range discovery {
+			data[key] = value
+		}
+		return data
+	}
+	addNetworkConfigMap(t, cliset, "reconcile-current", withDomainSuffix(current), false)
+	addNetworkConfigMap(t, cliset, "reconcile-custom", withDomainSuffix("example.com"), false)
+	addNetworkConfigMap(t, cliset, "reconcile-stale", withDomainSuffix(stale), false)
+	addNetworkConfigMap(t, cliset, "reconcile-immutable", withDomainSuffix(stale), true)
+	namespaces := []string{"reconcile-current", "reconcile-custom", "reconcile-stale", "reconcile-immutable"}
+	configmapGetter := clientsetConfigMapGetter(cliset)
+
+	tests := []struct {
+		name   string
+		repair bool
+		want   map[string]DomainSuffixReconcileResult
+	}{
+		{
+			name: "report",
+			want: map[string]DomainSuffixReconcileResult{
+				"reconcile-current":   {Action: DomainSuffixActionVerified},
+				"reconcile-custom":    {Action: DomainSuffixActionVerified},
+				"reconcile-stale":     {Action: DomainSuffixActionDriftDetected, DomainSuffix: stale},
+				"reconcile-immutable": {Action: DomainSuffixActionDriftDetected, DomainSuffix: stale},
+			},
+		},
+		{
+			name:   "repair",
+			repair: true,
+			want: map[string]DomainSuffixReconcileResult{
+				"reconcile-current":   {Action: DomainSuffixActionVerified},
+				"reconcile-custom":    {Action: DomainSuffixActionVerified},
+				"reconcile-stale":     {Action: DomainSuffixActionRediscovered, DomainSuffix: current},
+				"reconcile-immutable": {Action: DomainSuffixActionFailed, DomainSuffix: stale},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := ReconcileDomainSuffixes(context.Background(), configmapGetter, cliset, namespaces, tt.repair)
+			if len(results) != len(namespaces) {
+				t.Fatalf("ReconcileDomainSuffixes() = %v, want a result per namespace", results)
+			}
+			for _, result := range results {
+				want := tt.want[result.Namespace]
+				if result.Action != want.Action || result.DomainSuffix != want.DomainSuffix {
+					t.Errorf("ReconcileDomainSuffixes() in %s = %s %q, want %s %q", result.Namespace, result.Action, result.DomainSuffix, want.Action, want.DomainSuffix)
+				}
+				if wantErr := want.Action == DomainSuffixActionDriftDetected || want.Action == DomainSuffixActionFailed; (result.Error != "") != wantErr {
+					t.Errorf("ReconcileDomainSuffixes() in %s error = %q, want an error %v", result.Namespace, result.Error, wantErr)
+				}
+			}
+		})
+	}
+
+	configMap, err := configmapGetter(context.Background(), "reconcile-stale", consts.KubeConfigMapNameNetworkConfig)
+	if err != nil {
+		t.Fatalf("failed to get the network configmap: %v", err)
+	}
+	if got := configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix]; got != current {
+		t.Errorf("the repaired domain suffix = %q, want %q", got, current)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// reproRedacted replaces the sanitized values in a reproduction bundle.
+const reproRedacted = "REDACTED"
+
+// secretReferencePattern matches the keys of the annotations and configmap entries
+// whose values may be or point at secrets.
+var secretReferencePattern = regexp.MustCompile(`(?i)(secret|token|password|passwd|credential|auth|private-?key|api-?key)`)
+
+// RenderProbeIngress returns the probe ingress GetIngressIP would create for the
+// network config, without creating it. cliset may be nil, the ingress controller
+// isn't detected then, so none of its defaults apply.
+func RenderProbeIngress(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (*networkingv1.Ingress, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get configmap %s", consts.KubeConfigMapNameNetworkConfig)
+	}
+	ingressConfig, err := ParseIngressConfig(configMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get ingress config")
+	}
+	discoveryConfig, err := parseDiscoveryConfig(configMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get discovery config")
+	}
+
+	controllerType := IngressControllerUnknown
+	if cliset != nil {
+		controllerType = GetIngressControllerType(ctx, cliset, ingressConfig.ClassName)
+	}
+	return renderProbeIngress(discoveryLogger(ctx, correlationID), namespaceFromContext(ctx), correlationID, ingressConfig, discoveryConfig, controllerType)
+}
+
+// RenderProbeIngressYAML returns the probe ingress RenderProbeIngress renders
+// without a clientset as YAML, to preview it, e.g. in a dry run.
+func RenderProbeIngressYAML(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) ([]byte, error) {
+	probe, err := RenderProbeIngress(ctx, configmapGetter, nil)
+	if err != nil {
+		return nil, err
+	}
+	probe.TypeMeta = metav1.TypeMeta{APIVersion: networkingv1.SchemeGroupVersion.String(), Kind: "Ingress"}
+	out, err := yaml.Marshal(probe)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the probe ingress")
+	}
+	return out, nil
+}
+
+// DumpNetworkConfig returns the network config as the operator resolves it, with
+// its namespace, as indented JSON for debugging. The values of the ingress
+// annotations that look like secret references are redacted.
+func DumpNetworkConfig(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)) ([]byte, error) {
+	networkConfig, err := GetNetworkConfig(ctx, configmapGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressConfig := *networkConfig.Ingress
+	ingressConfig.Annotations = sanitizeValues(ingressConfig.Annotations)
+	sanitized := *networkConfig
+	sanitized.Ingress = &ingressConfig
+
+	out, err := json.MarshalIndent(struct {
+		Namespace string
+		*NetworkConfig
+	}{
+		Namespace:     namespaceFromContext(ctx),
+		NetworkConfig: &sanitized,
+	}, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the network config")
+	}
+	return out, nil
+}
+
+// BuildReproBundle returns a multi-document YAML bundle to attach to a bug report
+// about a failed discovery: the network configmap, the ingress class, the
+// rendered probe ingress and a configmap with the failure details. The values of
+// the keys that look like secret references are redacted.
+//
+// The bundle is built on a best-effort basis: a part that can't be collected is
+// described in the failure details instead.
+func BuildReproBundle(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface, discoveryErr error) ([]byte, error) {
+	namespace := namespaceFromContext(ctx)
+	details := map[string]string{}
+	if discoveryErr != nil {
+		details["error"] = discoveryErr.Error()
+		if fields := ErrorFields(discoveryErr); fields != nil {
+			details["fields"] = marshalJSONString(fields)
+		}
+	}
+	if state, ok := GetDiscoveryState(namespace); ok {
+		details["state"] = marshalJSONString(state)
+	}
+	if err := PreflightDiscovery(ctx, cliset, nil); err != nil {
+		details["preflight"] = err.Error()
+	}
+
+	var objects []interface{}
+
+	var className *string
+	configMap, err := GetNetworkConfigConfigMap(ctx, configmapGetter)
+	if err != nil {
+		details["network-configmap"] = err.Error()
+	} else {
+		objects = append(objects, sanitizeNetworkConfigMap(configMap))
+		if ingressConfig, err := ParseIngressConfig(configMap); err == nil {
+			className = ingressConfig.ClassName
+		}
+	}
+
+	if ingressClass, err := getReproIngressClass(ctx, cliset, className); err != nil {
+		details["ingress-class"] = err.Error()
+	} else if ingressClass != nil {
+		objects = append(objects, ingressClass)
+	}
+
+	if probe, err := RenderProbeIngress(ctx, configmapGetter, cliset); err != nil {
+		details["probe-ingress"] = err.Error()
+	} else {
+		probe.TypeMeta = metav1.TypeMeta{APIVersion: networkingv1.SchemeGroupVersion.String(), Kind: "Ingress"}
+		probe.Annotations = sanitizeValues(probe.Annotations)
+		objects = append(objects, probe)
+	}
+
+	objects = append(objects, &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "domain-suffix-discovery-failure",
+			Namespace: namespace,
+		},
+		Data: details,
+	})
+
+	var buf bytes.Buffer
+	for _, object := range objects {
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal the reproduction bundle")
+		}
+		buf.WriteString("---\n")
+		buf.Write(out)
+	}
+	return buf.Bytes(), nil
+}
+
+func getReproIngressClass(ctx context.Context, cliset kubernetes.Interface, className *string) (*networkingv1.IngressClass, error) {
+	classes, err := cliset.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list ingress classes")
+	}
+	for i := range classes.Items {
+		class := &classes.Items[i]
+		if (className != nil && class.Name == *className) || (className == nil && class.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == "true") {
+			class = class.DeepCopy()
+			class.TypeMeta = metav1.TypeMeta{APIVersion: networkingv1.SchemeGroupVersion.String(), Kind: "IngressClass"}
+			class.ObjectMeta = metav1.ObjectMeta{
+				Name:        class.Name,
+				Labels:      class.Labels,
+				Annotations: sanitizeValues(class.Annotations),
+			}
+			return class, nil
+		}
+	}
+	return nil, nil
+}
+
+// sanitizeNetworkConfigMap returns a copy of the network configmap without its
+// server-side metadata and with the secret-like values redacted, including inside
+// the ingress annotations.
+func sanitizeNetworkConfigMap(configMap *corev1.ConfigMap) *corev1.ConfigMap {
+	sanitized := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        configMap.Name,
+			Namespace:   configMap.Namespace,
+			Labels:      configMap.Labels,
+			Annotations: sanitizeValues(configMap.Annotations),
+		},
+		Immutable: configMap.Immutable,
+		Data:      sanitizeValues(configMap.Data),
+	}
+
+	for _, key := range []string{consts.KubeConfigMapKeyNetworkConfigIngressAnnotations, consts.KubeConfigMapKeyNetworkConfigIngressAnnotationsBase} {
+		annotations := map[string]string{}
+		if value, ok := sanitized.Data[key]; ok {
+			if err := json.Unmarshal([]byte(value), &annotations); err == nil {
+				sanitized.Data[key] = marshalJSONString(sanitizeValues(annotations))
+			}
+		}
+	}
+	return sanitized
+}
+
+func sanitizeValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	sanitized := make(map[string]string, len(values))
+	for k, v := range values {
+		if secretReferencePattern.MatchString(k) {
+			v = reproRedacted
+		}
+		sanitized[k] = v
+	}
+	return sanitized
+}
+
+func marshalJSONString(v interface{}) string {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
@@ -0,0 +1,85 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestSanitizeNetworkConfigMap(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:                                                     "nginx",
+		consts.KubeConfigMapKeyNetworkConfigIngressStaticTLSSecretName:                                       "wildcard-cert",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations:                                               `{"nginx.ingress.kubernetes.io/auth-secret": "basic-auth", "nginx.ingress.kubernetes.io/ssl-redirect": "false"}`,
+		consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation + "nginx.ingress.kubernetes.io/auth-url": "https://auth.example.com",
+	})
+	configMap.ResourceVersion = "42"
+	configMap.Annotations = map[string]string{"example.com/api-key": "hunter2"}
+
+	sanitized := sanitizeNetworkConfigMap(configMap)
+	if sanitized.ResourceVersion != "" {
+		t.Errorf("sanitizeNetworkConfigMap() kept the resource version %s", sanitized.ResourceVersion)
+	}
+	if got := sanitized.Annotations["example.com/api-key"]; got != reproRedacted {
+		t.Errorf("the api-key annotation = %q, want it redacted", got)
+	}
+	if got := sanitized.Data[consts.KubeConfigMapKeyNetworkConfigIngressClass]; got != "nginx" {
+		t.Errorf("the ingress class = %q, want it kept", got)
+	}
+	if got := sanitized.Data[consts.KubeConfigMapKeyNetworkConfigIngressStaticTLSSecretName]; got != reproRedacted {
+		t.Errorf("the static TLS secret name = %q, want it redacted", got)
+	}
+	if got := sanitized.Data[consts.KubeConfigMapKeyPrefixNetworkConfigIngressAnnotation+"nginx.ingress.kubernetes.io/auth-url"]; got != reproRedacted {
+		t.Errorf("the auth-url annotation key = %q, want it redacted", got)
+	}
+	var annotations map[string]string
+	if err := json.Unmarshal([]byte(sanitized.Data[consts.KubeConfigMapKeyNetworkConfigIngressAnnotations]), &annotations); err != nil {
+		t.Fatalf("the sanitized ingress annotations aren't JSON: %v", err)
+	}
+	if annotations["nginx.ingress.kubernetes.io/auth-secret"] != reproRedacted || annotations["nginx.ingress.kubernetes.io/ssl-redirect"] != "false" {
+		t.Errorf("the sanitized ingress annotations = %v, want only the auth-secret redacted", annotations)
+	}
+	if configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressStaticTLSSecretName] != "wildcard-cert" {
+		t.Error("sanitizeNetworkConfigMap() modified the configmap")
+	}
+}
+
+func TestBuildReproBundleRedactsSecrets(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:       "nginx",
+		consts.KubeConfigMapKeyNetworkConfigIngressAnnotations: `{"nginx.ingress.kubernetes.io/auth-secret": "basic-auth"}`,
+	})
+
+	bundle, err := BuildReproBundle(context.Background(), staticConfigMapGetter(configMap), newLoadBalancerClientset(), nil)
+	if err != nil {
+		t.Fatalf("BuildReproBundle() error = %v", err)
+	}
+	if strings.Contains(string(bundle), "basic-auth") {
+		t.Errorf("BuildReproBundle() leaked the secret reference:\n%s", bundle)
+	}
+	for _, want := range []string{"kind: ConfigMap", "kind: IngressClass", "kind: Ingress\n", reproRedacted, "domain-suffix-discovery-failure"} {
+		if !strings.Contains(string(bundle), want) {
+			t.Errorf("BuildReproBundle() has no %q:\n%s", want, bundle)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// defaultCreateRetries is how many times the creation of a probe is retried
+// after a transient failure, unless set in the network config.
+const defaultCreateRetries = 4
+
+// createRetryBackoff is the backoff between the retries of the creation of a
+// probe, its Steps is set from the retry budget.
+var createRetryBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Cap:      10 * time.Second,
+}
+
+// isRetriableCreateError reports whether the creation of a probe failed
+// transiently, e.g. because a validating webhook is still warming up. Invalid
+// specs are not retried, they would fail again.
+func isRetriableCreateError(err error) bool {
+	return k8serrors.IsConflict(err) ||
+		k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsInternalError(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		k8serrors.IsTooManyRequests(err)
+}
+
+// retryCreate calls create until it succeeds, fails with an error that isn't
+// transient, or has been retried retries times.
+func retryCreate(retries int, create func() error) error {
+	backoff := createRetryBackoff
+	backoff.Steps = retries + 1
+	return retry.OnError(backoff, isRetriableCreateError, create)
+}
+
+// IsRetryable reports whether a failed discovery, e.g. of GetDomainSuffix, may
+// succeed when retried: the load balancer got no address in time, a hostname
+// couldn't be resolved temporarily, the address changed during the discovery or
+// the API server failed transiently. Invalid configs, missing objects and
+// unresolvable hostnames are permanent, and so are the errors that aren't
+// recognized.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrShuttingDown) || errors.Is(err, ErrInsufficientTimeout) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var resolveErr *ResolveError
+	if errors.As(err, &resolveErr) {
+		return resolveErr.IsTemporary
+	}
+	var addressChangedErr *AddressChangedError
+	if errors.As(err, &addressChangedErr) {
+		return true
+	}
+	return isRetriableCreateError(err)
+}
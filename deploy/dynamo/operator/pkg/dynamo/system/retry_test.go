@@ -0,0 +1,145 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestGetIngressIPRetriesCreate(t *testing.T) {
+	backoff := createRetryBackoff
+	createRetryBackoff.Duration = time.Millisecond
+	defer func() { createRetryBackoff = backoff }()
+
+	ingressResource := schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}
+	conflict := k8serrors.NewConflict(ingressResource, "", nil)
+	serverTimeout := k8serrors.NewServerTimeout(ingressResource, "create", 1)
+	invalid := k8serrors.NewInvalid(schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}, "", field.ErrorList{field.Invalid(field.NewPath("spec"), nil, "bad")})
+
+	tests := []struct {
+		name      string
+		retries   string
+		failures  []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "transient failures",
+			failures:  []error{conflict, serverTimeout},
+			wantCalls: 3,
+		},
+		{
+			name:      "invalid spec",
+			failures:  []error{invalid},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "retry budget exhausted",
+			retries:   "1",
+			failures:  []error{conflict, conflict, conflict},
+			wantCalls: 2,
+			wantErr:   true,
+		},
+		{
+			name:      "retries disabled",
+			retries:   "0",
+			failures:  []error{conflict},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliset := newLoadBalancerClientset(networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"})
+			calls := 0
+			cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				calls++
+				if calls <= len(tt.failures) {
+					return true, nil, tt.failures[calls-1]
+				}
+				return false, nil, nil
+			})
+
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryCreateRetries:   tt.retries,
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetIngressIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && ip != "10.0.0.1" {
+				t.Errorf("GetIngressIP() = %q, want %q", ip, "10.0.0.1")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("the ingress creation was attempted %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	ingressResource := schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}
+	ingressClassResource := schema.GroupResource{Group: "networking.k8s.io", Resource: "ingressclasses"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "load balancer timeout", err: errors.Wrap(context.DeadlineExceeded, "failed to wait for ingress probe to be ready"), want: true},
+		{name: "temporary dns error", err: newResolveError("lb.example.com", &net.DNSError{Err: "timeout", Name: "lb.example.com", IsTimeout: true}), want: true},
+		{name: "dns not found", err: newResolveError("lb.example.com", &net.DNSError{Err: "no such host", Name: "lb.example.com", IsNotFound: true}), want: false},
+		{name: "api server timeout", err: errors.Wrap(k8serrors.NewServerTimeout(ingressResource, "create", 1), "failed to create ingress"), want: true},
+		{name: "probe create conflict", err: &ProbeCreateError{Kind: "ingress", Name: "probe", Err: k8serrors.NewConflict(ingressResource, "probe", nil)}, want: true},
+		{name: "address changed", err: &AddressChangedError{DiscoveredIP: "10.0.0.1", CurrentIP: "10.0.0.2"}, want: true},
+		{name: "ingress class not found", err: errors.Wrap(k8serrors.NewNotFound(ingressClassResource, "nginx"), "failed to get ingress class"), want: false},
+		{name: "no default ingress class", err: ErrNoDefaultIngressClass, want: false},
+		{name: "invalid config", err: errors.Errorf("invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigIngressPath, consts.KubeConfigMapNameNetworkConfig), want: false},
+		{name: "insufficient timeout", err: errors.Wrap(ErrInsufficientTimeout, "failed to discover"), want: false},
+		{name: "shutting down", err: ErrShuttingDown, want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AddressChangePolicy is what to do when the address of the ingress controller
+// changed between the discovery and the persistence of the domain suffix.
+type AddressChangePolicy string
+
+const (
+	// AddressChangePolicyPersist persists the discovered address without
+	// checking it again.
+	AddressChangePolicyPersist AddressChangePolicy = "persist"
+	// AddressChangePolicyReselect persists the current address instead.
+	AddressChangePolicyReselect AddressChangePolicy = "reselect"
+	// AddressChangePolicyAbort fails the discovery with an *AddressChangedError.
+	AddressChangePolicyAbort AddressChangePolicy = "abort"
+)
+
+// AddressChangedError is returned when the address of the ingress controller
+// changed before the discovered domain suffix was persisted.
+type AddressChangedError struct {
+	DiscoveredIP string
+	CurrentIP    string
+}
+
+func (e *AddressChangedError) Error() string {
+	return fmt.Sprintf("the ingress controller address changed from %s to %s during the domain suffix discovery", e.DiscoveredIP, e.CurrentIP)
+}
+
+// reverifyIngressIP checks the discovered IP against the current load balancer
+// status of the ingress controller Service, right before it is persisted. When
+// the Service or its address can't be found, the discovered IP is kept.
+func reverifyIngressIP(ctx context.Context, cliset kubernetes.Interface, config *discoveryConfig, configMap *corev1.ConfigMap, ip string) (string, error) {
+	if config.AddressChangePolicy == AddressChangePolicyPersist || config.NetworkMode != NetworkModeLoadBalancer || config.ExternalIP != "" {
+		return ip, nil
+	}
+
+	ingressConfig, err := ParseIngressConfig(configMap)
+	if err != nil {
+		return "", err
+	}
+
+	logger := contextLogger(ctx)
+	svc, err := findIngressControllerService(ctx, cliset, config, ingressConfig.ClassName)
+	if err != nil {
+		logger.Error(err, "Cannot re-verify the discovered address", "ip", ip)
+		return ip, nil
+	}
+
+	entries := serviceLoadBalancerEntries(svc)
+	if len(entries) == 0 {
+		logger.Info("Cannot re-verify the discovered address, the ingress controller service has no load balancer address", "ip", ip, "service", svc.Namespace+"/"+svc.Name)
+		return ip, nil
+	}
+
+	address, err := selectLoadBalancerAddress(entries, fmt.Sprintf("the service %s/%s", svc.Namespace, svc.Name), config)
+	if err != nil {
+		return "", err
+	}
+	currentIP, err := resolveLoadBalancerIngress(ctx, address, config)
+	if err != nil {
+		return "", err
+	}
+	if currentIP == ip {
+		return ip, nil
+	}
+
+	if config.AddressChangePolicy == AddressChangePolicyAbort {
+		return "", &AddressChangedError{DiscoveredIP: ip, CurrentIP: currentIP}
+	}
+	logger.Info("The ingress controller address changed during the discovery, so the current one is persisted instead", "ip", ip, "currentIP", currentIP)
+	return currentIP, nil
+}
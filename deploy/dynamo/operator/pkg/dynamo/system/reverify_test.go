@@ -0,0 +1,114 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestReverifyIngressIP(t *testing.T) {
+	newService := func(ips ...string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}
+		for _, ip := range ips {
+			svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: ip})
+		}
+		return svc
+	}
+
+	tests := []struct {
+		name       string
+		policy     AddressChangePolicy
+		objects    []runtime.Object
+		want       string
+		wantChange bool
+	}{
+		{
+			name:    "persist",
+			policy:  AddressChangePolicyPersist,
+			objects: []runtime.Object{newService("10.0.0.2")},
+			want:    "10.0.0.1",
+		},
+		{
+			name:    "unchanged",
+			policy:  AddressChangePolicyAbort,
+			objects: []runtime.Object{newService("10.0.0.1")},
+			want:    "10.0.0.1",
+		},
+		{
+			name:    "reselect",
+			policy:  AddressChangePolicyReselect,
+			objects: []runtime.Object{newService("10.0.0.2")},
+			want:    "10.0.0.2",
+		},
+		{
+			name:       "abort",
+			policy:     AddressChangePolicyAbort,
+			objects:    []runtime.Object{newService("10.0.0.2")},
+			wantChange: true,
+		},
+		{
+			name:   "no service",
+			policy: AddressChangePolicyAbort,
+			want:   "10.0.0.1",
+		},
+		{
+			name:    "no service address",
+			policy:  AddressChangePolicyAbort,
+			objects: []runtime.Object{newService()},
+			want:    "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:                 "nginx",
+				consts.KubeConfigMapKeyNetworkConfigIngressControllerService:     "ingress-nginx/ingress-nginx-controller",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressChangePolicy: string(tt.policy),
+			})
+			config, err := parseDiscoveryConfig(configMap)
+			if err != nil {
+				t.Fatalf("parseDiscoveryConfig() error = %v", err)
+			}
+
+			ip, err := reverifyIngressIP(context.Background(), fake.NewSimpleClientset(tt.objects...), config, configMap, "10.0.0.1")
+			if tt.wantChange {
+				var changedErr *AddressChangedError
+				if !errors.As(err, &changedErr) || changedErr.DiscoveredIP != "10.0.0.1" || changedErr.CurrentIP != "10.0.0.2" {
+					t.Errorf("reverifyIngressIP() error = %v, want an AddressChangedError from 10.0.0.1 to 10.0.0.2", err)
+				}
+				return
+			}
+			if err != nil || ip != tt.want {
+				t.Errorf("reverifyIngressIP() = %q, %v, want %q", ip, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// reverseLookupTimeout bounds a reverse lookup, since reverse DNS is often
+	// unconfigured and slow to fail.
+	reverseLookupTimeout = 2 * time.Second
+	// reverseLookupTTL is how long a reverse lookup result is cached, and
+	// reverseLookupCacheSize the number of cached IPs.
+	reverseLookupTTL       = time.Hour
+	reverseLookupCacheSize = 256
+)
+
+// reverseResolver is implemented by the resolvers, such as *net.Resolver, that
+// support reverse lookups.
+type reverseResolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+type reverseLookupEntry struct {
+	names   []string
+	expires time.Time
+}
+
+var (
+	reverseLookupCacheMu sync.Mutex
+	reverseLookupCache   = make(map[string]reverseLookupEntry)
+)
+
+// reverseLookup returns the names the IP maps to in reverse DNS, or nil if the
+// lookup fails or the resolver doesn't support it.
+func reverseLookup(ctx context.Context, ip string) []string {
+	now := time.Now()
+
+	reverseLookupCacheMu.Lock()
+	entry, ok := reverseLookupCache[ip]
+	reverseLookupCacheMu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.names
+	}
+
+	r, ok := getResolver().(reverseResolver)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reverseLookupTimeout)
+	defer cancel()
+	names, err := r.LookupAddr(ctx, ip)
+	if err != nil {
+		contextLogger(ctx).V(1).Info("The reverse lookup failed", "ip", ip, "error", err.Error())
+		return nil
+	}
+
+	reverseLookupCacheMu.Lock()
+	defer reverseLookupCacheMu.Unlock()
+	if len(reverseLookupCache) >= reverseLookupCacheSize {
+		reverseLookupCache = make(map[string]reverseLookupEntry)
+	}
+	reverseLookupCache[ip] = reverseLookupEntry{names: names, expires: now.Add(reverseLookupTTL)}
+	return names
+}
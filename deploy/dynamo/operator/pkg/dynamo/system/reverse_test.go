@@ -0,0 +1,74 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// reverseFakeResolver is a fakeResolver that also resolves the reverse names of
+// its IPs, counting the reverse lookups.
+type reverseFakeResolver struct {
+	fakeResolver
+	names   map[string][]string
+	lookups *int
+}
+
+func (r reverseFakeResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	*r.lookups++
+	names, ok := r.names[addr]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+	}
+	return names, nil
+}
+
+func TestReverseLookup(t *testing.T) {
+	var lookups int
+	SetResolver(reverseFakeResolver{
+		names:   map[string][]string{"192.0.2.40": {"lb.example.com."}},
+		lookups: &lookups,
+	})
+	defer SetResolver(nil)
+
+	for i := 0; i < 2; i++ {
+		if names := reverseLookup(context.Background(), "192.0.2.40"); !reflect.DeepEqual(names, []string{"lb.example.com."}) {
+			t.Errorf("reverseLookup() = %v, want lb.example.com.", names)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("the resolver was queried %d times, want once with the cached result", lookups)
+	}
+
+	// The failures aren't cached.
+	for i := 0; i < 2; i++ {
+		if names := reverseLookup(context.Background(), "192.0.2.41"); names != nil {
+			t.Errorf("reverseLookup() = %v of an IP without reverse names, want nil", names)
+		}
+	}
+	if lookups != 3 {
+		t.Errorf("the resolver was queried %d times, want the failed lookup retried", lookups)
+	}
+
+	SetResolver(fakeResolver{})
+	if names := reverseLookup(context.Background(), "192.0.2.42"); names != nil {
+		t.Errorf("reverseLookup() = %v with a resolver without reverse lookups, want nil", names)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+const (
+	nginxRewriteTargetAnnotation = "nginx.ingress.kubernetes.io/rewrite-target"
+	traefikMiddlewaresAnnotation = "traefik.ingress.kubernetes.io/router.middlewares"
+)
+
+// traefikMiddlewareRefRegexp matches a traefik middleware reference, such as
+// `<namespace>-<name>@kubernetescrd`.
+var traefikMiddlewareRefRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?@[a-z0-9]+$`)
+
+// ParseRewriteTarget returns the path the ingress controller rewrites the
+// requests to before forwarding them to the backend, and the traefik middleware
+// that does it, since traefik can't be configured through an annotation of the
+// ingress alone. Both are "" if they aren't set.
+func ParseRewriteTarget(configMap *corev1.ConfigMap) (rewriteTarget, middleware string, err error) {
+	rewriteTarget = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressRewriteTarget])
+	if rewriteTarget != "" && (!strings.HasPrefix(rewriteTarget, "/") || strings.ContainsAny(rewriteTarget, " \t\n")) {
+		err = errors.Errorf("invalid %s %q in configmap %s, must be a path starting with /", consts.KubeConfigMapKeyNetworkConfigIngressRewriteTarget, rewriteTarget, consts.KubeConfigMapNameNetworkConfig)
+		return
+	}
+
+	middleware = strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware])
+	if middleware != "" && !traefikMiddlewareRefRegexp.MatchString(middleware) {
+		err = errors.Errorf("invalid %s %q in configmap %s, must be a traefik middleware reference such as <namespace>-<name>@kubernetescrd", consts.KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware, middleware, consts.KubeConfigMapNameNetworkConfig)
+		return
+	}
+	return
+}
+
+// RewriteTargetIngressAnnotations returns the ingress annotations that make the
+// ingress controller rewrite the requests to the rewrite target. For traefik, they
+// reference the middleware, which is expected to replace the path with the
+// rewrite target. It fails for the controllers that it doesn't know how to
+// configure, rather than leaving the requests unrewritten.
+func RewriteTargetIngressAnnotations(rewriteTarget, middleware string, controllerType IngressControllerType) (map[string]string, error) {
+	switch {
+	case rewriteTarget == "":
+		return nil, nil
+	case controllerType == IngressControllerNginx:
+		return map[string]string{nginxRewriteTargetAnnotation: rewriteTarget}, nil
+	case controllerType == IngressControllerTraefik && middleware != "":
+		return map[string]string{traefikMiddlewaresAnnotation: middleware}, nil
+	case controllerType == IngressControllerTraefik:
+		return nil, errors.Errorf("the rewrite target %s of the ingress controller %q needs a middleware, set %s in configmap %s", rewriteTarget, controllerType, consts.KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware, consts.KubeConfigMapNameNetworkConfig)
+	default:
+		return nil, errors.Errorf("the rewrite target %s isn't supported for the ingress controller %q, set the annotations of the controller in %s of configmap %s instead", rewriteTarget, controllerType, consts.KubeConfigMapKeyNetworkConfigIngressAnnotations, consts.KubeConfigMapNameNetworkConfig)
+	}
+}
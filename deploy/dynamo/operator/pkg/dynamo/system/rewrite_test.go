@@ -0,0 +1,100 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestParseRewriteTarget(t *testing.T) {
+	tests := []struct {
+		name           string
+		rewriteTarget  string
+		middleware     string
+		wantTarget     string
+		wantMiddleware string
+		wantErr        bool
+	}{
+		{name: "unset"},
+		{name: "path", rewriteTarget: " / ", wantTarget: "/"},
+		{name: "capture group", rewriteTarget: "/$2", wantTarget: "/$2"},
+		{name: "middleware", rewriteTarget: "/", middleware: "dynamo-strip-inference@kubernetescrd", wantTarget: "/", wantMiddleware: "dynamo-strip-inference@kubernetescrd"},
+		{name: "relative path", rewriteTarget: "inference", wantErr: true},
+		{name: "path with spaces", rewriteTarget: "/a b", wantErr: true},
+		{name: "middleware without provider", rewriteTarget: "/", middleware: "dynamo-strip-inference", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressRewriteTarget:     tt.rewriteTarget,
+				consts.KubeConfigMapKeyNetworkConfigIngressRewriteMiddleware: tt.middleware,
+			})
+			ingressConfig, err := ParseIngressConfig(configMap)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngressConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (ingressConfig.RewriteTarget != tt.wantTarget || ingressConfig.RewriteMiddleware != tt.wantMiddleware) {
+				t.Errorf("RewriteTarget, RewriteMiddleware = %q, %q, want %q, %q", ingressConfig.RewriteTarget, ingressConfig.RewriteMiddleware, tt.wantTarget, tt.wantMiddleware)
+			}
+		})
+	}
+}
+
+func TestRewriteTargetIngressAnnotations(t *testing.T) {
+	const middleware = "dynamo-strip-inference@kubernetescrd"
+	tests := []struct {
+		rewriteTarget  string
+		middleware     string
+		controllerType IngressControllerType
+		want           map[string]string
+		wantErrMsg     string
+	}{
+		{rewriteTarget: "", controllerType: IngressControllerNginx},
+		{rewriteTarget: "", controllerType: IngressControllerTraefik},
+		{rewriteTarget: "", controllerType: IngressControllerUnknown},
+		{rewriteTarget: "/", controllerType: IngressControllerNginx, want: map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"}},
+		{rewriteTarget: "/", middleware: middleware, controllerType: IngressControllerNginx, want: map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"}},
+		{rewriteTarget: "/", middleware: middleware, controllerType: IngressControllerTraefik, want: map[string]string{"traefik.ingress.kubernetes.io/router.middlewares": middleware}},
+		{rewriteTarget: "/", controllerType: IngressControllerTraefik, wantErrMsg: "needs a middleware"},
+		{rewriteTarget: "/", controllerType: IngressControllerContour, wantErrMsg: `isn't supported for the ingress controller "contour"`},
+		{rewriteTarget: "/", controllerType: IngressControllerUnknown, wantErrMsg: `isn't supported for the ingress controller ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.controllerType)+tt.rewriteTarget+"/"+tt.middleware, func(t *testing.T) {
+			annotations, err := RewriteTargetIngressAnnotations(tt.rewriteTarget, tt.middleware, tt.controllerType)
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Errorf("RewriteTargetIngressAnnotations() error = %v, want one containing %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RewriteTargetIngressAnnotations() error = %v", err)
+			}
+			if (len(annotations) != 0 || len(tt.want) != 0) && !reflect.DeepEqual(annotations, tt.want) {
+				t.Errorf("RewriteTargetIngressAnnotations() = %v, want %v", annotations, tt.want)
+			}
+		})
+	}
+}
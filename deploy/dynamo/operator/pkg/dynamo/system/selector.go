@@ -0,0 +1,133 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// AddressFamily restricts the IPs the domain suffix may be built from. It
+// defaults to any, so that IPv6-only load balancers are discovered too.
+type AddressFamily string
+
+const (
+	AddressFamilyIPv4 AddressFamily = "ipv4"
+	AddressFamilyIPv6 AddressFamily = "ipv6"
+	AddressFamilyAny  AddressFamily = "any"
+)
+
+// AddressSelector picks the IP the domain suffix is built from among the addresses
+// a load balancer hostname resolves to.
+type AddressSelector struct {
+	Family AddressFamily
+	// Preferred is the family picked first when the addresses are of both
+	// families, IPv4 unless set to IPv6.
+	Preferred AddressFamily
+	// CIDRs, if any, the IP must be in one of.
+	CIDRs []*net.IPNet
+}
+
+// Matches reports whether the IP passes the family and CIDR filters.
+func (s *AddressSelector) Matches(ip net.IP) bool {
+	switch s.Family {
+	case AddressFamilyIPv4:
+		if ip.To4() == nil {
+			return false
+		}
+	case AddressFamilyIPv6:
+		if ip.To4() != nil {
+			return false
+		}
+	}
+	if len(s.CIDRs) == 0 {
+		return true
+	}
+	for _, cidr := range s.CIDRs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the first IP of the preferred family that passes the filters,
+// or else the first IP that passes them, in the order given.
+func (s *AddressSelector) Select(ips []net.IP) (net.IP, error) {
+	var fallback net.IP
+	for _, ip := range ips {
+		if !s.Matches(ip) {
+			continue
+		}
+		if (ip.To4() != nil) == (s.Preferred != AddressFamilyIPv6) {
+			return ip, nil
+		}
+		if fallback == nil {
+			fallback = ip
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+	candidates := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		candidates = append(candidates, ip.String())
+	}
+	return nil, errors.Errorf("none of the addresses [%s] matches the address family %s and the allowed CIDRs", strings.Join(candidates, ", "), s.Family)
+}
+
+func parseAddressSelector(configMap *corev1.ConfigMap) (*AddressSelector, error) {
+	selector := &AddressSelector{
+		Family: AddressFamily(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily])),
+	}
+	switch selector.Family {
+	case "":
+		selector.Family = AddressFamilyAny
+	case AddressFamilyIPv4, AddressFamilyIPv6, AddressFamilyAny:
+	default:
+		return nil, errors.Errorf("invalid %s in configmap %s: %s, expected one of %s, %s, %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily, consts.KubeConfigMapNameNetworkConfig, selector.Family, AddressFamilyIPv4, AddressFamilyIPv6, AddressFamilyAny)
+	}
+
+	selector.Preferred = AddressFamily(strings.TrimSpace(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferredAddressFamily]))
+	switch selector.Preferred {
+	case "":
+		selector.Preferred = AddressFamilyIPv4
+	case AddressFamilyIPv4, AddressFamilyIPv6:
+	default:
+		return nil, errors.Errorf("invalid %s in configmap %s: %s, expected one of %s, %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferredAddressFamily, consts.KubeConfigMapNameNetworkConfig, selector.Preferred, AddressFamilyIPv4, AddressFamilyIPv6)
+	}
+
+	for _, cidr := range strings.Split(configMap.Data[consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressCIDRs], ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s in configmap %s", consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressCIDRs, consts.KubeConfigMapNameNetworkConfig)
+		}
+		selector.CIDRs = append(selector.CIDRs, ipNet)
+	}
+
+	return selector, nil
+}
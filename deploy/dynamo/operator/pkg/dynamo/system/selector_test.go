@@ -0,0 +1,121 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"net"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestAddressSelectorSelect(t *testing.T) {
+	dualStack := []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("10.0.0.1")}
+
+	tests := []struct {
+		name    string
+		data    map[string]string
+		ips     []net.IP
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "prefers ipv4 by default",
+			ips:  dualStack,
+			want: "10.0.0.1",
+		},
+		{
+			name: "resolves ipv6 only load balancers by default",
+			ips:  []net.IP{net.ParseIP("2001:db8::2")},
+			want: "2001:db8::2",
+		},
+		{
+			name: "prefers ipv6 when configured",
+			data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferredAddressFamily: "ipv6"},
+			ips:  dualStack,
+			want: "2001:db8::1",
+		},
+		{
+			name:    "ipv4 only",
+			data:    map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily: "ipv4"},
+			ips:     []net.IP{net.ParseIP("2001:db8::2")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := parseAddressSelector(newNetworkConfigMap(tt.data))
+			if err != nil {
+				t.Fatalf("parseAddressSelector() error = %v", err)
+			}
+			ip, err := selector.Select(tt.ips)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && ip.String() != tt.want {
+				t.Errorf("Select() = %s, want %s", ip, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressSelectorMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+		ip   string
+		want bool
+	}{
+		{name: "any ipv4", ip: "10.0.0.1", want: true},
+		{name: "any ipv6", ip: "2001:db8::1", want: true},
+		{name: "ipv4 family", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily: "ipv4"}, ip: "2001:db8::1"},
+		{name: "ipv6 family", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily: "ipv6"}, ip: "10.0.0.1"},
+		{name: "in a CIDR", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressCIDRs: "192.168.0.0/16, 10.0.0.0/8"}, ip: "10.1.2.3", want: true},
+		{name: "outside the CIDRs", data: map[string]string{consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressCIDRs: "192.168.0.0/16"}, ip: "10.1.2.3"},
+		{
+			name: "family and CIDR",
+			data: map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressFamily: "ipv6",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryAddressCIDRs:  "10.0.0.0/8",
+			},
+			ip: "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := parseAddressSelector(newNetworkConfigMap(tt.data))
+			if err != nil {
+				t.Fatalf("parseAddressSelector() error = %v", err)
+			}
+			if got := selector.Matches(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("Matches(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAddressSelectorInvalidPreference(t *testing.T) {
+	_, err := parseAddressSelector(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPreferredAddressFamily: "any",
+	}))
+	if err == nil {
+		t.Error("parseAddressSelector() error = nil, want an error for an invalid preference")
+	}
+}
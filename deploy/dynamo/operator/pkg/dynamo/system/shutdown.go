@@ -0,0 +1,123 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// probeIngressDeleteTimeout bounds the deletion of a probe ingress, which uses a
+// background context so that it isn't skipped when the discovery is canceled.
+const probeIngressDeleteTimeout = 30 * time.Second
+
+// ErrShuttingDown is returned by the discoveries started or aborted after Shutdown
+// was called.
+var ErrShuttingDown = errors.New("the domain suffix discovery is shutting down")
+
+type activeProbe struct {
+	cancel  context.CancelFunc
+	cleanup func()
+}
+
+var (
+	activeProbesMu sync.Mutex
+	activeProbes   = make(map[*activeProbe]struct{})
+	shuttingDown   bool
+)
+
+// trackProbe registers the in-flight probe objects cleaned up by cleanup, and
+// returns a context that is canceled on Shutdown together with the function that
+// cleans the probe up and unregisters it. cleanup must not depend on ctx, and may
+// be called more than once.
+func trackProbe(ctx context.Context, cleanup func()) (probeCtx context.Context, done func(), err error) {
+	probeCtx, cancel := context.WithCancel(ctx)
+	probe := &activeProbe{cancel: cancel, cleanup: cleanup}
+
+	activeProbesMu.Lock()
+	if shuttingDown {
+		activeProbesMu.Unlock()
+		cancel()
+		cleanup()
+		return nil, nil, ErrShuttingDown
+	}
+	activeProbes[probe] = struct{}{}
+	activeProbesMu.Unlock()
+
+	done = func() {
+		cancel()
+		cleanup()
+		activeProbesMu.Lock()
+		delete(activeProbes, probe)
+		activeProbesMu.Unlock()
+	}
+	return probeCtx, done, nil
+}
+
+// trackProbeIngress is trackProbe for a probe ingress.
+func trackProbeIngress(ctx context.Context, cliset kubernetes.Interface, namespace, name string) (probeCtx context.Context, done func(), err error) {
+	logger := contextLogger(ctx)
+	return trackProbe(ctx, func() {
+		deleteProbeIngress(logger, cliset, namespace, name)
+	})
+}
+
+func deleteProbeIngress(logger logr.Logger, cliset kubernetes.Interface, namespace, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeIngressDeleteTimeout)
+	defer cancel()
+	err := getIngressClient(cliset, namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete the probe ingress", "ingress", namespace+"/"+name)
+	}
+}
+
+// Shutdown aborts the in-flight discoveries, deletes their probe ingresses and
+// waits for them to return, or for ctx to be done. Discoveries started afterwards
+// fail with ErrShuttingDown.
+func Shutdown(ctx context.Context) error {
+	activeProbesMu.Lock()
+	shuttingDown = true
+	probes := make([]*activeProbe, 0, len(activeProbes))
+	for probe := range activeProbes {
+		probes = append(probes, probe)
+	}
+	activeProbesMu.Unlock()
+
+	for _, probe := range probes {
+		probe.cancel()
+		probe.cleanup()
+	}
+
+	err := wait.PollUntilContextCancel(ctx, 100*time.Millisecond, true, func(context.Context) (bool, error) {
+		activeProbesMu.Lock()
+		defer activeProbesMu.Unlock()
+		return len(activeProbes) == 0, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to wait for the in-flight domain suffix discoveries")
+	}
+	return nil
+}
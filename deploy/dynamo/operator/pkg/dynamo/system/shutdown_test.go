@@ -0,0 +1,159 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	networkingclientv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+// contextCheckingClientset fails the ingress deletions made with a done context,
+// which the fake clientset would otherwise accept.
+type contextCheckingClientset struct {
+	*fake.Clientset
+}
+
+func (c contextCheckingClientset) NetworkingV1() networkingclientv1.NetworkingV1Interface {
+	return contextCheckingNetworking{c.Clientset.NetworkingV1()}
+}
+
+type contextCheckingNetworking struct {
+	networkingclientv1.NetworkingV1Interface
+}
+
+func (c contextCheckingNetworking) Ingresses(namespace string) networkingclientv1.IngressInterface {
+	return contextCheckingIngresses{c.NetworkingV1Interface.Ingresses(namespace)}
+}
+
+type contextCheckingIngresses struct {
+	networkingclientv1.IngressInterface
+}
+
+func (c contextCheckingIngresses) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.IngressInterface.Delete(ctx, name, opts)
+}
+
+func TestGetIngressIPDeletesProbeAfterCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeCliset := fake.NewSimpleClientset(&networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+	})
+	fakeCliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress)
+		ing.Name = ing.GenerateName + "canceled"
+		return false, nil, nil
+	})
+	// The load balancer never shows up, and the caller gives up while waiting.
+	fakeCliset.PrependReactor("get", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		cancel()
+		return false, nil, nil
+	})
+	var cliset kubernetes.Interface = contextCheckingClientset{fakeCliset}
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:             "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval:    "10ms",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollImmediately: "true",
+	})
+
+	if _, err := GetIngressIP(ctx, staticConfigMapGetter(configMap), cliset); err == nil {
+		t.Fatal("GetIngressIP() error = nil, want the cancellation error")
+	}
+
+	probes, err := fakeCliset.NetworkingV1().Ingresses(GetNamespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list ingresses: %v", err)
+	}
+	if len(probes.Items) != 0 {
+		t.Errorf("%d probe ingresses leaked after the cancellation, want 0", len(probes.Items))
+	}
+}
+
+func TestTrackProbeIngressDeletesWithCanceledParent(t *testing.T) {
+	fakeCliset := fake.NewSimpleClientset(&networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: probeIngressGenerateName + "tracked", Namespace: GetNamespace()},
+	})
+	var cliset kubernetes.Interface = contextCheckingClientset{fakeCliset}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	probeCtx, done, err := trackProbeIngress(ctx, cliset, GetNamespace(), probeIngressGenerateName+"tracked")
+	if err != nil {
+		t.Fatalf("trackProbeIngress() error = %v", err)
+	}
+	cancel()
+	if probeCtx.Err() == nil {
+		t.Error("the probe context isn't canceled with its parent")
+	}
+	done()
+
+	if _, err := fakeCliset.NetworkingV1().Ingresses(GetNamespace()).Get(context.Background(), probeIngressGenerateName+"tracked", metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("the tracked probe ingress isn't deleted after the cancellation, get error = %v", err)
+	}
+}
+
+func TestShutdownDeletesTrackedProbes(t *testing.T) {
+	defer func() {
+		activeProbesMu.Lock()
+		shuttingDown = false
+		activeProbesMu.Unlock()
+	}()
+	cliset := fake.NewSimpleClientset(&networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: probeIngressGenerateName + "inflight", Namespace: GetNamespace()},
+	})
+
+	probeCtx, done, err := trackProbeIngress(context.Background(), cliset, GetNamespace(), probeIngressGenerateName+"inflight")
+	if err != nil {
+		t.Fatalf("trackProbeIngress() error = %v", err)
+	}
+	// The discovery returns once its probe context is canceled.
+	go func() {
+		<-probeCtx.Done()
+		done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if _, err := cliset.NetworkingV1().Ingresses(GetNamespace()).Get(context.Background(), probeIngressGenerateName+"inflight", metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("the in-flight probe ingress isn't deleted by Shutdown(), get error = %v", err)
+	}
+
+	if _, _, err := trackProbeIngress(context.Background(), cliset, GetNamespace(), probeIngressGenerateName+"late"); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("trackProbeIngress() after Shutdown() error = %v, want ErrShuttingDown", err)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiscoveryOutcome is how the last domain suffix discovery of a namespace ended.
+type DiscoveryOutcome string
+
+const (
+	// DiscoveryOutcomeConfigured means the domain suffix was already set in the
+	// network config.
+	DiscoveryOutcomeConfigured DiscoveryOutcome = "configured"
+	// DiscoveryOutcomeOverridden means the domain suffix was forced by the
+	// DomainSuffixEnvKey environment variable.
+	DiscoveryOutcomeOverridden DiscoveryOutcome = "overridden"
+	// DiscoveryOutcomeDiscovered means the domain suffix was generated from the
+	// ingress address.
+	DiscoveryOutcomeDiscovered DiscoveryOutcome = "discovered"
+	// DiscoveryOutcomeFailed means the discovery returned an error.
+	DiscoveryOutcomeFailed DiscoveryOutcome = "failed"
+)
+
+// DiscoveryState is the latest domain suffix discovery result of a namespace.
+type DiscoveryState struct {
+	Namespace     string           `json:"namespace"`
+	CorrelationID string           `json:"correlationID"`
+	Outcome       DiscoveryOutcome `json:"outcome"`
+	Timestamp     time.Time        `json:"timestamp"`
+	DomainSuffix  string           `json:"domainSuffix,omitempty"`
+	// ReverseNames are the reverse DNS names of the discovered IP, when the
+	// reverse lookup is enabled and succeeds.
+	ReverseNames []string `json:"reverseNames,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+type discoveryRegistry struct {
+	mu     sync.RWMutex
+	states map[string]DiscoveryState
+}
+
+var defaultDiscoveryRegistry = &discoveryRegistry{
+	states: make(map[string]DiscoveryState),
+}
+
+// record records the result of a discovery of the namespace. If it succeeded,
+// publish, if not nil, is called with whether the domain suffix differs from the
+// previous one of the namespace. It is called under the lock of the swap, so that
+// a change is published exactly once, and the results of concurrent discoveries
+// in the order they are recorded.
+func (r *discoveryRegistry) record(namespace, correlationID string, outcome DiscoveryOutcome, domainSuffix string, reverseNames []string, err error, publish func(changed bool)) {
+	state := DiscoveryState{
+		Namespace:     namespace,
+		CorrelationID: correlationID,
+		Outcome:       outcome,
+		Timestamp:     time.Now(),
+		DomainSuffix:  domainSuffix,
+		ReverseNames:  reverseNames,
+	}
+	if err != nil {
+		state.Outcome = DiscoveryOutcomeFailed
+		state.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.states[namespace]
+	r.states[namespace] = state
+	if err == nil && publish != nil {
+		publish(domainSuffix != previous.DomainSuffix)
+	}
+}
+
+// GetDiscoveryState returns the latest domain suffix discovery state recorded for
+// the namespace, without querying the cluster.
+func GetDiscoveryState(namespace string) (state DiscoveryState, ok bool) {
+	defaultDiscoveryRegistry.mu.RLock()
+	defer defaultDiscoveryRegistry.mu.RUnlock()
+	state, ok = defaultDiscoveryRegistry.states[namespace]
+	return
+}
+
+// ListDiscoveryStates returns the latest domain suffix discovery state of every
+// namespace, sorted by namespace.
+func ListDiscoveryStates() []DiscoveryState {
+	defaultDiscoveryRegistry.mu.RLock()
+	states := make([]DiscoveryState, 0, len(defaultDiscoveryRegistry.states))
+	for _, state := range defaultDiscoveryRegistry.states {
+		states = append(states, state)
+	}
+	defaultDiscoveryRegistry.mu.RUnlock()
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Namespace < states[j].Namespace
+	})
+	return states
+}
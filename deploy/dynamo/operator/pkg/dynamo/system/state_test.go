@@ -0,0 +1,109 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package system
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestRecordDiscoveryState(t *testing.T) {
+	defaultDiscoveryRegistry.record("state-b", "id-b", DiscoveryOutcomeDiscovered, "b.example.com", []string{"lb.example.com"}, nil, nil)
+	defaultDiscoveryRegistry.record("state-a", "id-a", DiscoveryOutcomeDiscovered, "", nil, errors.New("no address"), nil)
+
+	state, ok := GetDiscoveryState("state-b")
+	if !ok {
+		t.Fatal("GetDiscoveryState(state-b) found no state")
+	}
+	if state.Outcome != DiscoveryOutcomeDiscovered || state.DomainSuffix != "b.example.com" || state.CorrelationID != "id-b" || state.Error != "" {
+		t.Errorf("GetDiscoveryState(state-b) = %+v, want a discovered b.example.com", state)
+	}
+	if state.Timestamp.IsZero() {
+		t.Error("the recorded state has no timestamp")
+	}
+
+	state, _ = GetDiscoveryState("state-a")
+	if state.Outcome != DiscoveryOutcomeFailed || state.Error != "no address" {
+		t.Errorf("GetDiscoveryState(state-a) = %+v, want the failed outcome with its error", state)
+	}
+
+	if _, ok := GetDiscoveryState("state-unknown"); ok {
+		t.Error("GetDiscoveryState(state-unknown) found a state, want none")
+	}
+
+	var namespaces []string
+	states := ListDiscoveryStates()
+	for i, state := range states {
+		if i > 0 && states[i-1].Namespace > state.Namespace {
+			t.Errorf("ListDiscoveryStates() is not sorted: %s before %s", states[i-1].Namespace, state.Namespace)
+		}
+		if state.Namespace == "state-a" || state.Namespace == "state-b" {
+			namespaces = append(namespaces, state.Namespace)
+		}
+	}
+	if len(namespaces) != 2 || namespaces[0] != "state-a" || namespaces[1] != "state-b" {
+		t.Errorf("ListDiscoveryStates() has %v, want state-a then state-b", namespaces)
+	}
+}
+
+func TestRecordPublishesChangeOnce(t *testing.T) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var changes int
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defaultDiscoveryRegistry.record("state-concurrent", "id", DiscoveryOutcomeDiscovered, "c.example.com", nil, nil, func(changed bool) {
+				if changed {
+					mu.Lock()
+					changes++
+					mu.Unlock()
+				}
+			})
+		}()
+	}
+	wg.Wait()
+	if changes != 1 {
+		t.Errorf("the concurrent discoveries of the same domain suffix published %d changes, want 1", changes)
+	}
+
+	defaultDiscoveryRegistry.record("state-concurrent", "id", DiscoveryOutcomeDiscovered, "", nil, errors.New("no address"), func(bool) {
+		t.Error("a failed discovery was published")
+	})
+}
+
+func TestGetDomainSuffixRecordsState(t *testing.T) {
+	configMap := newNetworkConfigMap(map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "configured.example.com"})
+	configMap.Namespace = "state-configured"
+	ctx := WithNamespace(context.Background(), "state-configured")
+	if _, err := GetDomainSuffix(ctx, staticConfigMapGetter(configMap), newLoadBalancerClientset()); err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+
+	state, ok := GetDiscoveryState("state-configured")
+	if !ok {
+		t.Fatal("GetDomainSuffix() recorded no state")
+	}
+	if state.Outcome != DiscoveryOutcomeConfigured || state.DomainSuffix != "configured.example.com" {
+		t.Errorf("the recorded state is %+v, want the configured configured.example.com", state)
+	}
+}
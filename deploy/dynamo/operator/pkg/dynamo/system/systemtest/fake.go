@@ -0,0 +1,81 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package systemtest
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/system"
+)
+
+// FakeIngressClassName is the default ingress class of the clientsets returned
+// by NewFakeClientsetWithReadyIngress.
+const FakeIngressClassName = "nginx"
+
+// NewFakeConfigMapGetter returns a configmap getter serving the network configmap
+// of the system namespace with the data. The other configmaps aren't found.
+func NewFakeConfigMapGetter(data map[string]string) func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	return func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		if namespace != system.GetNamespace() || name != consts.KubeConfigMapNameNetworkConfig {
+			return nil, k8serrors.NewNotFound(corev1.Resource("configmaps"), name)
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Data: make(map[string]string, len(data)),
+		}
+		for key, value := range data {
+			configMap.Data[key] = value
+		}
+		return configMap, nil
+	}
+}
+
+// NewFakeClientsetWithReadyIngress returns a fake clientset with a default
+// ingress class, FakeIngressClassName, whose ingresses get a load balancer
+// status with the ip as soon as they are created, so that the discovery
+// succeeds right away.
+func NewFakeClientsetWithReadyIngress(ip string) *fake.Clientset {
+	cliset := fake.NewSimpleClientset(&networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        FakeIngressClassName,
+			Annotations: map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"},
+		},
+		Spec: networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	})
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ing := action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress)
+		if ing.Name == "" {
+			ing.Name = ing.GenerateName + "fake"
+		}
+		ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: ip}}
+		return false, nil, nil
+	})
+	return cliset
+}
@@ -0,0 +1,74 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package systemtest_test
+
+import (
+	"context"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/system"
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/system/systemtest"
+)
+
+func TestNewFakeConfigMapGetter(t *testing.T) {
+	data := map[string]string{consts.KubeConfigMapKeyNetworkConfigIngressClass: "nginx"}
+	getter := systemtest.NewFakeConfigMapGetter(data)
+
+	configMap, err := getter(context.Background(), system.GetNamespace(), consts.KubeConfigMapNameNetworkConfig)
+	if err != nil {
+		t.Fatalf("getter() error = %v", err)
+	}
+	if configMap.Data[consts.KubeConfigMapKeyNetworkConfigIngressClass] != "nginx" {
+		t.Errorf("getter() data = %v, want %v", configMap.Data, data)
+	}
+	configMap.Data["changed"] = "true"
+	if _, ok := data["changed"]; ok {
+		t.Errorf("changing the configmap returned by getter() changed the data it was created with")
+	}
+
+	_, err = getter(context.Background(), system.GetNamespace(), "other")
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("getter() of another configmap error = %v, want a NotFound error", err)
+	}
+}
+
+func TestNewFakeClientsetWithReadyIngress(t *testing.T) {
+	ctx := context.Background()
+	getter := systemtest.NewFakeConfigMapGetter(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+
+	ip, err := system.GetIngressIP(ctx, getter, systemtest.NewFakeClientsetWithReadyIngress("10.0.0.1"))
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.1" {
+		t.Errorf("GetIngressIP() = %q, want %q", ip, "10.0.0.1")
+	}
+
+	domainSuffix, err := system.GetDomainSuffix(ctx, getter, systemtest.NewFakeClientsetWithReadyIngress("10.0.0.2"))
+	if err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+	if want := "10.0.0.2." + system.GetMagicDNS(); domainSuffix != want {
+		t.Errorf("GetDomainSuffix() = %q, want %q", domainSuffix, want)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package systemtest provides test doubles for code calling the system package,
+// so that it can be tested against the real discovery code paths.
+package systemtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// Fault makes the matching requests of a FaultingClient fail or stall.
+type Fault struct {
+	// Verb ("create", "get", "patch", "delete", ...) and Resource
+	// ("ingresses", "configmaps", ...) select the requests, "*" matches any.
+	Verb     string
+	Resource string
+	// Err is returned instead of performing the request, if set.
+	Err error
+	// Delay is slept before the request is performed or fails. It holds the
+	// fake clientset lock, so it also delays concurrent requests.
+	Delay time.Duration
+	// Times is how many requests the fault applies to, zero meaning all of
+	// them.
+	Times int
+}
+
+// FaultingClient is a fake clientset that fails or delays requests according to
+// the injected faults, e.g. to test timeout, conflict and forbidden handling.
+type FaultingClient struct {
+	*fake.Clientset
+
+	mu     sync.Mutex
+	faults []*Fault
+}
+
+// NewFaultingClient returns a FaultingClient seeded with the objects.
+func NewFaultingClient(objects ...runtime.Object) *FaultingClient {
+	c := &FaultingClient{
+		Clientset: fake.NewSimpleClientset(objects...),
+	}
+	c.PrependReactor("*", "*", c.react)
+	return c
+}
+
+// Inject adds a fault, checked after the previously injected ones.
+func (c *FaultingClient) Inject(fault Fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults = append(c.faults, &fault)
+}
+
+// Reset removes all the faults.
+func (c *FaultingClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults = nil
+}
+
+func (c *FaultingClient) react(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+	fault := c.match(action)
+	if fault == nil {
+		return false, nil, nil
+	}
+	if fault.Delay > 0 {
+		time.Sleep(fault.Delay)
+	}
+	if fault.Err != nil {
+		return true, nil, fault.Err
+	}
+	return false, nil, nil
+}
+
+func (c *FaultingClient) match(action k8stesting.Action) *Fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, fault := range c.faults {
+		if (fault.Verb != "*" && fault.Verb != action.GetVerb()) || (fault.Resource != "*" && fault.Resource != action.GetResource().Resource) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				c.faults = append(c.faults[:i], c.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+// Forbidden returns the error of a request denied by RBAC.
+func Forbidden(resource, name string) error {
+	return k8serrors.NewForbidden(schema.GroupResource{Resource: resource}, name, errors.New("injected fault"))
+}
+
+// Conflict returns the error of a write with a stale resource version.
+func Conflict(resource, name string) error {
+	return k8serrors.NewConflict(schema.GroupResource{Resource: resource}, name, errors.New("injected fault"))
+}
+
+// ServerTimeout returns the error of a request the API server couldn't complete
+// in time.
+func ServerTimeout(resource, verb string) error {
+	return k8serrors.NewServerTimeout(schema.GroupResource{Resource: resource}, verb, 1)
+}
@@ -0,0 +1,84 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package systemtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/system/systemtest"
+)
+
+func TestFaultingClient(t *testing.T) {
+	ctx := context.Background()
+	cli := systemtest.NewFaultingClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}})
+	configMaps := cli.CoreV1().ConfigMaps("default")
+
+	if _, err := configMaps.Get(ctx, "config", metav1.GetOptions{}); err != nil {
+		t.Fatalf("Get() without a fault error = %v", err)
+	}
+
+	cli.Inject(systemtest.Fault{Verb: "get", Resource: "configmaps", Err: systemtest.Forbidden("configmaps", "config"), Times: 2})
+	cli.Inject(systemtest.Fault{Verb: "*", Resource: "*", Err: systemtest.ServerTimeout("configmaps", "update")})
+	for i := 0; i < 2; i++ {
+		if _, err := configMaps.Get(ctx, "config", metav1.GetOptions{}); !k8serrors.IsForbidden(err) {
+			t.Errorf("Get() %d error = %v, want the injected Forbidden error", i, err)
+		}
+	}
+	// The get fault is used up, so the catch-all one applies.
+	if _, err := configMaps.Get(ctx, "config", metav1.GetOptions{}); !k8serrors.IsServerTimeout(err) {
+		t.Errorf("Get() after the get fault error = %v, want the injected ServerTimeout error", err)
+	}
+	if _, err := configMaps.Update(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}, metav1.UpdateOptions{}); !k8serrors.IsServerTimeout(err) {
+		t.Errorf("Update() error = %v, want the injected ServerTimeout error", err)
+	}
+
+	cli.Reset()
+	if _, err := configMaps.Get(ctx, "config", metav1.GetOptions{}); err != nil {
+		t.Errorf("Get() after Reset() error = %v", err)
+	}
+}
+
+func TestFaultingClientDelay(t *testing.T) {
+	ctx := context.Background()
+	cli := systemtest.NewFaultingClient()
+	cli.Inject(systemtest.Fault{Verb: "create", Resource: "configmaps", Delay: 50 * time.Millisecond, Times: 1})
+
+	start := time.Now()
+	_, err := cli.CoreV1().ConfigMaps("default").Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config"}}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() with a delay error = %v, want the request performed", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Create() took %s, want the injected delay of 50ms", elapsed)
+	}
+	if _, err := cli.CoreV1().ConfigMaps("default").Get(ctx, "config", metav1.GetOptions{}); err != nil {
+		t.Errorf("Get() of the delayed created configmap error = %v", err)
+	}
+}
+
+func TestConflict(t *testing.T) {
+	if err := systemtest.Conflict("configmaps", "config"); !k8serrors.IsConflict(err) {
+		t.Errorf("Conflict() = %v, want a Conflict error", err)
+	}
+}
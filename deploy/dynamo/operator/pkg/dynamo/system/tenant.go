@@ -0,0 +1,86 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	baseDomainCacheMu sync.RWMutex
+	baseDomainCache   = make(map[string]string)
+)
+
+// ComposeTenantDomain returns `<tenant>.<baseDomain>`, checking that the tenant is
+// a DNS label and that the result is a valid DNS name.
+func ComposeTenantDomain(tenant, baseDomain string) (string, error) {
+	tenant = strings.ToLower(strings.TrimSpace(tenant))
+	if errs := validation.IsDNS1123Label(tenant); len(errs) > 0 {
+		return "", errors.Errorf("invalid tenant %q: %s", tenant, strings.Join(errs, ", "))
+	}
+	domain := joinDomain(tenant, baseDomain)
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return "", errors.Errorf("invalid domain %q of tenant %s: %s", domain, tenant, strings.Join(errs, ", "))
+	}
+	return domain, nil
+}
+
+// GetTenantDomain returns the subdomain of the tenant under the cluster base
+// domain, which is the domain suffix of the system namespace. The base domain is
+// discovered once and cached until ResetDomainSuffix is called.
+func GetTenantDomain(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface, tenant string) (string, error) {
+	baseDomain, err := getBaseDomain(ctx, configmapGetter, cliset)
+	if err != nil {
+		return "", err
+	}
+	return ComposeTenantDomain(tenant, baseDomain)
+}
+
+func getBaseDomain(ctx context.Context, configmapGetter func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error), cliset kubernetes.Interface) (string, error) {
+	namespace := namespaceFromContext(ctx)
+
+	baseDomainCacheMu.RLock()
+	baseDomain, ok := baseDomainCache[namespace]
+	baseDomainCacheMu.RUnlock()
+	if ok {
+		return baseDomain, nil
+	}
+
+	baseDomain, err := GetDomainSuffix(ctx, configmapGetter, cliset)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the cluster base domain")
+	}
+
+	baseDomainCacheMu.Lock()
+	defer baseDomainCacheMu.Unlock()
+	baseDomainCache[namespace] = baseDomain
+	return baseDomain, nil
+}
+
+func forgetBaseDomain(namespace string) {
+	baseDomainCacheMu.Lock()
+	defer baseDomainCacheMu.Unlock()
+	delete(baseDomainCache, namespace)
+}
@@ -0,0 +1,114 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestComposeTenantDomain(t *testing.T) {
+	tests := []struct {
+		name       string
+		tenant     string
+		baseDomain string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "valid",
+			tenant:     " Team-A ",
+			baseDomain: "example.com",
+			want:       "team-a.example.com",
+		},
+		{
+			name:       "invalid tenant",
+			tenant:     "team_a",
+			baseDomain: "example.com",
+			wantErr:    "invalid tenant",
+		},
+		{
+			name:       "over-long tenant",
+			tenant:     strings.Repeat("a", 64),
+			baseDomain: "example.com",
+			wantErr:    "invalid tenant",
+		},
+		{
+			name:       "over-long domain",
+			tenant:     "team-a",
+			baseDomain: strings.Repeat(strings.Repeat("b", 60)+".", 4) + "com",
+			wantErr:    "invalid domain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComposeTenantDomain(tt.tenant, tt.baseDomain)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ComposeTenantDomain() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ComposeTenantDomain() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTenantDomainCachesBaseDomain(t *testing.T) {
+	ctx := WithNamespace(context.Background(), "tenant-cache")
+	defer forgetBaseDomain("tenant-cache")
+	configMap := newNetworkConfigMap(map[string]string{consts.KubeConfigMapKeyNetworkConfigDomainSuffix: "a.example.com"})
+	configMap.Namespace = "tenant-cache"
+	var calls int
+	configmapGetter := func(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+		calls++
+		return configMap.DeepCopy(), nil
+	}
+	cliset := newLoadBalancerClientset()
+
+	domain, err := GetTenantDomain(ctx, configmapGetter, cliset, "team-a")
+	if err != nil || domain != "team-a.a.example.com" {
+		t.Fatalf("GetTenantDomain() = %q, %v, want team-a.a.example.com", domain, err)
+	}
+
+	// The base domain is cached, so a change of the network config isn't seen.
+	configMap.Data[consts.KubeConfigMapKeyNetworkConfigDomainSuffix] = "b.example.com"
+	calls = 0
+	if domain, err = GetTenantDomain(ctx, configmapGetter, cliset, "team-b"); err != nil || domain != "team-b.a.example.com" {
+		t.Errorf("GetTenantDomain() cached = %q, %v, want team-b.a.example.com", domain, err)
+	}
+	if calls != 0 {
+		t.Errorf("GetTenantDomain() cached got the network configmap %d times, want 0", calls)
+	}
+	if _, err = GetTenantDomain(ctx, configmapGetter, cliset, "team_b"); err == nil {
+		t.Error("GetTenantDomain() of an invalid tenant error = nil, want an error")
+	}
+
+	forgetBaseDomain("tenant-cache")
+	if domain, err = GetTenantDomain(ctx, configmapGetter, cliset, "team-a"); err != nil || domain != "team-a.b.example.com" {
+		t.Errorf("GetTenantDomain() after forgetBaseDomain() = %q, %v, want team-a.b.example.com", domain, err)
+	}
+}
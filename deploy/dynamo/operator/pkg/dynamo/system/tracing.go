@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the discovery spans.
+const tracerName = "github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/system"
+
+// The spans of the discovery.
+const (
+	spanGetDomainSuffix         = "GetDomainSuffix"
+	spanPatchDomainSuffix       = "PatchDomainSuffix"
+	spanGetIngressIP            = "GetIngressIP"
+	spanCreateProbeIngress      = "CreateProbeIngress"
+	spanWaitForIngressReady     = "WaitForIngressReady"
+	spanResolveIngressAddresses = "ResolveIngressAddresses"
+)
+
+// The attributes of the discovery spans.
+const (
+	spanAttrNamespace    = attribute.Key("dynamo.discovery.namespace")
+	spanAttrIngressClass = attribute.Key("dynamo.discovery.ingress_class")
+	spanAttrProbeIngress = attribute.Key("dynamo.discovery.probe_ingress")
+	spanAttrPollCount    = attribute.Key("dynamo.discovery.poll_count")
+	spanAttrOutcome      = attribute.Key("dynamo.discovery.outcome")
+)
+
+// startSpan starts a span with the tracer provider of the span of ctx, so that
+// the discovery is traced as part of the caller's trace, and not at all if ctx
+// has no span.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends the span, recording err if any.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceSpan runs fn in a span.
+func traceSpan(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...attribute.KeyValue) (err error) {
+	ctx, span := startSpan(ctx, name, attrs...)
+	defer func() {
+		endSpan(span, err)
+	}()
+	return fn(ctx)
+}
+
+type pollCountKey struct{}
+
+// traceWait is traceSpan for a wait, recording how many times it polled.
+func traceWait(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...attribute.KeyValue) error {
+	polls := new(atomic.Int64)
+	ctx = context.WithValue(ctx, pollCountKey{}, polls)
+	return traceSpan(ctx, name, func(ctx context.Context) error {
+		defer func() {
+			trace.SpanFromContext(ctx).SetAttributes(spanAttrPollCount.Int64(polls.Load()))
+		}()
+		return fn(ctx)
+	}, attrs...)
+}
+
+// countPoll counts a poll of the wait traced by the context, if any.
+func countPoll(ctx context.Context) {
+	if polls, ok := ctx.Value(pollCountKey{}).(*atomic.Int64); ok {
+		polls.Add(1)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestGetDomainSuffixSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+	})
+	// The probe only gets its address once polled, so that it is waited for.
+	cliset := newLoadBalancerClientset()
+	var probeName string
+	cliset.PrependReactor("get", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, err := cliset.Tracker().Get(networkingv1.SchemeGroupVersion.WithResource("ingresses"), action.GetNamespace(), action.(k8stesting.GetAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		ing := obj.(*networkingv1.Ingress)
+		probeName = ing.Name
+		ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}
+		return true, ing, nil
+	})
+	if err := cliset.Tracker().Add(configMap); err != nil {
+		t.Fatalf("failed to add the network configmap: %v", err)
+	}
+
+	ctx, root := provider.Tracer("test").Start(context.Background(), "reconcile")
+	if _, err := GetDomainSuffix(ctx, staticConfigMapGetter(configMap), cliset); err != nil {
+		t.Fatalf("GetDomainSuffix() error = %v", err)
+	}
+	root.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		if _, ok := spans[span.Name()]; ok {
+			t.Fatalf("span %s is recorded more than once", span.Name())
+		}
+		spans[span.Name()] = span
+	}
+	parents := map[string]string{
+		spanGetDomainSuffix:         "reconcile",
+		spanGetIngressIP:            spanGetDomainSuffix,
+		spanCreateProbeIngress:      spanGetIngressIP,
+		spanWaitForIngressReady:     spanGetIngressIP,
+		spanResolveIngressAddresses: spanGetIngressIP,
+		spanPatchDomainSuffix:       spanGetDomainSuffix,
+	}
+	for name, parent := range parents {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("span %s isn't recorded", name)
+			continue
+		}
+		if got, want := span.Parent().SpanID(), spans[parent].SpanContext().SpanID(); got != want {
+			t.Errorf("parent of span %s = %s, want the span %s %s", name, got, parent, want)
+		}
+	}
+
+	wantAttrs := map[string]map[string]string{
+		spanGetDomainSuffix: {
+			string(spanAttrNamespace): GetNamespace(),
+			string(spanAttrOutcome):   string(DiscoveryOutcomeDiscovered),
+		},
+		spanGetIngressIP: {
+			string(spanAttrNamespace):    GetNamespace(),
+			string(spanAttrIngressClass): "nginx",
+			string(spanAttrProbeIngress): probeName,
+		},
+	}
+	for name, want := range wantAttrs {
+		got := make(map[string]string)
+		for _, attr := range spans[name].Attributes() {
+			got[string(attr.Key)] = attr.Value.Emit()
+		}
+		for key, value := range want {
+			if got[key] != value {
+				t.Errorf("attribute %s of span %s = %q, want %q", key, name, got[key], value)
+			}
+		}
+	}
+
+	var polls int64 = -1
+	for _, attr := range spans[spanWaitForIngressReady].Attributes() {
+		if attr.Key == spanAttrPollCount {
+			polls = attr.Value.AsInt64()
+		}
+	}
+	if polls < 1 {
+		t.Errorf("attribute %s of span %s = %d, want at least 1", spanAttrPollCount, spanWaitForIngressReady, polls)
+	}
+}
+
+func TestStartSpanWithoutTracer(t *testing.T) {
+	ctx, span := startSpan(context.Background(), spanGetIngressIP)
+	defer span.End()
+	if span.IsRecording() || trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Errorf("startSpan() without a tracer = a recording span, want a no-op one")
+	}
+}
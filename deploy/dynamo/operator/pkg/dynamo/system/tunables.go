@@ -0,0 +1,192 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"flag"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// discoveryTunables are the process-wide defaults of the discovery. The poll
+// interval, wait timeout and provisioning budget can be overridden per namespace
+// in the network config.
+type discoveryTunables struct {
+	// PollInterval is how often the probe ingress status is checked.
+	PollInterval time.Duration
+	// WaitTimeout is how long to wait for the probe ingress to get an address.
+	WaitTimeout time.Duration
+	// Concurrency is the maximum number of discoveries run at the same time by
+	// this process, 0 means unlimited.
+	Concurrency int
+	// ProvisioningBudget bounds the whole discovery, including the preflight
+	// checks and the hostname resolution, 0 means unbounded.
+	ProvisioningBudget time.Duration
+	// GCInterval is the base interval of StartProbeIngressGC, and JitterFactor
+	// spreads the runs of the background schedules over up to
+	// interval*(1+JitterFactor), so that the operators of a fleet don't all run
+	// them at the same time after a config change. The probe status polls are
+	// jittered by the same factor, around the poll interval.
+	GCInterval   time.Duration
+	JitterFactor float64
+	// PostHookCommand is run after every domain suffix discovery, see
+	// runPostDiscoveryHooks. It is run in the operator pod, so only the operator
+	// sets it.
+	PostHookCommand string
+}
+
+// postHookCommandFlag is the flag of discoveryTunables.PostHookCommand.
+const postHookCommandFlag = "domain-suffix-discovery-post-hook-command"
+
+var defaultDiscoveryTunables = discoveryTunables{
+	PollInterval: 10 * time.Second,
+	WaitTimeout:  20 * time.Minute,
+	GCInterval:   10 * time.Minute,
+	JitterFactor: 0.2,
+}
+
+// RegisterFlags registers the discovery tunables on the flag set. The flags are the
+// lowest precedence: the keys of the network config override them.
+func RegisterFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&defaultDiscoveryTunables.PollInterval, "domain-suffix-discovery-poll-interval", defaultDiscoveryTunables.PollInterval, "How often to check whether the domain suffix probe ingress got an address.")
+	fs.DurationVar(&defaultDiscoveryTunables.WaitTimeout, "domain-suffix-discovery-wait-timeout", defaultDiscoveryTunables.WaitTimeout, "How long to wait for the domain suffix probe ingress to get an address.")
+	fs.IntVar(&defaultDiscoveryTunables.Concurrency, "domain-suffix-discovery-concurrency", defaultDiscoveryTunables.Concurrency, "The maximum number of domain suffix discoveries to run at the same time, 0 means unlimited.")
+	fs.DurationVar(&defaultDiscoveryTunables.ProvisioningBudget, "domain-suffix-discovery-provisioning-budget", defaultDiscoveryTunables.ProvisioningBudget, "The maximum duration of a whole domain suffix discovery, 0 means unbounded.")
+	fs.DurationVar(&defaultDiscoveryTunables.GCInterval, "probe-ingress-gc-interval", defaultDiscoveryTunables.GCInterval, "The base interval of the garbage collection of the leaked domain suffix probe ingresses.")
+	fs.StringVar(&defaultDiscoveryTunables.PostHookCommand, postHookCommandFlag, defaultDiscoveryTunables.PostHookCommand, "The command to run after every domain suffix discovery, with the result in its DYNAMO_DOMAIN_SUFFIX, DYNAMO_INGRESS_IP and DYNAMO_NAMESPACE environment variables. It is split on whitespace and not run through a shell.")
+	fs.Float64Var(&defaultDiscoveryTunables.JitterFactor, "domain-suffix-discovery-jitter-factor", defaultDiscoveryTunables.JitterFactor, "The jitter factor of the background domain suffix schedules and of the probe status polls, 0 disables the jitter.")
+}
+
+// RegisterGoFlags is RegisterFlags for a flag set of the standard library, such as
+// the flag.CommandLine of the operator binary.
+func RegisterGoFlags(fs *flag.FlagSet) {
+	pfs := pflag.NewFlagSet(fs.Name(), pflag.ContinueOnError)
+	RegisterFlags(pfs)
+	pfs.VisitAll(func(f *pflag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+}
+
+// jitterUntil runs f every interval, jittered by the configured factor, until
+// ctx is done.
+func jitterUntil(ctx context.Context, f func(ctx context.Context), interval time.Duration) {
+	wait.JitterUntilWithContext(ctx, f, interval, defaultDiscoveryTunables.JitterFactor, true)
+}
+
+// pollJittered is wait.PollUntilContextTimeout with every interval jittered by the
+// configured factor, so that the operators restarted together don't all query the
+// API server at the same time. The intervals and the timeout follow the clock set
+// with SetClock. The progress callback of the context, if any, is called before
+// each poll.
+func pollJittered(ctx context.Context, interval, timeout time.Duration, immediate bool, condition wait.ConditionWithContextFunc) error {
+	clk := getClock()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// The timeout cancels ctx, rather than only being checked between the
+	// polls, so that a condition blocked on the API server is bounded too.
+	timeoutTimer := clk.NewTimer(timeout)
+	defer timeoutTimer.Stop()
+	go func() {
+		select {
+		case <-timeoutTimer.C():
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+		}
+	}()
+
+	uncounted := condition
+	condition = func(ctx context.Context) (bool, error) {
+		countPoll(ctx)
+		return uncounted(ctx)
+	}
+
+	if onProgress := progressFromContext(ctx); onProgress != nil {
+		start, attempt, poll := clk.Now(), 0, condition
+		condition = func(ctx context.Context) (bool, error) {
+			attempt++
+			onProgress(clk.Since(start), attempt)
+			return poll(ctx)
+		}
+	}
+
+	if immediate {
+		if done, err := condition(ctx); err != nil || done {
+			return err
+		}
+	}
+	timer := clk.NewTimer(jitterInterval(interval, defaultDiscoveryTunables.JitterFactor))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-timer.C():
+		}
+		if done, err := condition(ctx); err != nil || done {
+			if err != nil && ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
+			return err
+		}
+		timer.Reset(jitterInterval(interval, defaultDiscoveryTunables.JitterFactor))
+	}
+}
+
+// jitterInterval returns a random duration within interval*(1±factor/2), the
+// factor being capped to 1, so that the average stays the interval.
+func jitterInterval(interval time.Duration, factor float64) time.Duration {
+	if factor <= 0 {
+		return interval
+	}
+	if factor > 1 {
+		factor = 1
+	}
+	return interval + time.Duration((rand.Float64()-0.5)*factor*float64(interval))
+}
+
+var (
+	discoverySlotsOnce sync.Once
+	discoverySlots     chan struct{}
+)
+
+// acquireDiscoverySlot blocks until fewer than the configured number of
+// discoveries are running, and returns the function that releases the slot.
+func acquireDiscoverySlot(ctx context.Context) (release func(), err error) {
+	discoverySlotsOnce.Do(func() {
+		if defaultDiscoveryTunables.Concurrency > 0 {
+			discoverySlots = make(chan struct{}, defaultDiscoveryTunables.Concurrency)
+		}
+	})
+	if discoverySlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case discoverySlots <- struct{}{}:
+		return func() { <-discoverySlots }, nil
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "failed to wait for a free domain suffix discovery slot")
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestJitterInterval(t *testing.T) {
+	const interval = 10 * time.Second
+
+	if got := jitterInterval(interval, 0); got != interval {
+		t.Errorf("jitterInterval() = %s without jitter, want %s", got, interval)
+	}
+
+	for _, factor := range []float64{0.2, 1, 3} {
+		capped := factor
+		if capped > 1 {
+			capped = 1
+		}
+		low := time.Duration(float64(interval) * (1 - capped/2))
+		high := time.Duration(float64(interval) * (1 + capped/2))
+
+		const samples = 10000
+		var sum time.Duration
+		for i := 0; i < samples; i++ {
+			got := jitterInterval(interval, factor)
+			if got < low || got > high {
+				t.Fatalf("jitterInterval(%v) = %s, want within [%s, %s]", factor, got, low, high)
+			}
+			sum += got
+		}
+		if mean := sum / samples; mean < interval*98/100 || mean > interval*102/100 {
+			t.Errorf("jitterInterval(%v) mean = %s, want close to %s", factor, mean, interval)
+		}
+	}
+}
+
+func TestPollJittered(t *testing.T) {
+	var calls int
+	err := pollJittered(context.Background(), time.Millisecond, time.Minute, false, func(context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("pollJittered() = %v after %d calls, want nil after 3", err, calls)
+	}
+
+	err = pollJittered(context.Background(), time.Millisecond, 20*time.Millisecond, true, func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("pollJittered() = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	defaults := defaultDiscoveryTunables
+	defer func() { defaultDiscoveryTunables = defaults }()
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterFlags(fs)
+	if err := fs.Parse([]string{
+		"--domain-suffix-discovery-poll-interval=3s",
+		"--domain-suffix-discovery-wait-timeout=1m",
+		"--domain-suffix-discovery-concurrency=4",
+		"--domain-suffix-discovery-jitter-factor=0",
+	}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if defaultDiscoveryTunables.PollInterval != 3*time.Second || defaultDiscoveryTunables.WaitTimeout != time.Minute || defaultDiscoveryTunables.Concurrency != 4 || defaultDiscoveryTunables.JitterFactor != 0 {
+		t.Errorf("the tunables are %+v after parsing the flags", defaultDiscoveryTunables)
+	}
+	if defaultDiscoveryTunables.GCInterval != defaults.GCInterval {
+		t.Errorf("GCInterval = %s, want the default %s of an unset flag", defaultDiscoveryTunables.GCInterval, defaults.GCInterval)
+	}
+
+	// The network config overrides the flags.
+	config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "5s",
+	}))
+	if err != nil {
+		t.Fatalf("parseDiscoveryConfig() error = %v", err)
+	}
+	if config.PollInterval != 5*time.Second || config.WaitTimeout != time.Minute {
+		t.Errorf("parseDiscoveryConfig() = %s, %s, want the 5s of the network config and the 1m of the flag", config.PollInterval, config.WaitTimeout)
+	}
+}
+
+func TestRegisterGoFlags(t *testing.T) {
+	defaults := defaultDiscoveryTunables
+	defer func() { defaultDiscoveryTunables = defaults }()
+
+	fs := flag.NewFlagSet("operator", flag.ContinueOnError)
+	leaderElect := fs.Bool("leader-elect", false, "")
+	RegisterGoFlags(fs)
+	if err := fs.Parse([]string{
+		"--leader-elect",
+		"--domain-suffix-discovery-poll-interval=3s",
+		"-domain-suffix-discovery-wait-timeout", "1m",
+		"--" + postHookCommandFlag + "=notify-dns",
+	}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !*leaderElect || defaultDiscoveryTunables.PostHookCommand != "notify-dns" {
+		t.Errorf("the tunables are %+v and leader-elect %t after parsing the flags", defaultDiscoveryTunables, *leaderElect)
+	}
+
+	// The network config overrides the flags.
+	config, err := parseDiscoveryConfig(newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "5s",
+	}))
+	if err != nil {
+		t.Fatalf("parseDiscoveryConfig() error = %v", err)
+	}
+	if config.PollInterval != 5*time.Second || config.WaitTimeout != time.Minute {
+		t.Errorf("parseDiscoveryConfig() = %s, %s, want the 5s of the network config and the 1m of the flag", config.PollInterval, config.WaitTimeout)
+	}
+}
+
+func TestJitterUntil(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan struct{}, 3)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jitterUntil(ctx, func(context.Context) {
+			select {
+			case runs <- struct{}{}:
+			default:
+				cancel()
+			}
+		}, time.Millisecond)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("jitterUntil() didn't return once its context was canceled")
+	}
+	if len(runs) != 3 {
+		t.Errorf("jitterUntil() ran %d times before the cancellation, want 3", len(runs))
+	}
+}
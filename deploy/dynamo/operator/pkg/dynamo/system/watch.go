@@ -0,0 +1,118 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ingressWatchError is returned by watchIngressReady when the watch itself fails,
+// rather than the ingress.
+type ingressWatchError struct {
+	Err error
+}
+
+func (e *ingressWatchError) Error() string {
+	return "failed to watch the ingress: " + e.Err.Error()
+}
+
+func (e *ingressWatchError) Unwrap() error {
+	return e.Err
+}
+
+// watchOrWaitForIngressReady waits for the ingress to be ready with a watch, and
+// with the polls of waitForIngressReady for the rest of the wait timeout if the
+// watch fails.
+func watchOrWaitForIngressReady(ctx context.Context, logger logr.Logger, ingressCli IngressClient, name string, config *discoveryConfig, checker ReadinessChecker) (*networkingv1.Ingress, error) {
+	clk := getClock()
+	deadline := clk.Now().Add(config.WaitTimeout)
+	ing, err := watchIngressReady(ctx, ingressCli, name, config.WaitTimeout, checker)
+	var watchErr *ingressWatchError
+	if !errors.As(err, &watchErr) {
+		return ing, err
+	}
+
+	remaining := deadline.Sub(clk.Now())
+	if remaining <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	logger.Error(err, "Falling back to polling the ingress", "ingress", name)
+	return waitForIngressReady(ctx, ingressCli, name, config.PollInterval, remaining, true, checker)
+}
+
+// watchIngressReady waits for the ingress to be ready, as reported by the checker,
+// with a watch of it, so that its status updates are reacted to immediately.
+func watchIngressReady(ctx context.Context, ingressCli IngressClient, name string, timeout time.Duration, checker ReadinessChecker) (*networkingv1.Ingress, error) {
+	timer := getClock().NewTimer(timeout)
+	defer timer.Stop()
+
+	watcher, ok := ingressCli.(ingressWatcher)
+	if !ok {
+		return nil, &ingressWatchError{Err: errors.New("the ingress client doesn't support watches")}
+	}
+	w, err := watcher.Watch(ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()})
+	if err != nil {
+		return nil, &ingressWatchError{Err: err}
+	}
+	defer w.Stop()
+
+	// The ingress may have been programmed before the watch started.
+	ing, err := ingressCli.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if ready, ok := readyIngress(checker, ing); ok {
+		return ready, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-timer.C():
+			return nil, context.DeadlineExceeded
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil, &ingressWatchError{Err: errors.New("the watch was closed")}
+			}
+			switch event.Type {
+			case watch.Error:
+				return nil, &ingressWatchError{Err: k8serrors.FromObject(event.Object)}
+			case watch.Deleted:
+				return nil, k8serrors.NewNotFound(networkingv1.Resource("ingresses"), name)
+			case watch.Added, watch.Modified:
+				ing, ok := event.Object.(*networkingv1.Ingress)
+				if !ok {
+					continue
+				}
+				if ready, ok := readyIngress(checker, ing); ok {
+					return ready, nil
+				}
+			}
+		}
+	}
+}
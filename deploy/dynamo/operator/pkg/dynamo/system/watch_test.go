@@ -0,0 +1,125 @@
+/*
+ * SPDX-FileCopyrightText: Copyright (c) 2025 NVIDIA CORPORATION & AFFILIATES. All rights reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ai-dynamo/dynamo/deploy/dynamo/operator/pkg/dynamo/consts"
+)
+
+func TestGetIngressIPWatch(t *testing.T) {
+	// The poll interval outlasts the test timeout, so that only the watch can
+	// pick up the status.
+	configMap := newNetworkConfigMap(map[string]string{
+		consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryWatch:        "true",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "1h",
+		consts.KubeConfigMapKeyNetworkConfigDiscoveryWaitTimeout:  "2h",
+	})
+	cliset := newLoadBalancerClientset()
+	created := make(chan *networkingv1.Ingress, 1)
+	cliset.PrependReactor("create", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created <- action.(k8stesting.CreateAction).GetObject().(*networkingv1.Ingress).DeepCopy()
+		return false, nil, nil
+	})
+	watcher := watch.NewFake()
+	cliset.PrependWatchReactor("ingresses", k8stesting.DefaultWatchReactor(watcher, nil))
+	var gets int
+	cliset.PrependReactor("get", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+
+	go func() {
+		ing := <-created
+		ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.4"}}
+		watcher.Modify(ing)
+	}()
+
+	ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+	if err != nil {
+		t.Fatalf("GetIngressIP() error = %v", err)
+	}
+	if ip != "10.0.0.4" {
+		t.Errorf("GetIngressIP() = %q, want 10.0.0.4", ip)
+	}
+	if gets != 1 {
+		t.Errorf("the ingress was fetched %d times, want only once before the watch events", gets)
+	}
+}
+
+func TestGetIngressIPWatchFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		reactor k8stesting.WatchReactionFunc
+	}{
+		{
+			name: "watch error",
+			reactor: func(action k8stesting.Action) (bool, watch.Interface, error) {
+				return true, nil, errors.New("watch not allowed")
+			},
+		},
+		{
+			name: "watch closed",
+			reactor: func(action k8stesting.Action) (bool, watch.Interface, error) {
+				watcher := watch.NewFake()
+				watcher.Stop()
+				return true, watcher, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := newNetworkConfigMap(map[string]string{
+				consts.KubeConfigMapKeyNetworkConfigIngressClass:          "nginx",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryWatch:        "true",
+				consts.KubeConfigMapKeyNetworkConfigDiscoveryPollInterval: "10ms",
+			})
+			cliset := newLoadBalancerClientset()
+			cliset.PrependWatchReactor("ingresses", tt.reactor)
+			var gets int
+			cliset.PrependReactor("get", "ingresses", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				gets++
+				if gets < 3 {
+					return false, nil, nil
+				}
+				ing := &networkingv1.Ingress{}
+				ing.Name = action.(k8stesting.GetAction).GetName()
+				ing.Namespace = action.GetNamespace()
+				ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.5"}}
+				return true, ing, nil
+			})
+
+			ip, err := GetIngressIP(context.Background(), staticConfigMapGetter(configMap), cliset)
+			if err != nil {
+				t.Fatalf("GetIngressIP() error = %v", err)
+			}
+			if ip != "10.0.0.5" {
+				t.Errorf("GetIngressIP() = %q, want 10.0.0.5 from the polls", ip)
+			}
+		})
+	}
+}